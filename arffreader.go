@@ -0,0 +1,568 @@
+// Copyright 2017 M. Shulhan <ms@kilabit.info>. All rights reserved.
+// Use of this source code is governed by a BSD-style license that can be found
+// in the LICENSE file.
+
+package tabula
+
+import (
+	"bufio"
+	"errors"
+	"io"
+	"strconv"
+	"strings"
+	"time"
+)
+
+const (
+	// arffDefaultDateFormat is the format of ARFF date attribute when its
+	// not defined.
+	arffDefaultDateFormat = "yyyy-MM-dd'T'HH:mm:ss"
+)
+
+var (
+	// ErrInvalidARFF returned when input is not a valid ARFF.
+	ErrInvalidARFF = errors.New("tabula: invalid ARFF format")
+	// ErrNotInValueSpace returned when the value is not exist in column
+	// value space.
+	ErrNotInValueSpace = errors.New("tabula: value is not in value space")
+)
+
+//
+// ARFFReader read ARFF (Attribute-Relation File Format), the file format used
+// by Weka, and load it into claset.
+//
+// Each attribute is mapped into column with the same name. Type "numeric" and
+// "real" is mapped to TReal, "integer" to TInteger, "date" to TTime, and the
+// rest to TString. The date format, in Java SimpleDateFormat pattern, is
+// converted into column time format, with "yyyy-MM-dd'T'HH:mm:ss" as
+// default.
+// Nominal attribute values is saved in column value space.
+// Value "?" is converted to missing value based on the column type.
+//
+// Sparse data, where each line is in format "{idx value, ...}", is also
+// supported. Value that is not defined in sparse line is set to zero, or the
+// first value in value space for nominal attribute, or empty string for
+// string attribute.
+//
+type ARFFReader struct {
+	// Relation contain the name of relation after reading the input.
+	Relation string
+	// ClassName is the name of class attribute. If its empty, the last
+	// attribute is used as class.
+	ClassName string
+}
+
+//
+// NewARFFReader create and return new ARFF reader.
+//
+func NewARFFReader() *ARFFReader {
+	return &ARFFReader{}
+}
+
+//
+// ReadFile open ARFF file and read all of its data into `claset`.
+//
+func (reader *ARFFReader) ReadFile(file string, claset ClasetInterface) (
+	e error,
+) {
+	f, e := OpenFile(file)
+	if e != nil {
+		return e
+	}
+
+	e = reader.Read(f, claset)
+
+	errClose := f.Close()
+	if e == nil {
+		e = errClose
+	}
+
+	return
+}
+
+//
+// Read ARFF header and data from `r` into `claset`. The claset columns will
+// be replaced with attributes from header, and the mode is not changed.
+//
+func (reader *ARFFReader) Read(r io.Reader, claset ClasetInterface) (
+	e error,
+) {
+	scanner := bufio.NewScanner(r)
+	scanner.Buffer(nil, 1024*1024*1024)
+
+	var cols Columns
+	var line string
+	n := 0
+	inData := false
+
+	for scanner.Scan() {
+		n++
+		line = strings.TrimSpace(scanner.Text())
+		if len(line) == 0 || line[0] == '%' {
+			continue
+		}
+
+		if inData {
+			e = reader.pushData(claset, cols, line, n)
+			if e != nil {
+				return
+			}
+			continue
+		}
+
+		if line[0] != '@' {
+			return &ReadError{Line: n, Err: ErrInvalidARFF}
+		}
+
+		keyword, rest := arffCutSpace(line)
+
+		switch strings.ToLower(keyword) {
+		case "@relation":
+			reader.Relation, e = arffUnquote(rest)
+		case "@attribute":
+			var col *Column
+			col, e = newARFFColumn(rest)
+			if e == nil {
+				cols = append(cols, *col)
+			}
+		case "@data":
+			inData = true
+			e = reader.initClaset(claset, cols)
+		default:
+			e = ErrInvalidARFF
+		}
+		if e != nil {
+			return &ReadError{Line: n, Value: line, Err: e}
+		}
+	}
+
+	return scanner.Err()
+}
+
+//
+// initClaset initialize claset with columns from ARFF attributes and set
+// the class index.
+//
+func (reader *ARFFReader) initClaset(claset ClasetInterface, cols Columns) (
+	e error,
+) {
+	if len(cols) == 0 {
+		return ErrInvalidARFF
+	}
+
+	types := make([]int, len(cols))
+	names := make([]string, len(cols))
+	classIdx := len(cols) - 1
+
+	for x, col := range cols {
+		types[x] = col.Type
+		names[x] = col.Name
+		if reader.ClassName != "" && col.Name == reader.ClassName {
+			classIdx = x
+		}
+	}
+
+	claset.Init(claset.GetMode(), types, names)
+
+	dscols := claset.GetColumns()
+	for x := range *dscols {
+		(*dscols)[x].ValueSpace = cols[x].ValueSpace
+		(*dscols)[x].TimeFormat = cols[x].TimeFormat
+	}
+
+	claset.SetClassIndex(classIdx)
+
+	return nil
+}
+
+//
+// pushData convert data in `line` into row and push it to claset.
+//
+func (reader *ARFFReader) pushData(claset ClasetInterface, cols Columns,
+	line string, n int,
+) (e error) {
+	var fields []string
+	sparse := line[0] == '{'
+
+	if sparse {
+		if line[len(line)-1] != '}' {
+			return &ReadError{Line: n, Value: line, Err: ErrInvalidARFF}
+		}
+		line = line[1 : len(line)-1]
+	}
+
+	fields, e = arffSplit(line)
+	if e != nil {
+		return &ReadError{Line: n, Value: line, Err: e}
+	}
+
+	row := make(Row, len(cols))
+
+	if !sparse {
+		if len(fields) != len(cols) {
+			return &ReadError{Line: n, Err: ErrMisColLength}
+		}
+		for x, field := range fields {
+			row[x], e = newARFFRecord(&cols[x], field)
+			if e != nil {
+				return &ReadError{
+					Line:   n,
+					Column: x + 1,
+					Value:  field,
+					Err:    e,
+				}
+			}
+		}
+		claset.PushRow(&row)
+		return nil
+	}
+
+	for _, field := range fields {
+		if len(field) == 0 {
+			continue
+		}
+
+		sidx, value := arffCutSpace(field)
+
+		x, e := strconv.Atoi(sidx)
+		if e != nil || x < 0 || x >= len(cols) {
+			return &ReadError{Line: n, Value: field, Err: ErrColIdxOutOfRange}
+		}
+
+		row[x], e = newARFFRecord(&cols[x], value)
+		if e != nil {
+			return &ReadError{
+				Line:   n,
+				Column: x + 1,
+				Value:  value,
+				Err:    e,
+			}
+		}
+	}
+
+	for x := range row {
+		if row[x] != nil {
+			continue
+		}
+		row[x] = newARFFZeroRecord(&cols[x])
+	}
+
+	claset.PushRow(&row)
+
+	return nil
+}
+
+//
+// newARFFColumn parse the attribute declaration, without the "@attribute"
+// keyword, and create new column from it.
+//
+func newARFFColumn(decl string) (col *Column, e error) {
+	var name string
+
+	if len(decl) > 0 && (decl[0] == '\'' || decl[0] == '"') {
+		end := arffQuoteEnd(decl)
+		if end < 0 {
+			return nil, ErrUnclosedQuote
+		}
+		name, e = arffUnquote(decl[:end+1])
+		if e != nil {
+			return nil, e
+		}
+		decl = strings.TrimSpace(decl[end+1:])
+	} else {
+		name, decl = arffCutSpace(decl)
+	}
+
+	if len(name) == 0 || len(decl) == 0 {
+		return nil, ErrInvalidARFF
+	}
+
+	if decl[0] == '{' {
+		end := strings.LastIndexByte(decl, '}')
+		if end < 0 {
+			return nil, ErrInvalidARFF
+		}
+
+		values, e := arffSplit(decl[1:end])
+		if e != nil {
+			return nil, e
+		}
+
+		col = NewColumn(TString, name)
+		for _, v := range values {
+			v, e = arffUnquote(v)
+			if e != nil {
+				return nil, e
+			}
+			col.ValueSpace = append(col.ValueSpace, v)
+		}
+		return col, nil
+	}
+
+	tipe, format := arffCutSpace(decl)
+
+	switch strings.ToLower(tipe) {
+	case "numeric", "real":
+		col = NewColumn(TReal, name)
+	case "integer":
+		col = NewColumn(TInteger, name)
+	case "string":
+		col = NewColumn(TString, name)
+	case "date":
+		if format == "" {
+			format = arffDefaultDateFormat
+		} else {
+			format, e = arffUnquote(format)
+			if e != nil {
+				return nil, e
+			}
+		}
+		col = NewColumn(TTime, name)
+		col.TimeFormat = &TimeFormat{
+			Layouts: []string{arffDateLayout(format)},
+		}
+	default:
+		return nil, ErrInvalidARFF
+	}
+
+	return col, nil
+}
+
+//
+// newARFFRecord create new record from ARFF value `v` based on column type.
+//
+func newARFFRecord(col *Column, v string) (rec *Record, e error) {
+	rec = NewRecord()
+
+	if v == "?" {
+		rec.SetMissingValue(col.Type)
+		return rec, nil
+	}
+
+	v, e = arffUnquote(v)
+	if e != nil {
+		return nil, e
+	}
+
+	if len(col.ValueSpace) > 0 {
+		found := false
+		for _, vs := range col.ValueSpace {
+			if v == vs {
+				found = true
+				break
+			}
+		}
+		if !found {
+			return nil, ErrNotInValueSpace
+		}
+	}
+
+	if col.Type == TTime {
+		e = rec.SetTimeValue(v, col.TimeFormat)
+	} else {
+		e = rec.SetValue(v, col.Type)
+	}
+	if e != nil {
+		return nil, e
+	}
+
+	return rec, nil
+}
+
+//
+// arffDateLayout convert Java SimpleDateFormat pattern, which is used by
+// ARFF date attribute, into Go time layout. Text inside single quotes is
+// copied as is, and unknown pattern letter is copied as literal.
+//
+func arffDateLayout(format string) string {
+	var layout []byte
+
+	for x := 0; x < len(format); {
+		c := format[x]
+
+		if c == '\'' {
+			end := strings.IndexByte(format[x+1:], '\'')
+			if end < 0 {
+				layout = append(layout, format[x+1:]...)
+				break
+			}
+			if end == 0 {
+				layout = append(layout, '\'')
+			} else {
+				layout = append(layout, format[x+1:x+1+end]...)
+			}
+			x += end + 2
+			continue
+		}
+
+		n := 1
+		for x+n < len(format) && format[x+n] == c {
+			n++
+		}
+		x += n
+
+		switch c {
+		case 'y':
+			if n == 2 {
+				layout = append(layout, "06"...)
+			} else {
+				layout = append(layout, "2006"...)
+			}
+		case 'M':
+			switch {
+			case n >= 4:
+				layout = append(layout, "January"...)
+			case n == 3:
+				layout = append(layout, "Jan"...)
+			default:
+				layout = append(layout, "01"...)
+			}
+		case 'd':
+			layout = append(layout, "02"...)
+		case 'H':
+			layout = append(layout, "15"...)
+		case 'h':
+			layout = append(layout, "03"...)
+		case 'm':
+			layout = append(layout, "04"...)
+		case 's':
+			layout = append(layout, "05"...)
+		case 'S':
+			layout = append(layout, strings.Repeat("0", n)...)
+		case 'a':
+			layout = append(layout, "PM"...)
+		case 'E':
+			if n >= 4 {
+				layout = append(layout, "Monday"...)
+			} else {
+				layout = append(layout, "Mon"...)
+			}
+		case 'z':
+			layout = append(layout, "MST"...)
+		case 'Z':
+			layout = append(layout, "-0700"...)
+		case 'X':
+			layout = append(layout, "Z07:00"...)
+		default:
+			layout = append(layout, strings.Repeat(string(c), n)...)
+		}
+	}
+
+	return string(layout)
+}
+
+//
+// newARFFZeroRecord create new record for value that is not defined in sparse
+// data.
+//
+func newARFFZeroRecord(col *Column) (rec *Record) {
+	switch col.Type {
+	case TInteger:
+		return NewRecordInt(0)
+	case TReal:
+		return NewRecordReal(0)
+	case TBool:
+		return NewRecordBool(false)
+	case TTime:
+		return NewRecordTime(time.Unix(0, 0).UTC())
+	case TDecimal:
+		return NewRecordDecimal(Decimal{Scale: col.decimalScale()})
+	}
+	if len(col.ValueSpace) > 0 {
+		return NewRecordString(col.ValueSpace[0])
+	}
+	return NewRecordString("")
+}
+
+//
+// arffCutSpace split `s` into the first word and the rest of string, both
+// without leading and trailing spaces.
+//
+func arffCutSpace(s string) (word, rest string) {
+	s = strings.TrimSpace(s)
+
+	x := strings.IndexAny(s, " \t")
+	if x < 0 {
+		return s, ""
+	}
+
+	return s[:x], strings.TrimSpace(s[x+1:])
+}
+
+//
+// arffQuoteEnd return the index of closing quote in `s`, where `s` is started
+// with quote, or -1 if no closing quote found.
+//
+func arffQuoteEnd(s string) int {
+	for x := 1; x < len(s); x++ {
+		switch s[x] {
+		case '\\':
+			x++
+		case s[0]:
+			return x
+		}
+	}
+	return -1
+}
+
+//
+// arffSplit split the comma separated values in `s`, without removing the
+// quote in each value.
+//
+func arffSplit(s string) (values []string, e error) {
+	start := 0
+
+	for x := 0; x < len(s); x++ {
+		switch s[x] {
+		case '\'', '"':
+			end := arffQuoteEnd(s[x:])
+			if end < 0 {
+				return nil, ErrUnclosedQuote
+			}
+			x += end
+		case ',':
+			values = append(values, strings.TrimSpace(s[start:x]))
+			start = x + 1
+		}
+	}
+
+	values = append(values, strings.TrimSpace(s[start:]))
+
+	return values, nil
+}
+
+//
+// arffUnquote remove the quote in `s` and unescape the special characters
+// inside it. If `s` is not quoted, it will return `s` as is.
+//
+func arffUnquote(s string) (string, error) {
+	if len(s) == 0 || (s[0] != '\'' && s[0] != '"') {
+		return s, nil
+	}
+
+	end := arffQuoteEnd(s)
+	if end < 0 {
+		return "", ErrUnclosedQuote
+	}
+
+	var out []byte
+	for x := 1; x < end; x++ {
+		if s[x] != '\\' {
+			out = append(out, s[x])
+			continue
+		}
+		x++
+		switch s[x] {
+		case 'n':
+			out = append(out, '\n')
+		case 'r':
+			out = append(out, '\r')
+		case 't':
+			out = append(out, '\t')
+		default:
+			out = append(out, s[x])
+		}
+	}
+
+	return string(out), nil
+}
@@ -0,0 +1,140 @@
+// Copyright 2017 M. Shulhan <ms@kilabit.info>. All rights reserved.
+// Use of this source code is governed by a BSD-style license that can be found
+// in the LICENSE file.
+
+package tabula_test
+
+import (
+	"bytes"
+	"fmt"
+	"github.com/shuLhan/tabula"
+	"strings"
+	"testing"
+)
+
+func TestARFFReaderReadFile(t *testing.T) {
+	claset := tabula.NewClaset(tabula.DatasetModeMatrix, nil, nil)
+	reader := tabula.NewARFFReader()
+
+	e := reader.ReadFile("testdata/weather.arff", claset)
+	if e != nil {
+		t.Fatal(e)
+	}
+
+	assert(t, "weather", reader.Relation, true)
+	assert(t, 4, claset.GetClassIndex(), true)
+	assert(t, []string{"outlook", "temperature", "humidity", "wind note",
+		"play"}, claset.GetColumnsName(), true)
+	assert(t, []int{tabula.TString, tabula.TInteger, tabula.TReal,
+		tabula.TString, tabula.TString}, claset.GetColumnsType(), true)
+	assert(t, []string{"sunny", "overcast", "light rain"},
+		claset.Columns[0].ValueSpace, true)
+	assert(t, []string{"yes", "no"}, claset.GetClassValueSpace(), true)
+
+	exp := "&[sunny 85 85.5 it's windy no]" +
+		"&[overcast -9223372036854775808 86 calm yes]" +
+		"&[light rain 70 -Inf ? yes]"
+	got := fmt.Sprint(claset.Rows)
+
+	assert(t, exp, got, true)
+	assert(t, true, claset.Rows[1].GetRecord(1).IsMissingValue(), true)
+	assert(t, true, claset.Rows[2].GetRecord(2).IsMissingValue(), true)
+}
+
+func TestARFFReaderSparse(t *testing.T) {
+	claset := tabula.NewClaset(tabula.DatasetModeRows, nil, nil)
+
+	e := tabula.NewARFFReader().ReadFile("testdata/sparse.arff", claset)
+	if e != nil {
+		t.Fatal(e)
+	}
+
+	exp := "&[1.5 0  B]&[0 -2 x y A]&[0 0  A]"
+	got := fmt.Sprint(claset.Rows)
+
+	assert(t, exp, got, true)
+}
+
+func TestARFFReaderDate(t *testing.T) {
+	input := `@relation log
+@attribute at date "dd/MM/yyyy HH:mm"
+@attribute day date
+@attribute class {a,b}
+@data
+'31/12/2017 23:59',2017-12-31T00:00:00,a
+?,?,b
+`
+	claset := tabula.NewClaset(tabula.DatasetModeRows, nil, nil)
+
+	e := tabula.NewARFFReader().Read(strings.NewReader(input), claset)
+	if e != nil {
+		t.Fatal(e)
+	}
+
+	assert(t, []int{tabula.TTime, tabula.TTime, tabula.TString},
+		claset.GetColumnsType(), true)
+	assert(t, []string{"02/01/2006 15:04"},
+		claset.Columns[0].TimeFormat.Layouts, true)
+
+	exp := "&[2017-12-31T23:59:00Z 2017-12-31T00:00:00Z a]&[? ? b]"
+
+	assert(t, exp, claset.GetDataAsRows().String(), true)
+
+	var out bytes.Buffer
+
+	e = tabula.NewARFFWriter().Write(&out, claset)
+	if e != nil {
+		t.Fatal(e)
+	}
+
+	assert(t, true, strings.Contains(out.String(),
+		"@attribute at date\n"), true)
+	assert(t, true, strings.Contains(out.String(),
+		"2017-12-31T23:59:00,2017-12-31T00:00:00,a\n?,?,b\n"), true)
+}
+
+func TestARFFReaderError(t *testing.T) {
+	inputs := []string{
+		"@relation x\n@attribute a {p,q}\n@data\np\nr\n",
+		"@relation x\n@attribute a integer\n@data\n1.5\n",
+		"@relation x\n@attribute a relational\n@data\n",
+		"@relation x\n@attribute a integer\n@data\n{1 2}\n",
+	}
+	exps := []tabula.ReadError{{
+		Line:   5,
+		Column: 1,
+		Value:  "r",
+		Err:    tabula.ErrNotInValueSpace,
+	}, {
+		Line:   4,
+		Column: 1,
+		Value:  "1.5",
+	}, {
+		Line:  2,
+		Value: "@attribute a relational",
+		Err:   tabula.ErrInvalidARFF,
+	}, {
+		Line:  4,
+		Value: "1 2",
+		Err:   tabula.ErrColIdxOutOfRange,
+	}}
+
+	for x, input := range inputs {
+		claset := tabula.NewClaset(tabula.DatasetModeRows, nil, nil)
+
+		e := tabula.NewARFFReader().Read(bytes.NewBufferString(input),
+			claset)
+
+		got, ok := e.(*tabula.ReadError)
+		if !ok {
+			t.Fatalf("expecting ReadError, got %v", e)
+		}
+
+		assert(t, exps[x].Line, got.Line, true)
+		assert(t, exps[x].Column, got.Column, true)
+		assert(t, exps[x].Value, got.Value, true)
+		if exps[x].Err != nil {
+			assert(t, exps[x].Err, got.Err, true)
+		}
+	}
+}
@@ -0,0 +1,247 @@
+// Copyright 2017 M. Shulhan <ms@kilabit.info>. All rights reserved.
+// Use of this source code is governed by a BSD-style license that can be found
+// in the LICENSE file.
+
+package tabula
+
+import (
+	"bufio"
+	"io"
+	"strconv"
+	"strings"
+)
+
+const (
+	// arffTimeLayout is the Go layout of default ARFF date format.
+	arffTimeLayout = "2006-01-02T15:04:05"
+)
+
+//
+// ARFFWriter write claset into ARFF (Attribute-Relation File Format), the
+// file format used by Weka.
+//
+// Column with value space is written as nominal attribute, TInteger as
+// "integer", TReal as "real", TDecimal as "numeric" with all digits after
+// decimal point, TBool as nominal "{false,true}", TTime as "date" with
+// default format "yyyy-MM-dd'T'HH:mm:ss" in UTC, and TString as "string".
+// Missing value is written as "?".
+//
+// ARFF does not have a way to mark the class attribute, Weka use the last
+// attribute as class by default. If class index is not the last column, use
+// ARFFReader.ClassName to read it back.
+//
+type ARFFWriter struct {
+	// Relation is the name of relation. Default to "tabula".
+	Relation string
+	// Sparse if its true, data will be written in sparse format, where
+	// zero value, empty string, and the first nominal value is not
+	// written.
+	Sparse bool
+}
+
+//
+// NewARFFWriter create and return new ARFF writer.
+//
+func NewARFFWriter() *ARFFWriter {
+	return &ARFFWriter{
+		Relation: "tabula",
+	}
+}
+
+//
+// WriteFile create or truncate `file` and write `claset` into it.
+//
+func (writer *ARFFWriter) WriteFile(file string, claset ClasetInterface) (
+	e error,
+) {
+	f, e := CreateFile(file)
+	if e != nil {
+		return e
+	}
+
+	e = writer.Write(f, claset)
+
+	errClose := f.Close()
+	if e == nil {
+		e = errClose
+	}
+
+	return
+}
+
+//
+// Write the ARFF header and data from `claset` into `w`.
+//
+func (writer *ARFFWriter) Write(w io.Writer, claset ClasetInterface) (
+	e error,
+) {
+	bw := bufio.NewWriter(w)
+	cols := claset.GetColumns()
+
+	relation := writer.Relation
+	if relation == "" {
+		relation = "tabula"
+	}
+
+	_, e = bw.WriteString("@relation " + arffQuote(relation) + "\n\n")
+	if e != nil {
+		return
+	}
+
+	for _, col := range *cols {
+		_, e = bw.WriteString("@attribute " + arffQuote(col.Name) +
+			" " + arffAttributeType(&col) + "\n")
+		if e != nil {
+			return
+		}
+	}
+
+	_, e = bw.WriteString("\n@data\n")
+	if e != nil {
+		return
+	}
+
+	var line []byte
+	nrow := claset.Len()
+
+	for r := 0; r < nrow; r++ {
+		line = line[:0]
+
+		if writer.Sparse {
+			line = writer.appendSparse(line, claset, r)
+		} else {
+			for x := range *cols {
+				if x > 0 {
+					line = append(line, ',')
+				}
+				line = appendARFFValue(line, getRecordAt(claset, r, x))
+			}
+		}
+		line = append(line, '\n')
+
+		_, e = bw.Write(line)
+		if e != nil {
+			return
+		}
+	}
+
+	return bw.Flush()
+}
+
+//
+// appendSparse append the row `r` in sparse format into `line`.
+//
+func (writer *ARFFWriter) appendSparse(line []byte, claset ClasetInterface,
+	r int,
+) []byte {
+	cols := claset.GetColumns()
+	n := 0
+
+	line = append(line, '{')
+	for x := range *cols {
+		rec := getRecordAt(claset, r, x)
+		if isARFFZeroRecord(&(*cols)[x], rec) {
+			continue
+		}
+		if n > 0 {
+			line = append(line, ',')
+		}
+		line = strconv.AppendInt(line, int64(x), 10)
+		line = append(line, ' ')
+		line = appendARFFValue(line, rec)
+		n++
+	}
+
+	return append(line, '}')
+}
+
+//
+// isARFFZeroRecord return true if record value is equal to value that will
+// be used by reader when value is not defined in sparse data.
+//
+func isARFFZeroRecord(col *Column, rec *Record) bool {
+	if rec == nil || rec.IsNil() || rec.IsMissingValue() {
+		return false
+	}
+	return rec.IsEqual(newARFFZeroRecord(col))
+}
+
+//
+// arffAttributeType return the ARFF type of column.
+//
+func arffAttributeType(col *Column) string {
+	if len(col.ValueSpace) > 0 {
+		values := make([]string, len(col.ValueSpace))
+		for x, v := range col.ValueSpace {
+			values[x] = arffQuote(v)
+		}
+		return "{" + strings.Join(values, ",") + "}"
+	}
+
+	switch col.Type {
+	case TInteger:
+		return "integer"
+	case TReal:
+		return "real"
+	case TDecimal:
+		return "numeric"
+	case TBool:
+		return "{false,true}"
+	case TTime:
+		return "date"
+	}
+	return "string"
+}
+
+//
+// appendARFFValue convert record to ARFF value and append it to `line`.
+//
+func appendARFFValue(line []byte, rec *Record) []byte {
+	if rec == nil || rec.IsNil() || rec.IsMissingValue() {
+		return append(line, '?')
+	}
+
+	switch rec.Type() {
+	case TInteger:
+		return strconv.AppendInt(line, rec.Integer(), 10)
+	case TReal:
+		return strconv.AppendFloat(line, rec.Float(), 'f', -1, 64)
+	case TTime:
+		return rec.Time().UTC().AppendFormat(line, arffTimeLayout)
+	case TDecimal:
+		return append(line, rec.String()...)
+	}
+
+	return append(line, arffQuote(rec.String())...)
+}
+
+//
+// arffQuote enclose `s` with single quote if its empty or contain special
+// characters.
+//
+func arffQuote(s string) string {
+	if len(s) > 0 && !strings.ContainsAny(s, " \t\r\n,'\"\\%{}?") {
+		return s
+	}
+
+	var out []byte
+
+	out = append(out, '\'')
+	for x := 0; x < len(s); x++ {
+		switch s[x] {
+		case '\'', '\\':
+			out = append(out, '\\', s[x])
+		case '\n':
+			out = append(out, '\\', 'n')
+		case '\r':
+			out = append(out, '\\', 'r')
+		case '\t':
+			out = append(out, '\\', 't')
+		default:
+			out = append(out, s[x])
+		}
+	}
+	out = append(out, '\'')
+
+	return string(out)
+}
@@ -0,0 +1,127 @@
+// Copyright 2017 M. Shulhan <ms@kilabit.info>. All rights reserved.
+// Use of this source code is governed by a BSD-style license that can be found
+// in the LICENSE file.
+
+package tabula_test
+
+import (
+	"bytes"
+	"github.com/shuLhan/tabula"
+	"testing"
+)
+
+func TestARFFWriterRoundTrip(t *testing.T) {
+	files := []string{"testdata/weather.arff", "testdata/sparse.arff"}
+
+	for _, file := range files {
+		for _, sparse := range []bool{false, true} {
+			claset := tabula.NewClaset(tabula.DatasetModeColumns,
+				nil, nil)
+
+			e := tabula.NewARFFReader().ReadFile(file, claset)
+			if e != nil {
+				t.Fatal(e)
+			}
+
+			writer := tabula.NewARFFWriter()
+			writer.Sparse = sparse
+
+			var out bytes.Buffer
+
+			e = writer.Write(&out, claset)
+			if e != nil {
+				t.Fatal(e)
+			}
+
+			got := tabula.NewClaset(tabula.DatasetModeColumns, nil,
+				nil)
+
+			e = tabula.NewARFFReader().Read(&out, got)
+			if e != nil {
+				t.Fatal(e)
+			}
+
+			assert(t, claset.Columns, got.Columns, true)
+			assert(t, claset.ClassIndex, got.ClassIndex, true)
+		}
+	}
+}
+
+func TestARFFWriterWrite(t *testing.T) {
+	claset := tabula.NewClaset(tabula.DatasetModeRows, nil, nil)
+
+	e := tabula.NewARFFReader().ReadFile("testdata/sparse.arff", claset)
+	if e != nil {
+		t.Fatal(e)
+	}
+
+	writer := tabula.NewARFFWriter()
+	writer.Relation = "sparse data"
+	writer.Sparse = true
+
+	var out bytes.Buffer
+
+	e = writer.Write(&out, claset)
+	if e != nil {
+		t.Fatal(e)
+	}
+
+	exp := `@relation 'sparse data'
+
+@attribute a0 real
+@attribute a1 integer
+@attribute a2 string
+@attribute class {A,B}
+
+@data
+{0 1.5,3 B}
+{1 -2,2 'x y'}
+{}
+`
+	assert(t, exp, out.String(), true)
+}
+
+func TestARFFWriterDecimal(t *testing.T) {
+	claset := tabula.NewClaset(tabula.DatasetModeRows,
+		[]int{tabula.TDecimal, tabula.TString}, []string{"amount", "class"})
+	claset.Columns[0].Scale = 2
+	claset.SetClassIndex(1)
+
+	missing := tabula.NewRecord()
+	missing.SetMissingValue(tabula.TDecimal)
+
+	claset.PushRow(&tabula.Row{
+		tabula.NewRecordDecimal(tabula.Decimal{Value: 1250, Scale: 2}),
+		tabula.NewRecordString("a"),
+	})
+	claset.PushRow(&tabula.Row{
+		tabula.NewRecordDecimal(tabula.Decimal{Scale: 2}),
+		tabula.NewRecordString("b"),
+	})
+	claset.PushRow(&tabula.Row{missing, tabula.NewRecordString("a")})
+
+	exps := []string{
+		"@relation tabula\n\n" +
+			"@attribute amount numeric\n" +
+			"@attribute class string\n\n" +
+			"@data\n12.50,a\n0.00,b\n?,a\n",
+		"@relation tabula\n\n" +
+			"@attribute amount numeric\n" +
+			"@attribute class string\n\n" +
+			"@data\n{0 12.50,1 a}\n{1 b}\n{0 ?,1 a}\n",
+	}
+
+	for x, sparse := range []bool{false, true} {
+		writer := tabula.NewARFFWriter()
+		writer.Sparse = sparse
+
+		var out bytes.Buffer
+
+		e := writer.Write(&out, claset)
+		if e != nil {
+			t.Fatal(e)
+		}
+
+		assert(t, exps[x], out.String(), true)
+	}
+}
@@ -0,0 +1,1053 @@
+// Copyright 2017 M. Shulhan <ms@kilabit.info>. All rights reserved.
+// Use of this source code is governed by a BSD-style license that can be found
+// in the LICENSE file.
+
+package tabula
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/binary"
+	"errors"
+	"io"
+	"math"
+	"strconv"
+	"time"
+)
+
+//
+// Apache Arrow IPC format, as defined in
+// https://arrow.apache.org/docs/format/Columnar.html#serialization-and-interprocess-communication-ipc
+//
+// Column with type TInteger is written as Int64 array, TReal as Float64
+// array, TBool as Bool array, TTime as Timestamp array in microseconds with
+// UTC time zone, TDecimal as Decimal128 array with the column scale, and the
+// rest as Utf8 array. Missing value and nil record is written
+// as null in validity bitmap.
+//
+// Column flag is saved in field custom metadata with key "tabula:flag", and
+// class index of claset is saved in schema custom metadata with key
+// "tabula:class_index".
+//
+const (
+	arrowMagic             = "ARROW1"
+	arrowContinuation      = 0xFFFFFFFF
+	arrowMetadataV5        = 4
+	arrowHeaderSchema      = 1
+	arrowHeaderRecordBatch = 3
+	arrowTypeInt           = 2
+	arrowTypeFloat         = 3
+	arrowTypeBinary        = 4
+	arrowTypeUtf8          = 5
+	arrowTypeBool          = 6
+	arrowTypeDecimal       = 7
+	arrowTypeDate          = 8
+	arrowTypeTimestamp     = 10
+	arrowTypeLargeBinary   = 19
+	arrowTypeLargeUtf8     = 20
+	arrowPrecisionSingle   = 1
+	arrowPrecisionDouble   = 2
+	arrowDateDay           = 0
+	arrowUnitSecond        = 0
+	arrowUnitMillisecond   = 1
+	arrowUnitMicrosecond   = 2
+	arrowUnitNanosecond    = 3
+	arrowDecimalBitWidth   = 128
+	arrowKeyFlag           = "tabula:flag"
+	arrowKeyClassIndex     = "tabula:class_index"
+)
+
+var (
+	// ErrInvalidArrow returned when input is not a valid Arrow IPC
+	// stream or file.
+	ErrInvalidArrow = errors.New("tabula: invalid Arrow format")
+	// ErrArrowUnsupported returned when Arrow input contain data type or
+	// feature that is not supported, for example nested type, dictionary,
+	// or compressed body.
+	ErrArrowUnsupported = errors.New("tabula: unsupported Arrow data")
+)
+
+//
+// ArrowWriter write dataset into Apache Arrow IPC stream or file format.
+//
+type ArrowWriter struct {
+	// File if its true, the dataset is written using IPC file format,
+	// otherwise using IPC stream format.
+	File bool
+	// BatchSize is the maximum number of rows in each record batch. If
+	// its zero or negative, all rows is written in single record batch.
+	BatchSize int
+}
+
+//
+// NewArrowWriter create and return new Arrow writer.
+//
+func NewArrowWriter() *ArrowWriter {
+	return &ArrowWriter{}
+}
+
+//
+// arrowStream wrap writer to count the number of bytes written, and save
+// the position of each record batch for IPC file footer.
+//
+type arrowStream struct {
+	bw     *bufio.Writer
+	n      int64
+	blocks []byte
+	nblock int
+}
+
+func (stream *arrowStream) write(b []byte) (e error) {
+	_, e = stream.bw.Write(b)
+	stream.n += int64(len(b))
+	return
+}
+
+//
+// writeMessage write the encapsulated message, which contain continuation
+// marker, metadata length, metadata, and body.
+//
+func (stream *arrowStream) writeMessage(meta, body []byte) (e error) {
+	var prefix [8]byte
+
+	binary.LittleEndian.PutUint32(prefix[:], arrowContinuation)
+	binary.LittleEndian.PutUint32(prefix[4:], uint32(len(meta)))
+
+	e = stream.write(prefix[:])
+	if e != nil {
+		return
+	}
+	e = stream.write(meta)
+	if e != nil {
+		return
+	}
+	return stream.write(body)
+}
+
+//
+// arrowBody contain the record batch body and its metadata.
+//
+type arrowBody struct {
+	data    []byte
+	nodes   []byte
+	buffers []byte
+	nnode   int
+	nbuffer int
+}
+
+//
+// addNode add field node with `length` values and `nullCount` nulls.
+//
+func (body *arrowBody) addNode(length, nullCount int) {
+	var b [16]byte
+
+	binary.LittleEndian.PutUint64(b[:], uint64(length))
+	binary.LittleEndian.PutUint64(b[8:], uint64(nullCount))
+
+	body.nodes = append(body.nodes, b[:]...)
+	body.nnode++
+}
+
+//
+// addBuffer append `buf` to body, padded to multiple of 8 bytes.
+//
+func (body *arrowBody) addBuffer(buf []byte) {
+	var b [16]byte
+
+	binary.LittleEndian.PutUint64(b[:], uint64(len(body.data)))
+	binary.LittleEndian.PutUint64(b[8:], uint64(len(buf)))
+
+	body.buffers = append(body.buffers, b[:]...)
+	body.nbuffer++
+
+	body.data = append(body.data, buf...)
+	for len(body.data)%8 != 0 {
+		body.data = append(body.data, 0)
+	}
+}
+
+//
+// WriteFile create or truncate `file` and write dataset `ds` into it.
+//
+func (writer *ArrowWriter) WriteFile(file string, ds DatasetInterface) (
+	e error,
+) {
+	f, e := CreateFile(file)
+	if e != nil {
+		return e
+	}
+
+	e = writer.Write(f, ds)
+
+	errClose := f.Close()
+	if e == nil {
+		e = errClose
+	}
+
+	return
+}
+
+//
+// Write the schema and rows of dataset `ds` into `w`. Dataset is read based
+// on its mode without transposing.
+//
+func (writer *ArrowWriter) Write(w io.Writer, ds DatasetInterface) (e error) {
+	stream := &arrowStream{
+		bw: bufio.NewWriter(w),
+	}
+
+	if writer.File {
+		e = stream.write([]byte(arrowMagic + "\x00\x00"))
+		if e != nil {
+			return
+		}
+	}
+
+	schema := newArrowSchema(ds)
+
+	e = stream.writeMessage(newArrowMessage(arrowHeaderSchema, schema, 0),
+		nil)
+	if e != nil {
+		return
+	}
+
+	nrow := ds.Len()
+	size := writer.BatchSize
+	if size <= 0 || size > nrow {
+		size = nrow
+	}
+
+	for start := 0; start < nrow || start == 0; start += size {
+		end := start + size
+		if end > nrow {
+			end = nrow
+		}
+
+		e = writer.writeBatch(stream, ds, start, end)
+		if e != nil {
+			return
+		}
+
+		if size == 0 {
+			break
+		}
+	}
+
+	// End of stream marker.
+	var eos [8]byte
+	binary.LittleEndian.PutUint32(eos[:], arrowContinuation)
+
+	e = stream.write(eos[:])
+	if e != nil {
+		return
+	}
+
+	if writer.File {
+		e = writer.writeFooter(stream, schema)
+		if e != nil {
+			return
+		}
+	}
+
+	return stream.bw.Flush()
+}
+
+//
+// writeBatch write rows from `start` until `end` as one record batch.
+//
+func (writer *ArrowWriter) writeBatch(stream *arrowStream,
+	ds DatasetInterface, start, end int,
+) (e error) {
+	body := &arrowBody{}
+	types := ds.GetColumnsType()
+	cols := *ds.GetColumns()
+	n := end - start
+
+	for x, tipe := range types {
+		validity := make([]byte, (n+7)/8)
+		nnull := 0
+
+		var values, offsets []byte
+		switch tipe {
+		case TInteger, TReal, TTime, TDecimal:
+		case TBool:
+			values = make([]byte, (n+7)/8)
+		default:
+			offsets = make([]byte, 4, 4*(n+1))
+		}
+
+		for r := start; r < end; r++ {
+			rec := getRecordAt(ds, r, x)
+			isNull := rec == nil || rec.IsNil() || rec.IsMissingValue()
+			if isNull {
+				nnull++
+			} else {
+				i := r - start
+				validity[i/8] |= 1 << uint(i%8)
+			}
+
+			switch tipe {
+			case TInteger:
+				var v int64
+				if !isNull {
+					v = rec.Integer()
+				}
+				values = appendUint64(values, uint64(v))
+			case TReal:
+				var v float64
+				if !isNull {
+					v = rec.Float()
+				}
+				values = appendUint64(values, math.Float64bits(v))
+			case TBool:
+				if !isNull && rec.Bool() {
+					i := r - start
+					values[i/8] |= 1 << uint(i%8)
+				}
+			case TTime:
+				var v int64
+				if !isNull {
+					t := rec.Time()
+					v = t.Unix()*1e6 + int64(t.Nanosecond()/1e3)
+				}
+				values = appendUint64(values, uint64(v))
+			case TDecimal:
+				values, e = appendArrowDecimal(values, rec, isNull,
+					cols[x].decimalScale())
+				if e != nil {
+					return e
+				}
+			default:
+				if !isNull {
+					values = append(values, rec.String()...)
+				}
+				var b [4]byte
+				binary.LittleEndian.PutUint32(b[:], uint32(len(values)))
+				offsets = append(offsets, b[:]...)
+			}
+		}
+
+		body.addNode(n, nnull)
+		if nnull > 0 {
+			body.addBuffer(validity)
+		} else {
+			body.addBuffer(nil)
+		}
+		if offsets != nil {
+			body.addBuffer(offsets)
+		}
+		body.addBuffer(values)
+	}
+
+	batch := fbTable{
+		fbScalar(8, uint64(n)),
+		fbChild(fbStructs{n: body.nnode, align: 8, data: body.nodes}),
+		fbChild(fbStructs{n: body.nbuffer, align: 8, data: body.buffers}),
+	}
+
+	meta := newArrowMessage(arrowHeaderRecordBatch, batch, len(body.data))
+
+	var block [24]byte
+	binary.LittleEndian.PutUint64(block[:], uint64(stream.n))
+	binary.LittleEndian.PutUint32(block[8:], uint32(8+len(meta)))
+	binary.LittleEndian.PutUint64(block[16:], uint64(len(body.data)))
+	stream.blocks = append(stream.blocks, block[:]...)
+	stream.nblock++
+
+	return stream.writeMessage(meta, body.data)
+}
+
+//
+// writeFooter write the IPC file footer, which contain the schema and the
+// position of each record batch, followed by footer length and magic.
+//
+func (writer *ArrowWriter) writeFooter(stream *arrowStream, schema fbTable) (
+	e error,
+) {
+	footer := fbFinish(fbTable{
+		fbScalar(2, arrowMetadataV5),
+		fbChild(schema),
+		fbChild(fbStructs{align: 8}),
+		fbChild(fbStructs{
+			n:     stream.nblock,
+			align: 8,
+			data:  stream.blocks,
+		}),
+	})
+
+	var size [4]byte
+	binary.LittleEndian.PutUint32(size[:], uint32(len(footer)))
+
+	footer = append(footer, size[:]...)
+	footer = append(footer, arrowMagic...)
+
+	return stream.write(footer)
+}
+
+//
+// appendArrowDecimal append the decimal value of record, rescaled to
+// `scale`, into `b` as 16 bytes little endian two's complement. Null is
+// appended as zero. Record that can not be converted to decimal will return
+// ErrInvalidDecimal.
+//
+func appendArrowDecimal(b []byte, rec *Record, isNull bool, scale int) (
+	[]byte, error,
+) {
+	var v int64
+	if !isNull {
+		d, e := rec.Decimal().Rescale(scale)
+		if e != nil {
+			return b, e
+		}
+		if d.IsMissing() {
+			return b, ErrInvalidDecimal
+		}
+		v = d.Value
+	}
+
+	b = appendUint64(b, uint64(v))
+
+	// The high 64 bits is the sign extension of the value.
+	return appendUint64(b, uint64(v>>63)), nil
+}
+
+//
+// appendUint64 append `v` as 8 bytes little endian into `b`.
+//
+func appendUint64(b []byte, v uint64) []byte {
+	var buf [8]byte
+	binary.LittleEndian.PutUint64(buf[:], v)
+	return append(b, buf[:]...)
+}
+
+//
+// newArrowMessage create and encode the Message table with header `header`.
+//
+func newArrowMessage(headerType int, header fbTable, bodyLength int) []byte {
+	return fbFinish(fbTable{
+		fbScalar(2, arrowMetadataV5),
+		fbScalar(1, uint64(headerType)),
+		fbChild(header),
+		fbScalar(8, uint64(bodyLength)),
+	})
+}
+
+//
+// newArrowKeyValue create KeyValue table for custom metadata.
+//
+func newArrowKeyValue(key, value string) fbTable {
+	return fbTable{
+		fbChild(fbString(key)),
+		fbChild(fbString(value)),
+	}
+}
+
+//
+// newArrowSchema create Schema table from dataset columns.
+//
+func newArrowSchema(ds DatasetInterface) fbTable {
+	cols := ds.GetColumns()
+	fields := make(fbTables, len(*cols))
+
+	for x, col := range *cols {
+		var typeID uint64
+		var typ fbTable
+
+		switch col.Type {
+		case TInteger:
+			typeID = arrowTypeInt
+			typ = fbTable{fbScalar(4, 64), fbBool(true)}
+		case TReal:
+			typeID = arrowTypeFloat
+			typ = fbTable{fbScalar(2, arrowPrecisionDouble)}
+		case TBool:
+			typeID = arrowTypeBool
+			typ = fbTable{}
+		case TTime:
+			typeID = arrowTypeTimestamp
+			typ = fbTable{
+				fbScalar(2, arrowUnitMicrosecond),
+				fbChild(fbString("UTC")),
+			}
+		case TDecimal:
+			typeID = arrowTypeDecimal
+			typ = fbTable{
+				fbScalar(4, sqlDecimalPrecision),
+				fbScalar(4, uint64(col.decimalScale())),
+				fbScalar(4, arrowDecimalBitWidth),
+			}
+		default:
+			typeID = arrowTypeUtf8
+			typ = fbTable{}
+		}
+
+		fields[x] = fbTable{
+			fbChild(fbString(col.Name)),
+			fbBool(true),
+			fbScalar(1, typeID),
+			fbChild(typ),
+			{},
+			fbChild(fbTables{}),
+		}
+
+		if col.Flag != 0 {
+			fields[x] = append(fields[x], fbChild(fbTables{
+				newArrowKeyValue(arrowKeyFlag,
+					strconv.Itoa(col.Flag)),
+			}))
+		}
+	}
+
+	schema := fbTable{
+		{},
+		fbChild(fields),
+	}
+
+	if claset, ok := ds.(ClasetInterface); ok {
+		schema = append(schema, fbChild(fbTables{
+			newArrowKeyValue(arrowKeyClassIndex,
+				strconv.Itoa(claset.GetClassIndex())),
+		}))
+	}
+
+	return schema
+}
+
+//
+// arrowField contain the field information from Arrow schema.
+//
+type arrowField struct {
+	Column
+	typeID    int
+	bitWidth  int
+	signed    bool
+	precision int
+	unit      int
+}
+
+//
+// ReadArrowFile open Arrow IPC stream or file and read all of its rows into
+// dataset `ds`.
+//
+func ReadArrowFile(file string, ds DatasetInterface) (e error) {
+	f, e := OpenFile(file)
+	if e != nil {
+		return e
+	}
+
+	e = ReadArrow(f, ds)
+
+	errClose := f.Close()
+	if e == nil {
+		e = errClose
+	}
+
+	return
+}
+
+//
+// ReadArrow read Arrow IPC stream or file from `r` into dataset `ds`, using
+// the dataset mode. The dataset columns will be replaced with fields from
+// schema.
+//
+// Int, FloatingPoint, Bool, Decimal128, Date, Timestamp, Utf8, and Binary
+// arrays is supported. Integer array is read as TInteger, floating point as
+// TReal, boolean as TBool, Decimal128 as TDecimal with its scale, date and
+// timestamp as TTime in UTC, and the rest as TString. Decimal value that is
+// out of range of Decimal will return ErrDecimalOverflow. Null value is set
+// to null record based on the column type, see NewRecordNull.
+//
+// If `ds` is a claset and the class index is saved in schema, the class
+// index will be set.
+//
+func ReadArrow(r io.Reader, ds DatasetInterface) (e error) {
+	br := bufio.NewReader(r)
+
+	magic, e := br.Peek(len(arrowMagic))
+	if e == nil && string(magic) == arrowMagic {
+		stream, e := readArrowFileStream(br)
+		if e != nil {
+			return e
+		}
+		br = bufio.NewReader(bytes.NewReader(stream))
+	}
+
+	var fields []arrowField
+	var cols Columns
+	classIdx := -1
+
+	for {
+		meta, body, e := readArrowMessage(br)
+		if e != nil {
+			return e
+		}
+		if meta == nil {
+			break
+		}
+
+		fr := &fbReader{
+			buf:        meta,
+			errInvalid: ErrInvalidArrow,
+		}
+
+		msg := fr.root()
+		header := fr.child(msg, 2)
+
+		switch fr.scalar(msg, 1, 1, 0) {
+		case arrowHeaderSchema:
+			if fields != nil {
+				return ErrInvalidArrow
+			}
+			fields, classIdx, e = parseArrowSchema(fr, header)
+			if e != nil {
+				return e
+			}
+			cols = make(Columns, len(fields))
+			for x := range fields {
+				cols[x] = fields[x].Column
+			}
+		case arrowHeaderRecordBatch:
+			if fields == nil {
+				return ErrInvalidArrow
+			}
+			e = parseArrowBatch(fr, header, body, fields, cols)
+			if e != nil {
+				return e
+			}
+		default:
+			return ErrArrowUnsupported
+		}
+	}
+
+	if fields == nil {
+		return ErrInvalidArrow
+	}
+
+	setDatasetColumns(ds, cols)
+
+	if claset, ok := ds.(ClasetInterface); ok && classIdx >= 0 {
+		claset.SetClassIndex(classIdx)
+	}
+
+	return nil
+}
+
+//
+// readArrowFileStream read all of IPC file and return the messages between
+// the leading magic and the footer. The end of stream marker is optional in
+// IPC file, so the footer length is used to find the end of messages.
+//
+func readArrowFileStream(r io.Reader) (stream []byte, e error) {
+	data, e := io.ReadAll(r)
+	if e != nil {
+		return nil, e
+	}
+
+	// Leading magic with padding, footer length, and trailing magic.
+	n := len(data) - 4 - len(arrowMagic)
+	if n < len(arrowMagic)+2 ||
+		string(data[n+4:]) != arrowMagic {
+		return nil, ErrInvalidArrow
+	}
+
+	end := int64(n) - int64(binary.LittleEndian.Uint32(data[n:]))
+	if end < int64(len(arrowMagic)+2) {
+		return nil, ErrInvalidArrow
+	}
+
+	return data[len(arrowMagic)+2 : end], nil
+}
+
+//
+// readArrowMessage read the encapsulated message metadata and its body. At
+// the end of stream, it will return nil metadata.
+//
+func readArrowMessage(br *bufio.Reader) (meta, body []byte, e error) {
+	var prefix [4]byte
+
+	_, e = io.ReadFull(br, prefix[:])
+	if e == io.EOF {
+		return nil, nil, nil
+	}
+	if e != nil {
+		return nil, nil, ErrInvalidArrow
+	}
+
+	size := binary.LittleEndian.Uint32(prefix[:])
+	if size == arrowContinuation {
+		_, e = io.ReadFull(br, prefix[:])
+		if e != nil {
+			return nil, nil, ErrInvalidArrow
+		}
+		size = binary.LittleEndian.Uint32(prefix[:])
+	}
+	if size == 0 {
+		return nil, nil, nil
+	}
+	if size > math.MaxInt32 {
+		return nil, nil, ErrInvalidArrow
+	}
+
+	meta = make([]byte, size)
+
+	_, e = io.ReadFull(br, meta)
+	if e != nil {
+		return nil, nil, ErrInvalidArrow
+	}
+
+	fr := &fbReader{
+		buf:        meta,
+		errInvalid: ErrInvalidArrow,
+	}
+
+	bodyLength := int64(fr.scalar(fr.root(), 3, 8, 0))
+	if fr.e != nil {
+		return nil, nil, fr.e
+	}
+	if bodyLength < 0 || bodyLength > math.MaxInt32 {
+		return nil, nil, ErrInvalidArrow
+	}
+
+	body = make([]byte, bodyLength)
+
+	_, e = io.ReadFull(br, body)
+	if e != nil {
+		return nil, nil, ErrInvalidArrow
+	}
+
+	return meta, body, nil
+}
+
+//
+// parseArrowKeyValues parse the custom metadata in field `id` of table at
+// `tbl`.
+//
+func parseArrowKeyValues(fr *fbReader, tbl, id int) map[string]string {
+	start, n := fr.vector(tbl, id)
+	if n == 0 {
+		return nil
+	}
+
+	kv := make(map[string]string, n)
+	for x := 0; x < n; x++ {
+		pos := fr.indirect(start + 4*x)
+		kv[fr.string(pos, 0)] = fr.string(pos, 1)
+	}
+
+	return kv
+}
+
+//
+// parseArrowSchema parse the fields and class index from Schema table.
+//
+func parseArrowSchema(fr *fbReader, schema int) (fields []arrowField,
+	classIdx int, e error,
+) {
+	// Only little endian is supported.
+	if fr.scalar(schema, 0, 2, 0) != 0 {
+		return nil, -1, ErrArrowUnsupported
+	}
+
+	start, n := fr.vector(schema, 1)
+	fields = make([]arrowField, n)
+
+	for x := 0; x < n; x++ {
+		pos := fr.indirect(start + 4*x)
+		field := &fields[x]
+
+		field.Name = fr.string(pos, 0)
+		field.typeID = int(fr.scalar(pos, 2, 1, 0))
+		typ := fr.child(pos, 3)
+
+		switch field.typeID {
+		case arrowTypeInt:
+			field.Type = TInteger
+			field.bitWidth = int(int32(fr.scalar(typ, 0, 4, 0)))
+			field.signed = fr.scalar(typ, 1, 1, 0) != 0
+			switch field.bitWidth {
+			case 8, 16, 32, 64:
+			default:
+				return nil, -1, ErrArrowUnsupported
+			}
+		case arrowTypeFloat:
+			field.Type = TReal
+			field.precision = int(fr.scalar(typ, 0, 2, 0))
+			if field.precision != arrowPrecisionSingle &&
+				field.precision != arrowPrecisionDouble {
+				return nil, -1, ErrArrowUnsupported
+			}
+		case arrowTypeBool:
+			field.Type = TBool
+		case arrowTypeDecimal:
+			field.Type = TDecimal
+			field.Scale = int(int32(fr.scalar(typ, 1, 4, 0)))
+			field.bitWidth = int(int32(fr.scalar(typ, 2, 4,
+				arrowDecimalBitWidth)))
+			if field.bitWidth != arrowDecimalBitWidth ||
+				field.Scale < 0 || field.Scale > DecimalMaxScale {
+				return nil, -1, ErrArrowUnsupported
+			}
+		case arrowTypeDate:
+			field.Type = TTime
+			field.unit = int(fr.scalar(typ, 0, 2, arrowUnitMillisecond))
+		case arrowTypeTimestamp:
+			field.Type = TTime
+			field.unit = int(fr.scalar(typ, 0, 2, 0))
+			if field.unit > arrowUnitNanosecond {
+				return nil, -1, ErrArrowUnsupported
+			}
+		case arrowTypeBinary, arrowTypeUtf8, arrowTypeLargeBinary,
+			arrowTypeLargeUtf8:
+			field.Type = TString
+		default:
+			return nil, -1, ErrArrowUnsupported
+		}
+
+		// Dictionary encoded field is not supported.
+		if fr.field(pos, 4) != 0 {
+			return nil, -1, ErrArrowUnsupported
+		}
+
+		kv := parseArrowKeyValues(fr, pos, 6)
+		if v, ok := kv[arrowKeyFlag]; ok {
+			field.Flag, e = strconv.Atoi(v)
+			if e != nil {
+				return nil, -1, ErrInvalidArrow
+			}
+		}
+	}
+
+	classIdx = -1
+	kv := parseArrowKeyValues(fr, schema, 2)
+	if v, ok := kv[arrowKeyClassIndex]; ok {
+		classIdx, e = strconv.Atoi(v)
+		if e != nil || classIdx >= n {
+			return nil, -1, ErrInvalidArrow
+		}
+	}
+
+	if fr.e != nil {
+		return nil, -1, fr.e
+	}
+
+	return fields, classIdx, nil
+}
+
+//
+// parseArrowBatch parse the RecordBatch table and its body, and append the
+// records into columns.
+//
+func parseArrowBatch(fr *fbReader, batch int, body []byte,
+	fields []arrowField, cols Columns,
+) (e error) {
+	// Compressed body is not supported.
+	if fr.field(batch, 3) != 0 {
+		return ErrArrowUnsupported
+	}
+
+	length := int(int64(fr.scalar(batch, 0, 8, 0)))
+	nodes, nnode := fr.vector(batch, 1)
+	buffers, nbuffer := fr.vector(batch, 2)
+
+	if fr.e != nil {
+		return fr.e
+	}
+	if length < 0 || nnode != len(fields) {
+		return ErrInvalidArrow
+	}
+
+	getBuffer := func(idx int) []byte {
+		if idx >= nbuffer {
+			fr.e = ErrInvalidArrow
+			return nil
+		}
+		pos := buffers + 16*idx
+		off := int64(fr.u64(pos))
+		size := int64(fr.u64(pos + 8))
+		if off < 0 || size < 0 || off+size > int64(len(body)) {
+			fr.e = ErrInvalidArrow
+			return nil
+		}
+		return body[off : off+size]
+	}
+
+	ibuf := 0
+
+	for x := range fields {
+		field := &fields[x]
+		nnull := int64(fr.u64(nodes + 16*x + 8))
+
+		validity := getBuffer(ibuf)
+		ibuf++
+		if nnull == 0 {
+			validity = nil
+		} else if len(validity) < (length+7)/8 {
+			return ErrInvalidArrow
+		}
+
+		var offsets []byte
+		if field.Type == TString {
+			offsets = getBuffer(ibuf)
+			ibuf++
+		}
+		values := getBuffer(ibuf)
+		ibuf++
+
+		if fr.e != nil {
+			return fr.e
+		}
+
+		recs, e := newArrowRecords(field, length, validity, offsets,
+			values)
+		if e != nil {
+			return e
+		}
+
+		cols[x].Records = append(cols[x].Records, recs...)
+	}
+
+	return nil
+}
+
+//
+// newArrowRecords convert the array buffers into records.
+//
+func newArrowRecords(field *arrowField, length int, validity, offsets,
+	values []byte,
+) (recs Records, e error) {
+	width := 0
+	large := field.typeID == arrowTypeLargeBinary ||
+		field.typeID == arrowTypeLargeUtf8
+
+	switch field.Type {
+	case TInteger:
+		width = field.bitWidth / 8
+	case TReal:
+		width = 8
+		if field.precision == arrowPrecisionSingle {
+			width = 4
+		}
+	case TBool:
+		if len(values) < (length+7)/8 {
+			return nil, ErrInvalidArrow
+		}
+	case TTime:
+		width = 8
+		if field.typeID == arrowTypeDate && field.unit == arrowDateDay {
+			width = 4
+		}
+	case TDecimal:
+		width = field.bitWidth / 8
+	default:
+		width = 4
+		if large {
+			width = 8
+		}
+		if len(offsets) < width*(length+1) {
+			return nil, ErrInvalidArrow
+		}
+	}
+
+	if (field.Type == TInteger || field.Type == TReal ||
+		field.Type == TTime || field.Type == TDecimal) &&
+		len(values) < width*length {
+		return nil, ErrInvalidArrow
+	}
+
+	recs = make(Records, length)
+
+	for r := 0; r < length; r++ {
+		if validity != nil && validity[r/8]&(1<<uint(r%8)) == 0 {
+			recs[r] = NewRecordNull(field.Type)
+			continue
+		}
+
+		switch field.Type {
+		case TInteger:
+			recs[r] = NewRecordInt(arrowInt(values[r*width:], width,
+				field.signed))
+		case TReal:
+			var v float64
+			if width == 4 {
+				bits := binary.LittleEndian.Uint32(values[r*width:])
+				v = float64(math.Float32frombits(bits))
+			} else {
+				bits := binary.LittleEndian.Uint64(values[r*width:])
+				v = math.Float64frombits(bits)
+			}
+			recs[r] = NewRecordReal(v)
+		case TBool:
+			recs[r] = NewRecordBool(values[r/8]&(1<<uint(r%8)) != 0)
+		case TTime:
+			v := arrowInt(values[r*width:], width, true)
+			recs[r] = NewRecordTime(arrowTime(field, v))
+		case TDecimal:
+			v := int64(binary.LittleEndian.Uint64(values[r*width:]))
+			hi := int64(binary.LittleEndian.Uint64(values[r*width+8:]))
+			if hi != v>>63 || v == math.MinInt64 {
+				return nil, ErrDecimalOverflow
+			}
+			recs[r] = NewRecordDecimal(Decimal{
+				Value: v,
+				Scale: field.Scale,
+			})
+		default:
+			start := arrowInt(offsets[r*width:], width, true)
+			end := arrowInt(offsets[(r+1)*width:], width, true)
+			if start < 0 || start > end || end > int64(len(values)) {
+				return nil, ErrInvalidArrow
+			}
+			recs[r] = NewRecordString(string(values[start:end]))
+		}
+	}
+
+	return recs, nil
+}
+
+//
+// arrowInt read integer with `width` bytes in little endian from `b`.
+//
+func arrowInt(b []byte, width int, signed bool) int64 {
+	switch width {
+	case 1:
+		if signed {
+			return int64(int8(b[0]))
+		}
+		return int64(b[0])
+	case 2:
+		v := binary.LittleEndian.Uint16(b)
+		if signed {
+			return int64(int16(v))
+		}
+		return int64(v)
+	case 4:
+		v := binary.LittleEndian.Uint32(b)
+		if signed {
+			return int64(int32(v))
+		}
+		return int64(v)
+	}
+	return int64(binary.LittleEndian.Uint64(b))
+}
+
+//
+// arrowTime convert the value of date or timestamp array into time in UTC.
+//
+func arrowTime(field *arrowField, v int64) time.Time {
+	if field.typeID == arrowTypeDate {
+		if field.unit == arrowDateDay {
+			return time.Unix(v*86400, 0).UTC()
+		}
+		return time.Unix(v/1e3, v%1e3*1e6).UTC()
+	}
+
+	switch field.unit {
+	case arrowUnitSecond:
+		return time.Unix(v, 0).UTC()
+	case arrowUnitMillisecond:
+		return time.Unix(v/1e3, v%1e3*1e6).UTC()
+	case arrowUnitMicrosecond:
+		return time.Unix(v/1e6, v%1e6*1e3).UTC()
+	}
+	return time.Unix(0, v).UTC()
+}
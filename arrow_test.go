@@ -0,0 +1,383 @@
+// Copyright 2017 M. Shulhan <ms@kilabit.info>. All rights reserved.
+// Use of this source code is governed by a BSD-style license that can be found
+// in the LICENSE file.
+
+package tabula_test
+
+import (
+	"bytes"
+	"encoding/binary"
+	"github.com/shuLhan/tabula"
+	"io/ioutil"
+	"testing"
+	"time"
+)
+
+func createArrowClaset(t *testing.T) *tabula.Claset {
+	claset := tabula.NewClaset(tabula.DatasetModeRows, datasetTypes,
+		datasetNames)
+	claset.SetClassIndex(2)
+	claset.Columns[1].Flag = 3
+
+	e := populateWithRows(&claset.Dataset)
+	if e != nil {
+		t.Fatal(e)
+	}
+
+	null := tabula.Row{
+		tabula.NewRecordNull(tabula.TInteger),
+		tabula.NewRecordNull(tabula.TReal),
+		tabula.NewRecordNull(tabula.TString),
+	}
+	claset.PushRow(&null)
+
+	return claset
+}
+
+//
+// createArrowGolden create claset with the same schema and rows as in
+// "testdata/claset.arrow", which is written by IPC file writer of Apache
+// Arrow Go (github.com/apache/arrow/go/arrow@v0.0.0-20211112161151).
+//
+func createArrowGolden() *tabula.Claset {
+	types := []int{
+		tabula.TInteger, tabula.TReal, tabula.TString, tabula.TBool,
+		tabula.TTime, tabula.TDecimal,
+	}
+	names := []string{"id", "score", "name", "ok", "at", "amount"}
+
+	claset := tabula.NewClaset(tabula.DatasetModeRows, types, names)
+	claset.SetClassIndex(2)
+	claset.Columns[1].Flag = 3
+	claset.SetColumnsScale([]int{0, 0, 0, 0, 0, 2})
+
+	rows := []tabula.Row{{
+		tabula.NewRecordInt(1),
+		tabula.NewRecordReal(0.5),
+		tabula.NewRecordString("alpha"),
+		tabula.NewRecordBool(true),
+		tabula.NewRecordTime(time.Date(2017, 12, 31, 23, 59, 58,
+			123456000, time.UTC)),
+		tabula.NewRecordDecimal(tabula.Decimal{Value: 1250, Scale: 2}),
+	}, {
+		tabula.NewRecordInt(2),
+		tabula.NewRecordNull(tabula.TReal),
+		tabula.NewRecordString("beta"),
+		tabula.NewRecordBool(false),
+		tabula.NewRecordNull(tabula.TTime),
+		tabula.NewRecordDecimal(tabula.Decimal{Value: -50, Scale: 2}),
+	}, {
+		tabula.NewRecordNull(tabula.TInteger),
+		tabula.NewRecordReal(2.25),
+		tabula.NewRecordNull(tabula.TString),
+		tabula.NewRecordNull(tabula.TBool),
+		tabula.NewRecordTime(time.Date(1969, 7, 20, 20, 17, 0, 0,
+			time.UTC)),
+		tabula.NewRecordNull(tabula.TDecimal),
+	}, {
+		tabula.NewRecordInt(4),
+		tabula.NewRecordReal(-1),
+		tabula.NewRecordString("delta"),
+		tabula.NewRecordBool(true),
+		tabula.NewRecordTime(time.Unix(0, 0).UTC()),
+		tabula.NewRecordDecimal(tabula.Decimal{Value: 100000, Scale: 2}),
+	}}
+
+	for x := range rows {
+		claset.PushRow(&rows[x])
+	}
+
+	return claset
+}
+
+//
+// arrowBatchBody return the body of the only record batch in IPC file `in`,
+// which is started after the schema and record batch metadata, and ended
+// before the optional end of stream marker and the footer.
+//
+func arrowBatchBody(t *testing.T, in []byte) []byte {
+	pos := 8
+	for x := 0; x < 2; x++ {
+		if binary.LittleEndian.Uint32(in[pos:]) != 0xFFFFFFFF {
+			t.Fatalf("expecting continuation marker at %d", pos)
+		}
+		pos += 8 + int(binary.LittleEndian.Uint32(in[pos+4:]))
+	}
+
+	end := len(in) - 10 - int(binary.LittleEndian.Uint32(in[len(in)-10:]))
+
+	eos := []byte{0xFF, 0xFF, 0xFF, 0xFF, 0, 0, 0, 0}
+	if bytes.Equal(in[end-8:end], eos) {
+		end -= 8
+	}
+
+	return in[pos:end]
+}
+
+func TestArrowReadGolden(t *testing.T) {
+	exp := createArrowGolden()
+
+	modes := []int{
+		tabula.DatasetModeRows,
+		tabula.DatasetModeColumns,
+		tabula.DatasetModeMatrix,
+	}
+
+	for _, mode := range modes {
+		got := tabula.NewClaset(mode, nil, nil)
+
+		e := tabula.ReadArrowFile("testdata/claset.arrow", got)
+		if e != nil {
+			t.Fatal(e)
+		}
+
+		assert(t, exp.GetColumnsName(), got.GetColumnsName(), true)
+		assert(t, exp.GetColumnsType(), got.GetColumnsType(), true)
+		assert(t, 2, got.GetClassIndex(), true)
+		assert(t, 3, got.Columns[1].Flag, true)
+		assert(t, 2, got.Columns[5].Scale, true)
+		assert(t, exp.GetDataAsRows().String(),
+			got.GetDataAsRows().String(), true)
+
+		for x, rec := range *got.GetRow(2) {
+			if x == 1 || x == 4 {
+				continue
+			}
+			assert(t, true, rec.IsNull(), true)
+		}
+	}
+}
+
+func TestArrowWriteGolden(t *testing.T) {
+	golden, e := ioutil.ReadFile("testdata/claset.arrow")
+	if e != nil {
+		t.Fatal(e)
+	}
+
+	var out bytes.Buffer
+
+	e = (&tabula.ArrowWriter{File: true}).Write(&out, createArrowGolden())
+	if e != nil {
+		t.Fatal(e)
+	}
+
+	// The layout of FlatBuffers metadata depend on the encoder, so the
+	// record batch body is compared byte by byte, while the schema and
+	// buffers position is compared by reading it back.
+	assert(t, arrowBatchBody(t, golden), arrowBatchBody(t, out.Bytes()),
+		true)
+
+	exp := tabula.NewClaset(tabula.DatasetModeRows, nil, nil)
+	got := tabula.NewClaset(tabula.DatasetModeRows, nil, nil)
+
+	e = tabula.ReadArrow(bytes.NewReader(golden), exp)
+	if e != nil {
+		t.Fatal(e)
+	}
+
+	e = tabula.ReadArrow(&out, got)
+	if e != nil {
+		t.Fatal(e)
+	}
+
+	assert(t, exp.GetColumns(), got.GetColumns(), true)
+	assert(t, exp.GetClassIndex(), got.GetClassIndex(), true)
+}
+
+func TestArrowWriteRead(t *testing.T) {
+	claset := createArrowClaset(t)
+
+	writers := []*tabula.ArrowWriter{
+		{},
+		{File: true},
+		{BatchSize: 4},
+		{File: true, BatchSize: 1},
+	}
+	modes := []int{
+		tabula.DatasetModeRows,
+		tabula.DatasetModeColumns,
+		tabula.DatasetModeMatrix,
+	}
+
+	for _, writer := range writers {
+		var out bytes.Buffer
+
+		e := writer.Write(&out, claset)
+		if e != nil {
+			t.Fatal(e)
+		}
+
+		if writer.File {
+			assert(t, "ARROW1", string(out.Bytes()[:6]), true)
+			assert(t, "ARROW1", string(out.Bytes()[out.Len()-6:]),
+				true)
+		}
+
+		for _, mode := range modes {
+			got := tabula.NewClaset(mode, nil, nil)
+
+			e = tabula.ReadArrow(bytes.NewReader(out.Bytes()), got)
+			if e != nil {
+				t.Fatal(e)
+			}
+
+			assert(t, mode, got.GetMode(), true)
+			assert(t, datasetNames, got.GetColumnsName(), true)
+			assert(t, datasetTypes, got.GetColumnsType(), true)
+			assert(t, 2, got.GetClassIndex(), true)
+			assert(t, 3, got.Columns[1].Flag, true)
+			assert(t, claset.GetDataAsRows(), got.GetDataAsRows(),
+				true)
+		}
+	}
+}
+
+func TestArrowBool(t *testing.T) {
+	ds := tabula.NewDataset(tabula.DatasetModeRows, []int{tabula.TBool},
+		[]string{"ok"})
+
+	for _, v := range []string{"true", "false", "", "true"} {
+		rec := tabula.NewRecordNull(tabula.TBool)
+		if v != "" {
+			_ = rec.SetValue(v, tabula.TBool)
+		}
+		ds.PushRow(&tabula.Row{rec})
+	}
+
+	var out bytes.Buffer
+
+	e := tabula.NewArrowWriter().Write(&out, ds)
+	if e != nil {
+		t.Fatal(e)
+	}
+
+	got := tabula.NewDataset(tabula.DatasetModeRows, nil, nil)
+
+	e = tabula.ReadArrow(&out, got)
+	if e != nil {
+		t.Fatal(e)
+	}
+
+	assert(t, []int{tabula.TBool}, got.GetColumnsType(), true)
+	assert(t, ds.GetDataAsRows(), got.GetDataAsRows(), true)
+}
+
+func TestArrowTime(t *testing.T) {
+	ds := tabula.NewDataset(tabula.DatasetModeRows, []int{tabula.TTime},
+		[]string{"at"})
+
+	missing := tabula.NewRecord()
+	missing.SetMissingValue(tabula.TTime)
+
+	ds.PushRow(&tabula.Row{tabula.NewRecordTime(time.Date(2017, 12, 31,
+		23, 59, 58, 123456000, time.UTC))})
+	ds.PushRow(&tabula.Row{missing})
+	ds.PushRow(&tabula.Row{tabula.NewRecordTime(time.Date(1969, 7, 20,
+		20, 17, 0, 0, time.UTC))})
+
+	var out bytes.Buffer
+
+	e := tabula.NewArrowWriter().Write(&out, ds)
+	if e != nil {
+		t.Fatal(e)
+	}
+
+	got := tabula.NewDataset(tabula.DatasetModeRows, nil, nil)
+
+	e = tabula.ReadArrow(&out, got)
+	if e != nil {
+		t.Fatal(e)
+	}
+
+	assert(t, []int{tabula.TTime}, got.GetColumnsType(), true)
+	assert(t, "&[2017-12-31T23:59:58.123456Z]&[]&[1969-07-20T20:17:00Z]",
+		got.GetDataAsRows().String(), true)
+
+	// Missing value is written as null and read back as null.
+	assert(t, true, got.GetRow(1).GetRecord(0).IsNull(), true)
+	assert(t, tabula.TTime, got.GetRow(1).GetRecord(0).Type(), true)
+}
+
+func TestArrowDecimal(t *testing.T) {
+	ds := tabula.NewDataset(tabula.DatasetModeRows,
+		[]int{tabula.TDecimal}, []string{"amount"})
+	ds.Columns[0].Scale = 2
+
+	missing := tabula.NewRecord()
+	missing.SetMissingValue(tabula.TDecimal)
+
+	ds.PushRow(&tabula.Row{
+		tabula.NewRecordDecimal(tabula.Decimal{Value: 1250, Scale: 2}),
+	})
+	ds.PushRow(&tabula.Row{missing})
+	ds.PushRow(&tabula.Row{
+		tabula.NewRecordDecimal(tabula.Decimal{Value: -5, Scale: 1}),
+	})
+
+	var out bytes.Buffer
+
+	e := tabula.NewArrowWriter().Write(&out, ds)
+	if e != nil {
+		t.Fatal(e)
+	}
+
+	got := tabula.NewDataset(tabula.DatasetModeRows, nil, nil)
+
+	e = tabula.ReadArrow(&out, got)
+	if e != nil {
+		t.Fatal(e)
+	}
+
+	assert(t, []int{tabula.TDecimal}, got.GetColumnsType(), true)
+	assert(t, 2, got.Columns[0].Scale, true)
+	assert(t, "&[12.50]&[]&[-0.50]", got.GetDataAsRows().String(), true)
+	assert(t, true, got.GetRow(1).GetRecord(0).IsNull(), true)
+	assert(t, tabula.TDecimal, got.GetRow(1).GetRecord(0).Type(), true)
+}
+
+func TestArrowWriteEmpty(t *testing.T) {
+	ds := tabula.NewDataset(tabula.DatasetModeRows, datasetTypes,
+		datasetNames)
+
+	var out bytes.Buffer
+
+	e := tabula.NewArrowWriter().Write(&out, ds)
+	if e != nil {
+		t.Fatal(e)
+	}
+
+	got := tabula.NewDataset(tabula.DatasetModeRows, nil, nil)
+
+	e = tabula.ReadArrow(&out, got)
+	if e != nil {
+		t.Fatal(e)
+	}
+
+	assert(t, datasetNames, got.GetColumnsName(), true)
+	assert(t, 0, got.Len(), true)
+}
+
+func TestArrowReadInvalid(t *testing.T) {
+	claset := createArrowClaset(t)
+
+	var out bytes.Buffer
+
+	e := tabula.NewArrowWriter().Write(&out, claset)
+	if e != nil {
+		t.Fatal(e)
+	}
+
+	inputs := [][]byte{
+		nil,
+		[]byte("ARROW1"),
+		out.Bytes()[:out.Len()/2],
+		[]byte("\xff\xff\xff\xff\x10\x00\x00\x00garbage!garbage!"),
+	}
+
+	for _, in := range inputs {
+		ds := tabula.NewDataset(tabula.DatasetModeRows, nil, nil)
+
+		e = tabula.ReadArrow(bytes.NewReader(in), ds)
+		assert(t, tabula.ErrInvalidArrow, e, true)
+	}
+}
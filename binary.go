@@ -0,0 +1,136 @@
+// Copyright 2017 M. Shulhan <ms@kilabit.info>. All rights reserved.
+// Use of this source code is governed by a BSD-style license that can be found
+// in the LICENSE file.
+
+package tabula
+
+import (
+	"bytes"
+	"encoding/binary"
+	"errors"
+	"io"
+)
+
+var (
+	// ErrInvalidBinary returned when binary data can not be decoded.
+	ErrInvalidBinary = errors.New("tabula: invalid binary data")
+)
+
+//
+// appendUvarint encode unsigned integer `v` as uvarint and append it to `b`.
+//
+func appendUvarint(b []byte, v uint64) []byte {
+	var buf [binary.MaxVarintLen64]byte
+	n := binary.PutUvarint(buf[:], v)
+	return append(b, buf[:n]...)
+}
+
+//
+// appendVarint encode integer `v` as varint and append it to `b`.
+//
+func appendVarint(b []byte, v int64) []byte {
+	var buf [binary.MaxVarintLen64]byte
+	n := binary.PutVarint(buf[:], v)
+	return append(b, buf[:n]...)
+}
+
+//
+// appendBinaryString encode the length of `s` as uvarint followed by `s`,
+// and append it to `b`.
+//
+func appendBinaryString(b []byte, s string) []byte {
+	b = appendUvarint(b, uint64(len(s)))
+	return append(b, s...)
+}
+
+//
+// appendBinaryStrings encode the length of `list` as uvarint followed by
+// each string in list, and append it to `b`.
+//
+func appendBinaryStrings(b []byte, list []string) []byte {
+	b = appendUvarint(b, uint64(len(list)))
+	for _, s := range list {
+		b = appendBinaryString(b, s)
+	}
+	return b
+}
+
+//
+// readUvarint read uvarint from `br`. If its failed, it will return
+// `errInvalid`.
+//
+func readUvarint(br *bytes.Reader, errInvalid error) (uint64, error) {
+	v, e := binary.ReadUvarint(br)
+	if e != nil {
+		return 0, errInvalid
+	}
+	return v, nil
+}
+
+//
+// readVarint read varint from `br`. If its failed, it will return
+// `errInvalid`.
+//
+func readVarint(br *bytes.Reader, errInvalid error) (int64, error) {
+	v, e := binary.ReadVarint(br)
+	if e != nil {
+		return 0, errInvalid
+	}
+	return v, nil
+}
+
+//
+// readLength read uvarint from `br` that will be used as number of items,
+// where each item size is at least one byte. If the length is greater than
+// the rest of bytes, it will return `errInvalid`.
+//
+func readLength(br *bytes.Reader, errInvalid error) (int, error) {
+	l, e := binary.ReadUvarint(br)
+	if e != nil || l > uint64(br.Len()) {
+		return 0, errInvalid
+	}
+	return int(l), nil
+}
+
+//
+// readBinaryString read string that is encoded by appendBinaryString from
+// `br`. If its failed, it will return `errInvalid`.
+//
+func readBinaryString(br *bytes.Reader, errInvalid error) (string, error) {
+	l, e := readLength(br, errInvalid)
+	if e != nil {
+		return "", e
+	}
+
+	b := make([]byte, l)
+
+	_, e = io.ReadFull(br, b)
+	if e != nil {
+		return "", errInvalid
+	}
+
+	return string(b), nil
+}
+
+//
+// readBinaryStrings read list of string that is encoded by
+// appendBinaryStrings from `br`. If its failed, it will return `errInvalid`.
+//
+func readBinaryStrings(br *bytes.Reader, errInvalid error) (
+	list []string, e error,
+) {
+	n, e := readLength(br, errInvalid)
+	if e != nil {
+		return nil, e
+	}
+
+	for ; n > 0; n-- {
+		s, e := readBinaryString(br, errInvalid)
+		if e != nil {
+			return nil, e
+		}
+		list = append(list, s)
+	}
+
+	return list, nil
+}
@@ -0,0 +1,99 @@
+// Copyright 2017 M. Shulhan <ms@kilabit.info>. All rights reserved.
+// Use of this source code is governed by a BSD-style license that can be found
+// in the LICENSE file.
+
+package tabula
+
+// List of policy for value that is not in the value space of categorical
+// column.
+const (
+	// CategoryReject reject the value that is not in value space.
+	CategoryReject = iota
+	// CategoryExtend append the value that is not in value space into
+	// it.
+	CategoryExtend
+)
+
+//
+// categoryDict contain the value space of categorical column, the index of
+// each value, and the code of each value that is shared by all records with
+// the same value.
+//
+type categoryDict struct {
+	values []string
+	index  map[string]int
+	codes  []*categoryCode
+}
+
+//
+// categoryCode is the value of categorical record, which contain the index
+// of its value in dictionary.
+//
+type categoryCode struct {
+	code int
+	dict *categoryDict
+}
+
+//
+// newCategoryDict create new dictionary from copy of `values`. If value is
+// duplicated, the first index is used.
+//
+func newCategoryDict(values []string) (dict *categoryDict) {
+	dict = &categoryDict{
+		values: make([]string, 0, len(values)),
+		index:  make(map[string]int, len(values)),
+		codes:  make([]*categoryCode, 0, len(values)),
+	}
+
+	for _, v := range values {
+		dict.push(v)
+	}
+
+	return dict
+}
+
+//
+// push append value `v` into dictionary and return its code.
+//
+func (dict *categoryDict) push(v string) *categoryCode {
+	c := &categoryCode{
+		code: len(dict.values),
+		dict: dict,
+	}
+
+	if _, ok := dict.index[v]; !ok {
+		dict.index[v] = c.code
+	}
+	dict.values = append(dict.values, v)
+	dict.codes = append(dict.codes, c)
+
+	return c
+}
+
+//
+// lookup return the code of value `v`, or nil if its not exist.
+//
+func (dict *categoryDict) lookup(v string) *categoryCode {
+	idx, ok := dict.index[v]
+	if !ok {
+		return nil
+	}
+	return dict.codes[idx]
+}
+
+//
+// isValues return true if `values` is the same slice as dictionary values.
+//
+func (dict *categoryDict) isValues(values []string) bool {
+	if len(values) != len(dict.values) {
+		return false
+	}
+	return len(values) == 0 || &values[0] == &dict.values[0]
+}
+
+//
+// String return the value of code.
+//
+func (c *categoryCode) String() string {
+	return c.dict.values[c.code]
+}
@@ -5,6 +5,8 @@
 package tabula
 
 import (
+	"bytes"
+	"encoding/json"
 	"fmt"
 	"github.com/shuLhan/numerus"
 	"github.com/shuLhan/tekstus"
@@ -53,6 +55,14 @@ func (claset *Claset) Clone() interface{} {
 		major:      claset.MajorityClass(),
 		minor:      claset.MinorityClass(),
 	}
+	if claset.vs != nil {
+		clone.vs = make([]string, len(claset.vs))
+		copy(clone.vs, claset.vs)
+	}
+	if claset.counts != nil {
+		clone.counts = make([]int, len(claset.counts))
+		copy(clone.counts, claset.counts)
+	}
 	clone.SetDataset(claset.GetDataset().Clone().(DatasetInterface))
 	return &clone
 }
@@ -300,3 +310,177 @@ func (claset *Claset) String() (s string) {
 
 	return
 }
+
+//
+// clasetJSON define the JSON representation of claset.
+//
+type clasetJSON struct {
+	datasetJSON
+	ClassIndex int
+}
+
+//
+// MarshalJSON convert claset into JSON object, which is the same as dataset
+// JSON with additional class index.
+//
+func (claset *Claset) MarshalJSON() ([]byte, error) {
+	ds, e := claset.Dataset.toJSON()
+	if e != nil {
+		return nil, e
+	}
+
+	out := clasetJSON{
+		datasetJSON: *ds,
+		ClassIndex:  claset.ClassIndex,
+	}
+
+	return json.Marshal(&out)
+}
+
+//
+// UnmarshalJSON set the claset from JSON object. Class index is replaced
+// only if its exist in JSON.
+//
+func (claset *Claset) UnmarshalJSON(b []byte) (e error) {
+	in := clasetJSON{
+		datasetJSON: datasetJSON{
+			Mode: claset.Mode,
+		},
+		ClassIndex: claset.ClassIndex,
+	}
+
+	e = json.Unmarshal(b, &in)
+	if e != nil {
+		return e
+	}
+
+	claset.ClassIndex = in.ClassIndex
+
+	return claset.Dataset.fromJSON(&in.datasetJSON)
+}
+
+//
+// MarshalBinary convert claset into binary, which contain the dataset, class
+// index, and the class statistics: value space, counts, majority and
+// minority class.
+//
+func (claset *Claset) MarshalBinary() ([]byte, error) {
+	b := claset.Dataset.appendBinary(nil)
+
+	b = appendVarint(b, int64(claset.ClassIndex))
+	b = appendBinaryStrings(b, claset.vs)
+
+	b = appendUvarint(b, uint64(len(claset.counts)))
+	for _, c := range claset.counts {
+		b = appendVarint(b, int64(c))
+	}
+
+	b = appendBinaryString(b, claset.major)
+	b = appendBinaryString(b, claset.minor)
+
+	return b, nil
+}
+
+//
+// UnmarshalBinary set the claset from binary that is created by
+// MarshalBinary.
+//
+func (claset *Claset) UnmarshalBinary(b []byte) (e error) {
+	br := bytes.NewReader(b)
+
+	e = claset.Dataset.readBinary(br)
+	if e != nil {
+		return
+	}
+
+	classIdx, e := readVarint(br, ErrInvalidBinary)
+	if e != nil {
+		return
+	}
+	claset.ClassIndex = int(classIdx)
+
+	claset.vs, e = readBinaryStrings(br, ErrInvalidBinary)
+	if e != nil {
+		return
+	}
+
+	n, e := readLength(br, ErrInvalidBinary)
+	if e != nil {
+		return
+	}
+
+	claset.counts = nil
+	for ; n > 0; n-- {
+		c, e := readVarint(br, ErrInvalidBinary)
+		if e != nil {
+			return e
+		}
+		claset.counts = append(claset.counts, int(c))
+	}
+
+	claset.major, e = readBinaryString(br, ErrInvalidBinary)
+	if e != nil {
+		return
+	}
+	claset.minor, e = readBinaryString(br, ErrInvalidBinary)
+	if e != nil {
+		return
+	}
+
+	if br.Len() > 0 {
+		return ErrInvalidBinary
+	}
+
+	return nil
+}
+
+//
+// GobEncode implement the gob.GobEncoder interface, see MarshalBinary.
+//
+func (claset *Claset) GobEncode() ([]byte, error) {
+	return claset.MarshalBinary()
+}
+
+//
+// GobDecode implement the gob.GobDecoder interface, see UnmarshalBinary.
+//
+func (claset *Claset) GobDecode(b []byte) error {
+	return claset.UnmarshalBinary(b)
+}
+
+//
+// MarshalMsgpack convert claset into MessagePack map, which is the same as
+// dataset MessagePack with additional class index.
+//
+func (claset *Claset) MarshalMsgpack() ([]byte, error) {
+	b := claset.Dataset.appendMsgpack(nil, 1)
+	b = appendMsgpackString(b, "ClassIndex")
+	return appendMsgpackInt(b, int64(claset.ClassIndex)), nil
+}
+
+//
+// UnmarshalMsgpack set the claset from MessagePack map. Class index is
+// replaced only if its exist in map.
+//
+func (claset *Claset) UnmarshalMsgpack(b []byte) (e error) {
+	br := bytes.NewReader(b)
+
+	e = claset.Dataset.readMsgpack(br, func(key string) (e error) {
+		if key != "ClassIndex" {
+			return skipMsgpack(br)
+		}
+
+		classIdx, e := readMsgpackInt(br)
+		claset.ClassIndex = int(classIdx)
+
+		return e
+	})
+	if e != nil {
+		return
+	}
+	if br.Len() > 0 {
+		return ErrInvalidMsgpack
+	}
+
+	return nil
+}
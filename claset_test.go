@@ -0,0 +1,100 @@
+// Copyright 2017 M. Shulhan <ms@kilabit.info>. All rights reserved.
+// Use of this source code is governed by a BSD-style license that can be found
+// in the LICENSE file.
+
+package tabula_test
+
+import (
+	"bytes"
+	"encoding/gob"
+	"encoding/json"
+	"github.com/shuLhan/tabula"
+	"testing"
+)
+
+func TestClasetJSON(t *testing.T) {
+	claset := tabula.NewClaset(tabula.DatasetModeRows, testColTypes,
+		testColNames)
+	claset.SetClassIndex(testClassIdx)
+
+	rows, e := initRows()
+	if e != nil {
+		t.Fatal(e)
+	}
+	claset.SetRows(&rows)
+
+	b, e := json.Marshal(claset)
+	if e != nil {
+		t.Fatal(e)
+	}
+
+	exp := `{"Mode":1,"Columns":[` +
+		`{"Name":"int01","Type":1,"Flag":0,"ValueSpace":null},` +
+		`{"Name":"int02","Type":1,"Flag":0,"ValueSpace":null},` +
+		`{"Name":"int03","Type":1,"Flag":0,"ValueSpace":null},` +
+		`{"Name":"class","Type":0,"Flag":0,"ValueSpace":null}],` +
+		`"Rows":[[1,5,9,"+"],[2,6,0,"-"],[3,7,1,"-"],[4,8,2,"+"]],` +
+		`"ClassIndex":3}`
+
+	assert(t, exp, string(b), true)
+
+	got := tabula.Claset{}
+
+	e = json.Unmarshal(b, &got)
+	if e != nil {
+		t.Fatal(e)
+	}
+
+	assert(t, claset, &got, true)
+}
+
+func TestClasetGob(t *testing.T) {
+	claset := tabula.NewClaset(tabula.DatasetModeMatrix, testColTypes,
+		testColNames)
+	claset.SetClassIndex(testClassIdx)
+	claset.Columns[testClassIdx].ValueSpace = []string{"+", "-"}
+	claset.Columns[testClassIdx].Categorical = true
+
+	rows, e := initRows()
+	if e != nil {
+		t.Fatal(e)
+	}
+	for _, row := range rows {
+		claset.PushRow(row)
+	}
+
+	plus := tabula.Row{
+		tabula.NewRecordInt(5),
+		tabula.NewRecordInt(9),
+		tabula.NewRecordInt(3),
+		tabula.NewRecordString("+"),
+	}
+	claset.PushRow(&plus)
+	claset.RecountMajorMinor()
+
+	var buf bytes.Buffer
+
+	e = gob.NewEncoder(&buf).Encode(claset)
+	if e != nil {
+		t.Fatal(e)
+	}
+
+	got := &tabula.Claset{}
+
+	e = gob.NewDecoder(&buf).Decode(got)
+	if e != nil {
+		t.Fatal(e)
+	}
+
+	assert(t, claset, got, true)
+	assert(t, true, got.Columns[testClassIdx].Categorical, true)
+	assert(t, 1, got.Rows[1].GetRecord(testClassIdx).Code(), true)
+	assert(t, "+", got.MajorityClass(), true)
+	assert(t, "-", got.MinorityClass(), true)
+	assert(t, []int{3, 2}, got.Counts(), true)
+
+	clone := claset.Clone().(*tabula.Claset)
+
+	assert(t, []int{3, 2}, clone.Counts(), true)
+	assert(t, "+", clone.MajorityClass(), true)
+}
@@ -5,6 +5,8 @@
 package tabula
 
 import (
+	"bytes"
+	"encoding/json"
 	"strconv"
 )
 
@@ -21,8 +23,36 @@ type Column struct {
 	Flag int
 	// ValueSpace contain the possible value in records
 	ValueSpace []string
+	// TimeFormat define the layouts and time zone to parse and format
+	// the value in TTime column. If its nil, DefaultTimeLayouts in UTC is
+	// used.
+	TimeFormat *TimeFormat
+	// MissingValues contain the values, for example "", "NA", or "null",
+	// that is set to null when its read or set into column.
+	MissingValues []string
+	// Scale is the number of digits after decimal point of the value in
+	// TDecimal column. Decimal that is pushed or set into column is
+	// rescaled to it. If its DecimalAutoScale, the scale is taken from the
+	// first decimal value. Column that is created by NewColumn or
+	// Columns.SetTypes with TDecimal type use DecimalAutoScale.
+	Scale int
+	// Categorical if its true, string value that is pushed or set into
+	// column is stored as the index of its value in ValueSpace, instead of
+	// as string. The value is decoded back when the record is converted
+	// to string.
+	Categorical bool
+	// CategoryPolicy define what to do with the value that is not in
+	// ValueSpace on categorical column. CategoryReject set the record to
+	// null and return ErrNotInValueSpace from PushBack, or return it as
+	// ReadError when its read by DSVReader, DSVChunkReader, SQLReader,
+	// FixedWidthReader, XMLReader, XLSXReader, or ReadJSONL.
+	// CategoryExtend append the value into ValueSpace.
+	CategoryPolicy int
 	// Records contain column data.
 	Records Records
+
+	// dict contain the dictionary of ValueSpace on categorical column.
+	dict *categoryDict
 }
 
 //
@@ -34,6 +64,9 @@ func NewColumn(colType int, colName string) (col *Column) {
 		Name: colName,
 		Flag: 0,
 	}
+	if colType == TDecimal {
+		col.Scale = DecimalAutoScale
+	}
 
 	col.Records = make([]*Record, 0)
 
@@ -164,17 +197,188 @@ func (col *Column) Len() int {
 }
 
 //
-// PushBack push record the end of column.
+// PushBack push record the end of column. On categorical column, the record
+// is encoded, and on decimal column, the record is rescaled to column scale.
+// The record is always pushed, but if its failed, it will return the error:
+// value that is rejected by category policy is set to null and
+// ErrNotInValueSpace is returned, and decimal that can not be rescaled is
+// kept as is.
 //
-func (col *Column) PushBack(r *Record) {
+func (col *Column) PushBack(r *Record) (e error) {
+	e = col.normalize(r)
 	col.Records = append(col.Records, r)
+	return e
 }
 
 //
-// PushRecords append slice of record to the end of column's records.
+// PushRecords append slice of record to the end of column's records. Each
+// record is encoded or rescaled as in PushBack, and the first error is
+// returned.
 //
-func (col *Column) PushRecords(rs []*Record) {
+func (col *Column) PushRecords(rs []*Record) (e error) {
+	for _, r := range rs {
+		if err := col.normalize(r); err != nil && e == nil {
+			e = err
+		}
+	}
 	col.Records = append(col.Records, rs...)
+	return e
+}
+
+//
+// Encode convert the string value of record `r` into its index in column
+// value space, if column is categorical. Nil, null, and missing value is
+// not encoded. If value is not in value space, it will return
+// ErrNotInValueSpace and record is not changed, unless CategoryPolicy is
+// CategoryExtend where the value is appended into value space.
+//
+func (col *Column) Encode(r *Record) error {
+	if !col.Categorical || col.Type != TString || r == nil ||
+		r.IsMissingValue() {
+		return nil
+	}
+
+	dict := col.categories()
+
+	var v string
+
+	switch rv := r.v.(type) {
+	case string:
+		v = rv
+	case *categoryCode:
+		if rv.dict == dict {
+			return nil
+		}
+		v = rv.String()
+	default:
+		return nil
+	}
+
+	c := dict.lookup(v)
+	if c == nil {
+		if col.CategoryPolicy != CategoryExtend {
+			return ErrNotInValueSpace
+		}
+		c = dict.push(v)
+		col.ValueSpace = dict.values
+	}
+
+	r.v = c
+
+	return nil
+}
+
+//
+// normalize encode record `r` on categorical column, or rescale it to column
+// scale on decimal column. If the column scale is DecimalAutoScale, it is
+// set to the scale of the first decimal that is not missing value.
+//
+// Value that is rejected by category policy is set to null, and decimal that
+// can not be rescaled is kept as is, and the error is returned.
+//
+func (col *Column) normalize(r *Record) (e error) {
+	e = col.Encode(r)
+	if e != nil {
+		r.SetNull(col.Type)
+		return e
+	}
+	if col.Type != TDecimal || r == nil {
+		return nil
+	}
+	d, ok := r.v.(Decimal)
+	if !ok {
+		return nil
+	}
+	if col.Scale < 0 {
+		if d.IsMissing() {
+			return nil
+		}
+		col.Scale = d.Scale
+	}
+	d, e = d.Rescale(col.Scale)
+	if e != nil {
+		return e
+	}
+	r.v = d
+	return nil
+}
+
+//
+// decimalScale return the column scale, or zero if the scale has not been
+// taken from the value.
+//
+func (col *Column) decimalScale() int {
+	if col.Scale < 0 {
+		return 0
+	}
+	return col.Scale
+}
+
+//
+// newRecord create new record from value `v` using column type, time
+// format, and scale, where numeric value is normalized using number format
+// `nf`. Decimal that can not be rescaled to column scale will return an
+// error.
+//
+func (col *Column) newRecord(v string, nf *NumberFormat) (*Record, error) {
+	if col.Type == TDecimal {
+		d, e := ParseDecimal(nf.Normalize(v), col.Scale)
+		if e != nil {
+			return nil, e
+		}
+		return NewRecordDecimal(d), nil
+	}
+	return newRecordFormat(v, col.Type, nf, col.TimeFormat)
+}
+
+//
+// SumDecimal return the exact sum of column values as decimal with column
+// scale. Null and missing value is skipped. If the value can not be
+// converted to decimal, it will return ErrInvalidDecimal.
+//
+// The sum only use column records, which is empty on dataset with rows mode,
+// use Dataset.SumDecimal to sum the column on any mode.
+//
+func (col *Column) SumDecimal() (sum Decimal, e error) {
+	sum.Scale = col.decimalScale()
+
+	for _, rec := range col.Records {
+		sum, e = addDecimal(sum, rec)
+		if e != nil {
+			return Decimal{}, e
+		}
+	}
+
+	return sum, nil
+}
+
+//
+// addDecimal add the value of record `rec` into `sum`. Nil, null, and
+// missing value is skipped.
+//
+func addDecimal(sum Decimal, rec *Record) (Decimal, error) {
+	if rec == nil || rec.IsMissingValue() {
+		return sum, nil
+	}
+
+	d := rec.Decimal()
+	if d.IsMissing() {
+		return sum, ErrInvalidDecimal
+	}
+
+	return sum.Add(d)
+}
+
+//
+// categories return the dictionary of column value space. The dictionary is
+// created again if value space has been replaced.
+//
+func (col *Column) categories() *categoryDict {
+	if col.dict == nil || !col.dict.isValues(col.ValueSpace) {
+		col.dict = newCategoryDict(col.ValueSpace)
+		col.ValueSpace = col.dict.values
+	}
+	return col.dict
 }
 
 //
@@ -226,9 +430,29 @@ func (col *Column) ClearValues() {
 	}
 }
 
+//
+// IsMissingValue return true if `v` is one of column missing values.
+//
+func (col *Column) IsMissingValue(v string) bool {
+	return isMissingValue(col.MissingValues, v)
+}
+
+//
+// isMissingValue return true if `v` is one of `missingValues`.
+//
+func isMissingValue(missingValues []string, v string) bool {
+	for _, mv := range missingValues {
+		if v == mv {
+			return true
+		}
+	}
+	return false
+}
+
 //
 // SetValueAt will set column value at cell `idx` with `v`, unless the index
-// is out of range.
+// is out of range. If `v` is one of missing values, the record is set to
+// null. On categorical column, the value is encoded as in PushBack.
 //
 func (col *Column) SetValueAt(idx int, v string) {
 	if idx < 0 {
@@ -237,12 +461,22 @@ func (col *Column) SetValueAt(idx int, v string) {
 	if col.Records.Len() <= idx {
 		return
 	}
+	if col.IsMissingValue(v) {
+		col.Records[idx].SetNull(col.Type)
+		return
+	}
+	if col.Type == TTime {
+		_ = col.Records[idx].SetTimeValue(v, col.TimeFormat)
+		return
+	}
 	_ = col.Records[idx].SetValue(v, col.Type)
+	col.normalize(col.Records[idx])
 }
 
 //
 // SetValueByNumericAt will set column value at cell `idx` with numeric value
-// `v`, unless the index is out of range.
+// `v`, unless the index is out of range. On boolean column, non zero value is
+// set to true. On time column, the value is Unix time in seconds.
 //
 func (col *Column) SetValueByNumericAt(idx int, v float64) {
 	if idx < 0 {
@@ -258,11 +492,19 @@ func (col *Column) SetValueByNumericAt(idx int, v float64) {
 		col.Records[idx].SetInteger(int64(v))
 	case TReal:
 		col.Records[idx].SetFloat(v)
+	case TBool:
+		col.Records[idx].SetBool(v != 0)
+	case TTime:
+		col.Records[idx].SetTime(NewRecordReal(v).Time())
+	case TDecimal:
+		col.Records[idx].SetDecimal(NewRecordReal(v).Decimal())
+		col.normalize(col.Records[idx])
 	}
 }
 
 //
-// SetValues of all column record.
+// SetValues of all column record. Value that is one of missing values is set
+// to null.
 //
 func (col *Column) SetValues(values []string) {
 	vallen := len(values)
@@ -281,10 +523,37 @@ func (col *Column) SetValues(values []string) {
 	}
 
 	for x := 0; x < minlen; x++ {
-		_ = col.Records[x].SetValue(values[x], col.Type)
+		if col.Records[x] == nil {
+			col.Records[x] = NewRecord()
+		}
+		col.SetValueAt(x, values[x])
 	}
 }
 
+//
+// CountNull return number of null record in column.
+//
+func (col *Column) CountNull() (n int) {
+	for _, rec := range col.Records {
+		if rec == nil || rec.IsNull() {
+			n++
+		}
+	}
+	return
+}
+
+//
+// NullIndexes return the index of null record in column.
+//
+func (col *Column) NullIndexes() (idxs []int) {
+	for x, rec := range col.Records {
+		if rec == nil || rec.IsNull() {
+			idxs = append(idxs, x)
+		}
+	}
+	return
+}
+
 //
 // DeleteRecordAt will delete record at index `i` and return it.
 //
@@ -307,3 +576,341 @@ func (col *Column) DeleteRecordAt(i int) *Record {
 
 	return r
 }
+
+//
+// columnJSON define the JSON representation of column.
+//
+type columnJSON struct {
+	Name           string
+	Type           int
+	Flag           int
+	ValueSpace     []string
+	TimeFormat     *TimeFormat       `json:",omitempty"`
+	MissingValues  []string          `json:",omitempty"`
+	Scale          int               `json:",omitempty"`
+	Categorical    bool              `json:",omitempty"`
+	CategoryPolicy int               `json:",omitempty"`
+	Records        []json.RawMessage `json:",omitempty"`
+}
+
+//
+// MarshalJSON convert column metadata and its records into JSON object.
+//
+func (col *Column) MarshalJSON() ([]byte, error) {
+	out := columnJSON{
+		Name:           col.Name,
+		Type:           col.Type,
+		Flag:           col.Flag,
+		ValueSpace:     col.ValueSpace,
+		TimeFormat:     col.TimeFormat,
+		MissingValues:  col.MissingValues,
+		Scale:          col.Scale,
+		Categorical:    col.Categorical,
+		CategoryPolicy: col.CategoryPolicy,
+		Records:        make([]json.RawMessage, len(col.Records)),
+	}
+
+	for x, rec := range col.Records {
+		if rec == nil {
+			out.Records[x] = json.RawMessage("null")
+			continue
+		}
+		v, e := rec.MarshalJSON()
+		if e != nil {
+			return nil, e
+		}
+		out.Records[x] = v
+	}
+
+	return json.Marshal(&out)
+}
+
+//
+// UnmarshalJSON set the column metadata and records from JSON object. Each
+// record is converted using the column type, null is converted to missing
+// value.
+//
+func (col *Column) UnmarshalJSON(b []byte) (e error) {
+	var in columnJSON
+
+	e = json.Unmarshal(b, &in)
+	if e != nil {
+		return e
+	}
+
+	col.Name = in.Name
+	col.Type = in.Type
+	col.Flag = in.Flag
+	col.ValueSpace = in.ValueSpace
+	col.TimeFormat = in.TimeFormat
+	col.MissingValues = in.MissingValues
+	col.Scale = in.Scale
+	col.Categorical = in.Categorical
+	col.CategoryPolicy = in.CategoryPolicy
+	col.Records = nil
+
+	if in.Records != nil {
+		col.Records = make(Records, len(in.Records))
+	}
+
+	for x, raw := range in.Records {
+		col.Records[x], e = NewRecordJSON(raw, in.Type)
+		if e != nil {
+			return e
+		}
+		col.normalize(col.Records[x])
+	}
+
+	return nil
+}
+
+//
+// MarshalBinary convert column metadata and its records into binary.
+//
+func (col *Column) MarshalBinary() ([]byte, error) {
+	b := col.appendBinaryMeta(nil)
+
+	b = appendUvarint(b, uint64(len(col.Records)))
+	for _, rec := range col.Records {
+		if rec == nil {
+			rec = NewRecord()
+		}
+		b = rec.appendBinary(b)
+	}
+
+	return b, nil
+}
+
+//
+// UnmarshalBinary set the column metadata and its records from binary that
+// is created by MarshalBinary. Record on categorical column is encoded
+// again using its value space.
+//
+func (col *Column) UnmarshalBinary(b []byte) (e error) {
+	br := bytes.NewReader(b)
+
+	e = col.readBinaryMeta(br, ErrInvalidBinary)
+	if e != nil {
+		return
+	}
+
+	n, e := readLength(br, ErrInvalidBinary)
+	if e != nil {
+		return
+	}
+
+	col.Records = make(Records, n)
+	for x := range col.Records {
+		col.Records[x], e = readRecordBinary(br)
+		if e != nil {
+			return
+		}
+		col.normalize(col.Records[x])
+	}
+
+	if br.Len() > 0 {
+		return ErrInvalidBinary
+	}
+
+	return nil
+}
+
+//
+// GobEncode implement the gob.GobEncoder interface, see MarshalBinary.
+//
+func (col *Column) GobEncode() ([]byte, error) {
+	return col.MarshalBinary()
+}
+
+//
+// GobDecode implement the gob.GobDecoder interface, see UnmarshalBinary.
+//
+func (col *Column) GobDecode(b []byte) error {
+	return col.UnmarshalBinary(b)
+}
+
+//
+// appendBinaryMeta convert column name, type, flag, value space, time
+// format, missing values, scale, and categorical policy into binary and
+// append it to `b`.
+//
+func (col *Column) appendBinaryMeta(b []byte) []byte {
+	b = appendBinaryString(b, col.Name)
+	b = appendVarint(b, int64(col.Type))
+	b = appendVarint(b, int64(col.Flag))
+	b = appendBinaryStrings(b, col.ValueSpace)
+	b = appendTimeFormatBinary(b, col.TimeFormat)
+	b = appendBinaryStrings(b, col.MissingValues)
+	b = appendVarint(b, int64(col.Scale))
+	if col.Categorical {
+		b = append(b, 1)
+	} else {
+		b = append(b, 0)
+	}
+	return appendVarint(b, int64(col.CategoryPolicy))
+}
+
+//
+// readBinaryMeta read column name, type, flag, value space, time format,
+// missing values, scale, and categorical policy from `br`. If its failed, it
+// will return `errInvalid`.
+//
+func (col *Column) readBinaryMeta(br *bytes.Reader, errInvalid error) (
+	e error,
+) {
+	col.Name, e = readBinaryString(br, errInvalid)
+	if e != nil {
+		return
+	}
+
+	tipe, e := readVarint(br, errInvalid)
+	if e != nil {
+		return
+	}
+	flag, e := readVarint(br, errInvalid)
+	if e != nil {
+		return
+	}
+	col.Type = int(tipe)
+	col.Flag = int(flag)
+
+	col.ValueSpace, e = readBinaryStrings(br, errInvalid)
+	if e != nil {
+		return
+	}
+
+	col.TimeFormat, e = readTimeFormatBinary(br, errInvalid)
+	if e != nil {
+		return
+	}
+
+	col.MissingValues, e = readBinaryStrings(br, errInvalid)
+	if e != nil {
+		return
+	}
+
+	scale, e := readVarint(br, errInvalid)
+	if e != nil {
+		return
+	}
+	col.Scale = int(scale)
+
+	c, e := br.ReadByte()
+	if e != nil || c > 1 {
+		return errInvalid
+	}
+	col.Categorical = c == 1
+
+	policy, e := readVarint(br, errInvalid)
+	if e != nil {
+		return
+	}
+	col.CategoryPolicy = int(policy)
+
+	return
+}
+
+//
+// appendMsgpackMeta convert column name, type, flag, value space, time
+// format, missing values, scale, and categorical policy into MessagePack map
+// and append it to `b`.
+//
+func (col *Column) appendMsgpackMeta(b []byte) []byte {
+	n := 4
+	if col.TimeFormat != nil {
+		n++
+	}
+	if len(col.MissingValues) > 0 {
+		n++
+	}
+	if col.Scale != 0 {
+		n++
+	}
+	if col.Categorical {
+		n += 2
+	}
+
+	b = appendMsgpackMap(b, n)
+	if col.TimeFormat != nil {
+		b = appendMsgpackString(b, "TimeFormat")
+		b = col.TimeFormat.appendMsgpack(b)
+	}
+	if len(col.MissingValues) > 0 {
+		b = appendMsgpackString(b, "MissingValues")
+		b = appendMsgpackStrings(b, col.MissingValues)
+	}
+	if col.Scale != 0 {
+		b = appendMsgpackString(b, "Scale")
+		b = appendMsgpackInt(b, int64(col.Scale))
+	}
+	if col.Categorical {
+		b = appendMsgpackString(b, "Categorical")
+		b = appendMsgpackBool(b, true)
+		b = appendMsgpackString(b, "CategoryPolicy")
+		b = appendMsgpackInt(b, int64(col.CategoryPolicy))
+	}
+	b = appendMsgpackString(b, "Name")
+	b = appendMsgpackString(b, col.Name)
+	b = appendMsgpackString(b, "Type")
+	b = appendMsgpackInt(b, int64(col.Type))
+	b = appendMsgpackString(b, "Flag")
+	b = appendMsgpackInt(b, int64(col.Flag))
+	b = appendMsgpackString(b, "ValueSpace")
+	return appendMsgpackStrings(b, col.ValueSpace)
+}
+
+//
+// readMsgpackMeta read column name, type, flag, value space, time format,
+// missing values, scale, and categorical policy from MessagePack map in
+// `br`. Unknown key is ignored.
+//
+func (col *Column) readMsgpackMeta(br *bytes.Reader) (e error) {
+	n, e := readMsgpackMap(br)
+	if e != nil {
+		return
+	}
+	if n < 0 {
+		return ErrInvalidMsgpack
+	}
+
+	for ; n > 0; n-- {
+		key, e := readMsgpackString(br)
+		if e != nil {
+			return e
+		}
+
+		var v int64
+
+		switch key {
+		case "Name":
+			col.Name, e = readMsgpackString(br)
+		case "Type":
+			v, e = readMsgpackInt(br)
+			col.Type = int(v)
+		case "Flag":
+			v, e = readMsgpackInt(br)
+			col.Flag = int(v)
+		case "ValueSpace":
+			col.ValueSpace, e = readMsgpackStrings(br)
+		case "TimeFormat":
+			col.TimeFormat, e = readTimeFormatMsgpack(br)
+		case "MissingValues":
+			col.MissingValues, e = readMsgpackStrings(br)
+		case "Scale":
+			v, e = readMsgpackInt(br)
+			col.Scale = int(v)
+		case "Categorical":
+			col.Categorical, e = readMsgpackBool(br)
+		case "CategoryPolicy":
+			v, e = readMsgpackInt(br)
+			col.CategoryPolicy = int(v)
+		default:
+			e = skipMsgpack(br)
+		}
+		if e != nil {
+			return e
+		}
+	}
+
+	return nil
+}
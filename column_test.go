@@ -64,3 +64,120 @@ func TestDeleteRecordAt(t *testing.T) {
 
 	assert(t, exp, got, true)
 }
+
+func TestColumnBinary(t *testing.T) {
+	col := initColReal(t)
+	col.Flag = 2
+	col.ValueSpace = []string{"a", "b"}
+	col.PushBack(tabula.NewRecord())
+
+	b, e := col.MarshalBinary()
+	if e != nil {
+		t.Fatal(e)
+	}
+
+	got := tabula.Column{}
+
+	e = got.UnmarshalBinary(b)
+	if e != nil {
+		t.Fatal(e)
+	}
+
+	assert(t, col, &got, true)
+
+	e = got.UnmarshalBinary(b[:len(b)-1])
+
+	assert(t, tabula.ErrInvalidBinary, e, true)
+}
+
+func TestColumnMissingValues(t *testing.T) {
+	col := tabula.NewColumn(tabula.TInteger, "qty")
+	col.MissingValues = []string{"", "NA", "null"}
+
+	col.SetValues([]string{"1", "NA", "3", "", "null"})
+
+	assert(t, 3, col.CountNull(), true)
+	assert(t, []int{1, 3, 4}, col.NullIndexes(), true)
+
+	col.SetValueAt(2, "4")
+	col.SetValueAt(0, "NA")
+
+	assert(t, []int{0, 1, 3, 4}, col.NullIndexes(), true)
+	assert(t, tabula.TInteger, col.Records[0].Type(), true)
+	assert(t, int64(4), col.Records[2].Integer(), true)
+
+	b, e := col.MarshalBinary()
+	if e != nil {
+		t.Fatal(e)
+	}
+
+	got := tabula.Column{}
+
+	e = got.UnmarshalBinary(b)
+	if e != nil {
+		t.Fatal(e)
+	}
+
+	assert(t, col, &got, true)
+
+	got.SetValueAt(2, "null")
+
+	assert(t, []int{0, 1, 2, 3, 4}, got.NullIndexes(), true)
+}
+
+func TestColumnCategorical(t *testing.T) {
+	col := tabula.NewColumn(tabula.TString, "class")
+	col.ValueSpace = []string{"yes", "no"}
+	col.Categorical = true
+
+	for _, v := range []string{"no", "yes", "maybe", "no"} {
+		col.PushBack(tabula.NewRecordString(v))
+	}
+
+	assert(t, []string{"no", "yes", "", "no"}, col.ToStringSlice(), true)
+	assert(t, 1, col.Records[0].Code(), true)
+	assert(t, 0, col.Records[1].Code(), true)
+	assert(t, true, col.Records[2].IsNull(), true)
+	assert(t, "no", col.Records[3].Interface(), true)
+	assert(t, true, col.Records[0].IsEqual(col.Records[3]), true)
+	assert(t, true, col.Records[0].IsEqualToInterface("no"), true)
+
+	e := col.Encode(tabula.NewRecordString("maybe"))
+	assert(t, tabula.ErrNotInValueSpace, e, true)
+
+	col.CategoryPolicy = tabula.CategoryExtend
+	col.PushBack(tabula.NewRecordString("maybe"))
+	col.SetValueAt(0, "unknown")
+
+	assert(t, []string{"yes", "no", "maybe", "unknown"}, col.ValueSpace,
+		true)
+	assert(t, []string{"unknown", "yes", "", "no", "maybe"},
+		col.ToStringSlice(), true)
+	assert(t, 3, col.Records[0].Code(), true)
+	assert(t, 2, col.Records[4].Code(), true)
+
+	col.PushBack(tabula.NewRecordString("?"))
+	assert(t, -1, col.Records[5].Code(), true)
+	assert(t, true, col.Records[5].IsMissingValue(), true)
+
+	b, e := col.MarshalBinary()
+	if e != nil {
+		t.Fatal(e)
+	}
+
+	got := tabula.Column{}
+
+	e = got.UnmarshalBinary(b)
+	if e != nil {
+		t.Fatal(e)
+	}
+
+	assert(t, col, &got, true)
+	assert(t, 3, got.Records[0].Code(), true)
+
+	got.PushBack(tabula.NewRecordString("new"))
+
+	assert(t, 4, got.Records[6].Code(), true)
+	assert(t, []string{"yes", "no", "maybe", "unknown", "new"},
+		got.ValueSpace, true)
+}
@@ -0,0 +1,683 @@
+// Copyright 2017 M. Shulhan <ms@kilabit.info>. All rights reserved.
+// Use of this source code is governed by a BSD-style license that can be found
+// in the LICENSE file.
+
+package tabula
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/binary"
+	"errors"
+	"io"
+	"math"
+	"time"
+)
+
+//
+// The columnar file format is a binary format for saving dataset, where each
+// column is saved as typed block, followed by footer which contain the
+// schema and the index of each block,
+//
+// 	magic
+// 	column block 0
+// 	...
+// 	column block n
+// 	footer
+// 	footer offset (8 bytes, little endian)
+// 	magic
+//
+// Each column block is started with number of nil and null records and, if
+// its not zero, the bitmap of them, where nil record is read back as null
+// record with the column type. The values is saved based on column type:
+// TInteger as varint, TReal as 8 bytes of IEEE 754 bits in little endian,
+// TBool as one byte of 0 for false, 1 for true, or 2 for missing value, TTime
+// as varint of Unix seconds, uvarint of nanoseconds, and varint of time zone
+// offset in seconds, with missing value saved as zero time, TDecimal as
+// varint of unscaled value followed by uvarint of scale, and TString as
+// uvarint length followed by the string.
+//
+// The footer contain number of rows, class index or -1 if dataset is not a
+// claset, number of columns, and for each column: name, type, flag, value
+// space, time format, missing values, scale, categorical policy, offset, and
+// size of block.
+//
+const (
+	columnarMagic = "TBLCOL01"
+)
+
+var (
+	// ErrInvalidColumnar returned when input is not a valid columnar
+	// file.
+	ErrInvalidColumnar = errors.New("tabula: invalid columnar format")
+)
+
+//
+// columnarMeta contain the column metadata and its block position in
+// columnar file.
+//
+type columnarMeta struct {
+	Column
+	offset int64
+	size   int64
+}
+
+//
+// columnarWriter wrap writer to count the number of bytes written.
+//
+type columnarWriter struct {
+	bw *bufio.Writer
+	n  int64
+}
+
+func (cw *columnarWriter) write(b []byte) (e error) {
+	_, e = cw.bw.Write(b)
+	cw.n += int64(len(b))
+	return
+}
+
+//
+// WriteColumnarFile create or truncate `file` and write dataset `ds` into it
+// using columnar format.
+//
+func WriteColumnarFile(file string, ds DatasetInterface) (e error) {
+	f, e := CreateFile(file)
+	if e != nil {
+		return e
+	}
+
+	e = WriteColumnar(f, ds)
+
+	errClose := f.Close()
+	if e == nil {
+		e = errClose
+	}
+
+	return
+}
+
+//
+// WriteColumnar write dataset `ds` into `w` using columnar format. If `ds`
+// is a claset, the class index is also saved. Dataset is read based on its
+// mode without transposing.
+//
+func WriteColumnar(w io.Writer, ds DatasetInterface) (e error) {
+	cw := &columnarWriter{
+		bw: bufio.NewWriter(w),
+	}
+
+	e = cw.write([]byte(columnarMagic))
+	if e != nil {
+		return
+	}
+
+	cols := ds.GetColumns()
+	nrow := ds.Len()
+	metas := make([]columnarMeta, len(*cols))
+
+	var block []byte
+
+	for x, col := range *cols {
+		block = block[:0]
+		block = appendColumnarBlock(block, ds, x, col.Type, nrow)
+
+		metas[x].Column = col
+		metas[x].offset = cw.n
+		metas[x].size = int64(len(block))
+
+		e = cw.write(block)
+		if e != nil {
+			return
+		}
+	}
+
+	classIdx := -1
+	if claset, ok := ds.(ClasetInterface); ok {
+		classIdx = claset.GetClassIndex()
+	}
+
+	footerOffset := cw.n
+
+	footer := appendUvarint(nil, uint64(nrow))
+	footer = appendVarint(footer, int64(classIdx))
+	footer = appendUvarint(footer, uint64(len(metas)))
+
+	for _, meta := range metas {
+		footer = meta.appendBinaryMeta(footer)
+		footer = appendUvarint(footer, uint64(meta.offset))
+		footer = appendUvarint(footer, uint64(meta.size))
+	}
+
+	footer = append(footer, make([]byte, 8)...)
+	binary.LittleEndian.PutUint64(footer[len(footer)-8:],
+		uint64(footerOffset))
+	footer = append(footer, columnarMagic...)
+
+	e = cw.write(footer)
+	if e != nil {
+		return
+	}
+
+	return cw.bw.Flush()
+}
+
+//
+// appendColumnarBlock convert all records in column `x` into block.
+//
+func appendColumnarBlock(block []byte, ds DatasetInterface, x, tipe,
+	nrow int,
+) []byte {
+	var nils []byte
+	nnil := 0
+
+	recs := make([]*Record, nrow)
+	for r := 0; r < nrow; r++ {
+		recs[r] = getRecordAt(ds, r, x)
+		if recs[r] != nil && !recs[r].IsNull() {
+			continue
+		}
+		if nils == nil {
+			nils = make([]byte, (nrow+7)/8)
+		}
+		nils[r/8] |= 1 << uint(r%8)
+		nnil++
+	}
+
+	block = appendUvarint(block, uint64(nnil))
+	block = append(block, nils...)
+
+	var f64 [8]byte
+
+	for _, rec := range recs {
+		isNil := rec == nil || rec.IsNull()
+
+		switch tipe {
+		case TInteger:
+			var v int64
+			if !isNil {
+				v = rec.Integer()
+			}
+			block = appendVarint(block, v)
+		case TReal:
+			var v float64
+			if !isNil {
+				v = rec.Float()
+			}
+			binary.LittleEndian.PutUint64(f64[:], math.Float64bits(v))
+			block = append(block, f64[:]...)
+		case TBool:
+			switch {
+			case isNil || !rec.IsMissingValue() && !rec.Bool():
+				block = append(block, recordBinaryFalse)
+			case rec.IsMissingValue():
+				block = append(block, recordBinaryBoolMissing)
+			default:
+				block = append(block, recordBinaryTrue)
+			}
+		case TTime:
+			var v time.Time
+			if !isNil {
+				v = rec.Time()
+			}
+			_, offset := v.Zone()
+			block = appendVarint(block, v.Unix())
+			block = appendUvarint(block, uint64(v.Nanosecond()))
+			block = appendVarint(block, int64(offset))
+		case TDecimal:
+			var v Decimal
+			if !isNil {
+				v = rec.Decimal()
+			}
+			block = appendVarint(block, v.Value)
+			block = appendUvarint(block, uint64(v.Scale))
+		default:
+			var v string
+			if !isNil {
+				v = rec.String()
+			}
+			block = appendBinaryString(block, v)
+		}
+	}
+
+	return block
+}
+
+//
+// ColumnarReader read dataset from columnar file. The schema and index is
+// read when reader is created, and each column is read only when requested.
+//
+type ColumnarReader struct {
+	r        io.ReaderAt
+	closer   io.Closer
+	nrow     int
+	classIdx int
+	metas    []columnarMeta
+}
+
+//
+// OpenColumnarFile open columnar `file` and return the reader. Caller must
+// close the reader after its not used anymore.
+//
+func OpenColumnarFile(file string) (reader *ColumnarReader, e error) {
+	r, size, closer, e := openFileAt(file)
+	if e != nil {
+		return nil, e
+	}
+
+	reader, e = NewColumnarReader(r, size)
+	if e != nil {
+		if closer != nil {
+			_ = closer.Close()
+		}
+		return nil, e
+	}
+
+	reader.closer = closer
+
+	return reader, nil
+}
+
+//
+// NewColumnarReader create new reader from `r` with `size` bytes, and read
+// the schema and index from footer.
+//
+func NewColumnarReader(r io.ReaderAt, size int64) (
+	reader *ColumnarReader, e error,
+) {
+	magicLen := int64(len(columnarMagic))
+	trailerLen := 8 + magicLen
+
+	if size < magicLen+trailerLen {
+		return nil, ErrInvalidColumnar
+	}
+
+	magic := make([]byte, magicLen)
+
+	_, e = r.ReadAt(magic, 0)
+	if e != nil {
+		return nil, e
+	}
+	if string(magic) != columnarMagic {
+		return nil, ErrInvalidColumnar
+	}
+
+	trailer := make([]byte, trailerLen)
+
+	_, e = r.ReadAt(trailer, size-trailerLen)
+	if e != nil {
+		return nil, e
+	}
+	if string(trailer[8:]) != columnarMagic {
+		return nil, ErrInvalidColumnar
+	}
+
+	footerOffset := int64(binary.LittleEndian.Uint64(trailer))
+	if footerOffset < magicLen || footerOffset > size-trailerLen {
+		return nil, ErrInvalidColumnar
+	}
+
+	footer := make([]byte, size-trailerLen-footerOffset)
+
+	_, e = r.ReadAt(footer, footerOffset)
+	if e != nil {
+		return nil, e
+	}
+
+	reader = &ColumnarReader{
+		r: r,
+	}
+
+	e = reader.parseFooter(bytes.NewReader(footer), footerOffset)
+	if e != nil {
+		return nil, e
+	}
+
+	return reader, nil
+}
+
+//
+// parseFooter read the schema and index from footer.
+//
+func (reader *ColumnarReader) parseFooter(br *bytes.Reader,
+	footerOffset int64,
+) (e error) {
+	nrow, e := readUvarint(br, ErrInvalidColumnar)
+	if e != nil {
+		return
+	}
+	// Each row in column block need at least one byte.
+	if nrow > math.MaxInt32 || nrow > uint64(footerOffset) {
+		return ErrInvalidColumnar
+	}
+	classIdx, e := readVarint(br, ErrInvalidColumnar)
+	if e != nil {
+		return
+	}
+	ncol, e := readLength(br, ErrInvalidColumnar)
+	if e != nil {
+		return
+	}
+
+	reader.nrow = int(nrow)
+	reader.classIdx = int(classIdx)
+	reader.metas = make([]columnarMeta, ncol)
+
+	for x := range reader.metas {
+		meta := &reader.metas[x]
+
+		e = meta.readBinaryMeta(br, ErrInvalidColumnar)
+		if e != nil {
+			return
+		}
+
+		offset, e := readUvarint(br, ErrInvalidColumnar)
+		if e != nil {
+			return e
+		}
+		size, e := readUvarint(br, ErrInvalidColumnar)
+		if e != nil {
+			return e
+		}
+		if offset > uint64(footerOffset) ||
+			size > uint64(footerOffset)-offset || nrow > size {
+			return ErrInvalidColumnar
+		}
+		meta.offset = int64(offset)
+		meta.size = int64(size)
+	}
+
+	return nil
+}
+
+//
+// Close the underlying file, if reader is created using OpenColumnarFile.
+//
+func (reader *ColumnarReader) Close() error {
+	if reader.closer == nil {
+		return nil
+	}
+	return reader.closer.Close()
+}
+
+//
+// Len return number of rows in columnar file.
+//
+func (reader *ColumnarReader) Len() int {
+	return reader.nrow
+}
+
+//
+// GetClassIndex return the class index in columnar file, or -1 if the saved
+// dataset is not a claset.
+//
+func (reader *ColumnarReader) GetClassIndex() int {
+	return reader.classIdx
+}
+
+//
+// GetColumnsName return name of all columns in columnar file.
+//
+func (reader *ColumnarReader) GetColumnsName() (names []string) {
+	for _, meta := range reader.metas {
+		names = append(names, meta.Name)
+	}
+	return
+}
+
+//
+// GetColumnsType return type of all columns in columnar file.
+//
+func (reader *ColumnarReader) GetColumnsType() (types []int) {
+	for _, meta := range reader.metas {
+		types = append(types, meta.Type)
+	}
+	return
+}
+
+//
+// ReadColumnByName read and return column with `name`, or nil and
+// ErrColIdxOutOfRange if no column with that name.
+//
+func (reader *ColumnarReader) ReadColumnByName(name string) (*Column, error) {
+	for x, meta := range reader.metas {
+		if meta.Name == name {
+			return reader.ReadColumn(x)
+		}
+	}
+	return nil, ErrColIdxOutOfRange
+}
+
+//
+// ReadColumn read and return column at index `idx` including its records.
+// Only the block of column is read from file.
+//
+func (reader *ColumnarReader) ReadColumn(idx int) (col *Column, e error) {
+	if idx < 0 || idx >= len(reader.metas) {
+		return nil, ErrColIdxOutOfRange
+	}
+
+	meta := &reader.metas[idx]
+	block := make([]byte, meta.size)
+
+	_, e = reader.r.ReadAt(block, meta.offset)
+	if e != nil {
+		return nil, e
+	}
+
+	col = &Column{
+		Name:           meta.Name,
+		Type:           meta.Type,
+		Flag:           meta.Flag,
+		ValueSpace:     meta.ValueSpace,
+		TimeFormat:     meta.TimeFormat,
+		MissingValues:  meta.MissingValues,
+		Scale:          meta.Scale,
+		Categorical:    meta.Categorical,
+		CategoryPolicy: meta.CategoryPolicy,
+		Records:        make(Records, reader.nrow),
+	}
+
+	e = columnarParseBlock(bytes.NewReader(block), col)
+	if e != nil {
+		return nil, e
+	}
+
+	return col, nil
+}
+
+//
+// columnarParseBlock convert the block into column records.
+//
+func columnarParseBlock(br *bytes.Reader, col *Column) (e error) {
+	nrow := len(col.Records)
+
+	nnil, e := readUvarint(br, ErrInvalidColumnar)
+	if e != nil {
+		return
+	}
+
+	var nils []byte
+	if nnil > 0 {
+		nils = make([]byte, (nrow+7)/8)
+		_, e = io.ReadFull(br, nils)
+		if e != nil {
+			return ErrInvalidColumnar
+		}
+	}
+
+	var f64 [8]byte
+
+	for r := 0; r < nrow; r++ {
+		rec := NewRecord()
+
+		switch col.Type {
+		case TInteger:
+			v, e := readVarint(br, ErrInvalidColumnar)
+			if e != nil {
+				return e
+			}
+			rec.SetInteger(v)
+		case TReal:
+			_, e = io.ReadFull(br, f64[:])
+			if e != nil {
+				return ErrInvalidColumnar
+			}
+			rec.SetFloat(math.Float64frombits(
+				binary.LittleEndian.Uint64(f64[:])))
+		case TBool:
+			c, e := br.ReadByte()
+			if e != nil {
+				return ErrInvalidColumnar
+			}
+			switch c {
+			case recordBinaryFalse:
+				rec.SetBool(false)
+			case recordBinaryTrue:
+				rec.SetBool(true)
+			case recordBinaryBoolMissing:
+				rec.SetMissingValue(TBool)
+			default:
+				return ErrInvalidColumnar
+			}
+		case TTime:
+			sec, e := readVarint(br, ErrInvalidColumnar)
+			if e != nil {
+				return e
+			}
+			nsec, e := readUvarint(br, ErrInvalidColumnar)
+			if e != nil || nsec >= 1e9 {
+				return ErrInvalidColumnar
+			}
+			offset, e := readVarint(br, ErrInvalidColumnar)
+			if e != nil {
+				return e
+			}
+			v := time.Unix(sec, int64(nsec)).UTC()
+			if offset != 0 {
+				v = v.In(time.FixedZone("", int(offset)))
+			}
+			rec.SetTime(v)
+		case TDecimal:
+			v, e := readVarint(br, ErrInvalidColumnar)
+			if e != nil {
+				return e
+			}
+			scale, e := readUvarint(br, ErrInvalidColumnar)
+			if e != nil || scale > DecimalMaxScale {
+				return ErrInvalidColumnar
+			}
+			rec.SetDecimal(Decimal{Value: v, Scale: int(scale)})
+		default:
+			v, e := readBinaryString(br, ErrInvalidColumnar)
+			if e != nil {
+				return e
+			}
+			rec.SetString(v)
+		}
+
+		if nils != nil && nils[r/8]&(1<<uint(r%8)) != 0 {
+			rec = NewRecordNull(col.Type)
+		}
+
+		col.normalize(rec)
+		col.Records[r] = rec
+	}
+
+	return nil
+}
+
+//
+// Read all columns into dataset `ds`, using the dataset mode.
+// See ReadColumns for more information.
+//
+func (reader *ColumnarReader) Read(ds DatasetInterface) (e error) {
+	colsIdx := make([]int, len(reader.metas))
+	for x := range colsIdx {
+		colsIdx[x] = x
+	}
+	return reader.ReadColumns(ds, colsIdx)
+}
+
+//
+// ReadColumns read only columns at index `colsIdx` into dataset `ds`, using
+// the dataset mode. The dataset columns will be replaced.
+//
+// If `ds` is a claset, its class index will be set to the position of saved
+// class column in `colsIdx`, or -1 if class column is not read.
+//
+func (reader *ColumnarReader) ReadColumns(ds DatasetInterface, colsIdx []int) (
+	e error,
+) {
+	cols := make(Columns, len(colsIdx))
+	classIdx := -1
+
+	for x, idx := range colsIdx {
+		col, e := reader.ReadColumn(idx)
+		if e != nil {
+			return e
+		}
+		cols[x] = *col
+
+		if idx == reader.classIdx {
+			classIdx = x
+		}
+	}
+
+	setDatasetColumns(ds, cols)
+
+	if claset, ok := ds.(ClasetInterface); ok {
+		claset.SetClassIndex(classIdx)
+	}
+
+	return nil
+}
+
+//
+// setDatasetColumns replace the columns in dataset with `cols`, including
+// the metadata and records, and fill the rows based on dataset mode.
+//
+func setDatasetColumns(ds DatasetInterface, cols Columns) {
+	types := make([]int, len(cols))
+	names := make([]string, len(cols))
+
+	for x, col := range cols {
+		types[x] = col.Type
+		names[x] = col.Name
+	}
+
+	mode := ds.GetMode()
+	ds.Init(mode, types, names)
+
+	dscols := ds.GetColumns()
+	for x, col := range cols {
+		dscol := &(*dscols)[x]
+		dscol.Flag = col.Flag
+		dscol.ValueSpace = col.ValueSpace
+		dscol.TimeFormat = col.TimeFormat
+		dscol.MissingValues = col.MissingValues
+		dscol.Scale = col.Scale
+		dscol.Categorical = col.Categorical
+		dscol.CategoryPolicy = col.CategoryPolicy
+		dscol.dict = col.dict
+	}
+
+	if mode == DatasetModeColumns {
+		for x, col := range cols {
+			(*dscols)[x].Records = col.Records
+		}
+		return
+	}
+
+	nrow := 0
+	if len(cols) > 0 {
+		nrow = cols[0].Len()
+	}
+
+	for r := 0; r < nrow; r++ {
+		row := make(Row, len(cols))
+		for x := range cols {
+			row[x] = cols[x].Records[r]
+		}
+		ds.PushRow(&row)
+	}
+}
@@ -0,0 +1,215 @@
+// Copyright 2017 M. Shulhan <ms@kilabit.info>. All rights reserved.
+// Use of this source code is governed by a BSD-style license that can be found
+// in the LICENSE file.
+
+package tabula_test
+
+import (
+	"bytes"
+	"github.com/shuLhan/tabula"
+	"math"
+	"testing"
+	"time"
+)
+
+func createColumnarClaset(t *testing.T) *tabula.Claset {
+	claset := tabula.NewClaset(tabula.DatasetModeRows, datasetTypes,
+		datasetNames)
+	claset.SetClassIndex(2)
+	claset.Columns[2].ValueSpace = []string{"A", "B", "C", "D", "E", "F"}
+	claset.Columns[1].Flag = 1
+
+	e := populateWithRows(&claset.Dataset)
+	if e != nil {
+		t.Fatal(e)
+	}
+
+	special := tabula.Row{
+		tabula.NewRecordInt(math.MinInt64),
+		tabula.NewRecordNull(tabula.TReal),
+		tabula.NewRecordString(""),
+	}
+	claset.PushRow(&special)
+
+	return claset
+}
+
+func TestColumnarRead(t *testing.T) {
+	claset := createColumnarClaset(t)
+
+	var out bytes.Buffer
+
+	e := tabula.WriteColumnar(&out, claset)
+	if e != nil {
+		t.Fatal(e)
+	}
+
+	modes := []int{
+		tabula.DatasetModeRows,
+		tabula.DatasetModeColumns,
+		tabula.DatasetModeMatrix,
+	}
+
+	for _, mode := range modes {
+		reader, e := tabula.NewColumnarReader(
+			bytes.NewReader(out.Bytes()), int64(out.Len()))
+		if e != nil {
+			t.Fatal(e)
+		}
+
+		assert(t, claset.Len(), reader.Len(), true)
+		assert(t, datasetNames, reader.GetColumnsName(), true)
+		assert(t, datasetTypes, reader.GetColumnsType(), true)
+
+		got := tabula.NewClaset(mode, nil, nil)
+
+		e = reader.Read(got)
+		if e != nil {
+			t.Fatal(e)
+		}
+
+		assert(t, mode, got.GetMode(), true)
+		assert(t, 2, got.GetClassIndex(), true)
+		assert(t, claset.Columns[1].Flag, got.Columns[1].Flag, true)
+		assert(t, claset.GetClassValueSpace(),
+			got.GetClassValueSpace(), true)
+		assert(t, claset.GetDataAsRows(), got.GetDataAsRows(), true)
+	}
+}
+
+func TestColumnarMeta(t *testing.T) {
+	ds := tabula.NewDataset(tabula.DatasetModeColumns, []int{
+		tabula.TTime, tabula.TString, tabula.TDecimal,
+	}, []string{"at", "class", "amount"})
+
+	ds.Columns[0].TimeFormat = &tabula.TimeFormat{
+		Layouts:  []string{"02/01/2006 15:04"},
+		Location: time.UTC,
+	}
+	ds.Columns[1].ValueSpace = []string{"a", "b"}
+	ds.Columns[1].MissingValues = []string{"NA"}
+	ds.Columns[1].Categorical = true
+	ds.Columns[1].CategoryPolicy = tabula.CategoryExtend
+	ds.Columns[2].Scale = 2
+
+	at := time.Date(2017, 12, 31, 23, 59, 0, 0,
+		time.FixedZone("", 7*3600))
+
+	ds.PushRow(&tabula.Row{
+		tabula.NewRecordTime(at),
+		tabula.NewRecordString("c"),
+		tabula.NewRecordDecimal(tabula.Decimal{Value: 150, Scale: 2}),
+	})
+	ds.PushRow(&tabula.Row{
+		tabula.NewRecordTime(at.UTC()),
+		tabula.NewRecordString("a"),
+		tabula.NewRecordDecimal(tabula.Decimal{Value: -5, Scale: 2}),
+	})
+
+	var out bytes.Buffer
+
+	e := tabula.WriteColumnar(&out, ds)
+	if e != nil {
+		t.Fatal(e)
+	}
+
+	reader, e := tabula.NewColumnarReader(bytes.NewReader(out.Bytes()),
+		int64(out.Len()))
+	if e != nil {
+		t.Fatal(e)
+	}
+
+	got := tabula.NewDataset(tabula.DatasetModeColumns, nil, nil)
+
+	e = reader.Read(got)
+	if e != nil {
+		t.Fatal(e)
+	}
+
+	assert(t, ds, got, true)
+	assert(t, "2017-12-31T23:59:00+07:00",
+		got.Columns[0].Records[0].String(), true)
+	assert(t, 2, got.Columns[1].Records[0].Code(), true)
+
+	got.Columns[1].SetValueAt(1, "NA")
+
+	assert(t, []int{1}, got.Columns[1].NullIndexes(), true)
+}
+
+func TestColumnarReadColumn(t *testing.T) {
+	claset := createColumnarClaset(t)
+
+	var out bytes.Buffer
+
+	e := tabula.WriteColumnar(&out, claset)
+	if e != nil {
+		t.Fatal(e)
+	}
+
+	reader, e := tabula.NewColumnarReader(bytes.NewReader(out.Bytes()),
+		int64(out.Len()))
+	if e != nil {
+		t.Fatal(e)
+	}
+
+	col, e := reader.ReadColumnByName("real")
+	if e != nil {
+		t.Fatal(e)
+	}
+
+	assert(t, "real", col.Name, true)
+	assert(t, tabula.TReal, col.Type, true)
+	assert(t, 1, col.Flag, true)
+
+	claset.TransposeToColumns()
+
+	assert(t, claset.Columns[1].Records, col.Records, true)
+
+	_, e = reader.ReadColumnByName("unknown")
+
+	assert(t, tabula.ErrColIdxOutOfRange, e, true)
+
+	// Read only the class column.
+	got := tabula.NewClaset(tabula.DatasetModeColumns, nil, nil)
+
+	e = reader.ReadColumns(got, []int{2})
+	if e != nil {
+		t.Fatal(e)
+	}
+
+	assert(t, 0, got.GetClassIndex(), true)
+	assert(t, claset.GetClassAsStrings(), got.GetClassAsStrings(), true)
+
+	// Read without class column.
+	e = reader.ReadColumns(got, []int{0, 1})
+	if e != nil {
+		t.Fatal(e)
+	}
+
+	assert(t, -1, got.GetClassIndex(), true)
+	assert(t, []string{"int", "real"}, got.GetColumnsName(), true)
+}
+
+func TestColumnarInvalid(t *testing.T) {
+	inputs := [][]byte{
+		[]byte("TBLCOL01"),
+		[]byte("TBLCOL01\x00\x00\x00\x00\x00\x00\x00\x00TBLCOL02"),
+		[]byte("TBLCOL01\xff\x00\x00\x00\x00\x00\x00\x00TBLCOL01"),
+		[]byte("TBLCOL01\x01\x00\x01\x08\x00\x00\x00\x00\x00\x00\x00TBLCOL01"),
+		// Invalid leading magic.
+		[]byte("TBLCOLXX\x00\x01\x00\x08\x00\x00\x00\x00\x00\x00\x00TBLCOL01"),
+		// Number of rows is out of range.
+		[]byte("TBLCOL01\xff\xff\xff\xff\xff\xff\xff\xff\xff\x01\x01\x00" +
+			"\x08\x00\x00\x00\x00\x00\x00\x00TBLCOL01"),
+		// Number of rows is larger than column block.
+		[]byte("TBLCOL01\x00\x05\x01\x01\x01a\x02\x00\x00\x08\x01" +
+			"\x09\x00\x00\x00\x00\x00\x00\x00TBLCOL01"),
+	}
+
+	for _, input := range inputs {
+		_, e := tabula.NewColumnarReader(bytes.NewReader(input),
+			int64(len(input)))
+
+		assert(t, tabula.ErrInvalidColumnar, e, true)
+	}
+}
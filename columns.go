@@ -33,6 +33,7 @@ func (cols *Columns) Reset() {
 //
 // SetTypes of each column. The length of type must be equal with the number of
 // column, otherwise it will used the minimum length between types or columns.
+// Column that is changed to TDecimal use DecimalAutoScale.
 //
 func (cols *Columns) SetTypes(types []int) {
 	typeslen := len(types)
@@ -44,10 +45,29 @@ func (cols *Columns) SetTypes(types []int) {
 	}
 
 	for x := 0; x < minlen; x++ {
+		if types[x] == TDecimal && (*cols)[x].Type != TDecimal {
+			(*cols)[x].Scale = DecimalAutoScale
+		}
 		(*cols)[x].Type = types[x]
 	}
 }
 
+//
+// SetScales set the scale of each column. The length of scales must be equal
+// with the number of column, otherwise it will used the minimum length
+// between scales or columns.
+//
+func (cols *Columns) SetScales(scales []int) {
+	minlen := len(scales)
+	if len(*cols) < minlen {
+		minlen = len(*cols)
+	}
+
+	for x := 0; x < minlen; x++ {
+		(*cols)[x].Scale = scales[x]
+	}
+}
+
 //
 // RandomPick column in columns until n item and return it like its has been
 // shuffled.  If duplicate is true, column that has been picked can be picked up
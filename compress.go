@@ -0,0 +1,231 @@
+// Copyright 2017 M. Shulhan <ms@kilabit.info>. All rights reserved.
+// Use of this source code is governed by a BSD-style license that can be found
+// in the LICENSE file.
+
+package tabula
+
+import (
+	"bufio"
+	"bytes"
+	"compress/bzip2"
+	"compress/flate"
+	"compress/gzip"
+	"compress/zlib"
+	"io"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+//
+// compressReadCloser wrap the decompressed stream, and close all of the
+// underlying decompressor and file.
+//
+type compressReadCloser struct {
+	io.Reader
+	closers []io.Closer
+}
+
+//
+// Close all the underlying closers, in order.
+//
+func (rc *compressReadCloser) Close() (e error) {
+	for _, c := range rc.closers {
+		errClose := c.Close()
+		if e == nil {
+			e = errClose
+		}
+	}
+	return
+}
+
+//
+// compressWriteCloser wrap the compressor, and close and flush the
+// compressor before closing the file.
+//
+type compressWriteCloser struct {
+	io.Writer
+	closers []io.Closer
+}
+
+//
+// Close all the underlying closers, in order.
+//
+func (wc *compressWriteCloser) Close() (e error) {
+	for _, c := range wc.closers {
+		errClose := c.Close()
+		if e == nil {
+			e = errClose
+		}
+	}
+	return
+}
+
+//
+// OpenFile open `file` for reading. If the file content is compressed, the
+// returned reader will decompress it transparently.
+//
+// Gzip, bzip2, and zlib is detected from the magic bytes in the beginning
+// of file. Zlib with uncommon header and raw deflate, which does not have
+// magic bytes, is detected from file extension: ".zz" or ".zlib" for zlib,
+// and ".deflate" for raw deflate.
+//
+// If file is not compressed, the returned reader is *os.File.
+//
+func OpenFile(file string) (rc io.ReadCloser, e error) {
+	f, e := os.Open(file)
+	if e != nil {
+		return nil, e
+	}
+
+	rc, e = newDecompressReader(f, f, strings.ToLower(filepath.Ext(file)))
+	if e != nil {
+		_ = f.Close()
+		return nil, e
+	}
+
+	return rc, nil
+}
+
+//
+// NewDecompressReader return the reader that decompress `r` if its content
+// is compressed with gzip, bzip2, or zlib, detected from its magic bytes.
+// If content is not compressed, the returned reader read `r` as is.
+//
+// Closing the returned reader does not close `r`.
+//
+func NewDecompressReader(r io.Reader) (rc io.ReadCloser, e error) {
+	return newDecompressReader(r, nil, "")
+}
+
+//
+// newDecompressReader detect the compression of `r` from magic bytes or
+// from file extension `ext`, and wrap it with decompressor. The `closer`
+// will be closed after the decompressor when the returned reader is closed.
+//
+func newDecompressReader(r io.Reader, closer io.Closer, ext string) (
+	rc io.ReadCloser, e error,
+) {
+	br := bufio.NewReader(r)
+	magic, _ := br.Peek(4)
+
+	var dec io.Reader
+
+	switch {
+	case bytes.HasPrefix(magic, []byte{0x1f, 0x8b}):
+		dec, e = gzip.NewReader(br)
+	case len(magic) == 4 && bytes.HasPrefix(magic, []byte("BZh")) &&
+		magic[3] >= '1' && magic[3] <= '9':
+		dec = bzip2.NewReader(br)
+	case isZlibMagic(magic) || ext == ".zz" || ext == ".zlib":
+		dec, e = zlib.NewReader(br)
+	case ext == ".deflate":
+		dec = flate.NewReader(br)
+	default:
+		if f, ok := r.(*os.File); ok && closer == f {
+			// Use the file directly, so caller can use it as
+			// io.ReaderAt, unless its not seekable.
+			_, errSeek := f.Seek(0, io.SeekStart)
+			if errSeek == nil {
+				return f, nil
+			}
+		}
+		dec = br
+	}
+	if e != nil {
+		return nil, e
+	}
+
+	crc := &compressReadCloser{
+		Reader: dec,
+	}
+	if c, ok := dec.(io.Closer); ok {
+		crc.closers = append(crc.closers, c)
+	}
+	if closer != nil {
+		crc.closers = append(crc.closers, closer)
+	}
+
+	return crc, nil
+}
+
+//
+// isZlibMagic return true if `magic` is zlib header with default window
+// size and one of the common compression level.
+//
+func isZlibMagic(magic []byte) bool {
+	if len(magic) < 2 || magic[0] != 0x78 {
+		return false
+	}
+	switch magic[1] {
+	case 0x01, 0x9c, 0xda:
+		return true
+	}
+	return false
+}
+
+//
+// CreateFile create or truncate `file` for writing. If the file extension is
+// ".gz" or ".gzip", the content will be compressed with gzip; if its ".zz"
+// or ".zlib", it will be compressed with zlib. Otherwise, the returned writer
+// is *os.File.
+//
+// The returned writer must be closed to flush the compressed data.
+//
+func CreateFile(file string) (wc io.WriteCloser, e error) {
+	f, e := os.Create(file)
+	if e != nil {
+		return nil, e
+	}
+
+	var enc io.WriteCloser
+
+	switch strings.ToLower(filepath.Ext(file)) {
+	case ".gz", ".gzip":
+		enc = gzip.NewWriter(f)
+	case ".zz", ".zlib":
+		enc = zlib.NewWriter(f)
+	default:
+		return f, nil
+	}
+
+	return &compressWriteCloser{
+		Writer:  enc,
+		closers: []io.Closer{enc, f},
+	}, nil
+}
+
+//
+// openFileAt open `file` for random access. If the file is compressed, all
+// of its decompressed content is loaded into memory.
+//
+func openFileAt(file string) (r io.ReaderAt, size int64, closer io.Closer,
+	e error,
+) {
+	rc, e := OpenFile(file)
+	if e != nil {
+		return nil, 0, nil, e
+	}
+
+	if f, ok := rc.(*os.File); ok {
+		fi, e := f.Stat()
+		if e != nil {
+			_ = f.Close()
+			return nil, 0, nil, e
+		}
+		return f, fi.Size(), f, nil
+	}
+
+	content, e := ioutil.ReadAll(rc)
+
+	errClose := rc.Close()
+	if e == nil {
+		e = errClose
+	}
+	if e != nil {
+		return nil, 0, nil, e
+	}
+
+	return bytes.NewReader(content), int64(len(content)), nil, nil
+}
@@ -0,0 +1,190 @@
+// Copyright 2017 M. Shulhan <ms@kilabit.info>. All rights reserved.
+// Use of this source code is governed by a BSD-style license that can be found
+// in the LICENSE file.
+
+package tabula_test
+
+import (
+	"bytes"
+	"compress/flate"
+	"compress/gzip"
+	"github.com/shuLhan/tabula"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestOpenFileBzip2(t *testing.T) {
+	exp := tabula.Claset{}
+	got := tabula.Claset{}
+
+	e := newTestDSVReader().ReadFile("testdata/claset.csv", &exp)
+	if e != nil {
+		t.Fatal(e)
+	}
+
+	e = newTestDSVReader().ReadFile("testdata/claset.csv.bz2", &got)
+	if e != nil {
+		t.Fatal(e)
+	}
+
+	assert(t, exp.String(), got.String(), true)
+}
+
+func TestCreateFile(t *testing.T) {
+	dir, e := ioutil.TempDir("", "tabula")
+	if e != nil {
+		t.Fatal(e)
+	}
+	defer os.RemoveAll(dir)
+
+	ds := tabula.NewDataset(tabula.DatasetModeRows, datasetTypes,
+		datasetNames)
+	e = populateWithRows(ds)
+	if e != nil {
+		t.Fatal(e)
+	}
+
+	var exp bytes.Buffer
+
+	e = tabula.NewDSVWriter().Write(&exp, ds)
+	if e != nil {
+		t.Fatal(e)
+	}
+
+	magics := map[string][]byte{
+		"data.csv":      exp.Bytes()[:2],
+		"data.csv.gz":   {0x1f, 0x8b},
+		"data.csv.zlib": {0x78, 0x9c},
+	}
+
+	for name, magic := range magics {
+		file := filepath.Join(dir, name)
+
+		e = tabula.NewDSVWriter().WriteFile(file, ds)
+		if e != nil {
+			t.Fatal(e)
+		}
+
+		raw, e := ioutil.ReadFile(file)
+		if e != nil {
+			t.Fatal(e)
+		}
+
+		assert(t, magic, raw[:2], true)
+
+		got := tabula.NewDataset(tabula.DatasetModeRows, datasetTypes,
+			datasetNames)
+
+		e = tabula.NewDSVReader().ReadFile(file, got)
+		if e != nil {
+			t.Fatal(e)
+		}
+
+		assert(t, ds.Rows.String(), got.Rows.String(), true)
+	}
+}
+
+func TestOpenFileDeflate(t *testing.T) {
+	dir, e := ioutil.TempDir("", "tabula")
+	if e != nil {
+		t.Fatal(e)
+	}
+	defer os.RemoveAll(dir)
+
+	exp := "a,b\n1,x\n"
+
+	var buf bytes.Buffer
+
+	fw, _ := flate.NewWriter(&buf, flate.DefaultCompression)
+	_, _ = fw.Write([]byte(exp))
+	_ = fw.Close()
+
+	file := filepath.Join(dir, "data.csv.deflate")
+
+	e = ioutil.WriteFile(file, buf.Bytes(), 0600)
+	if e != nil {
+		t.Fatal(e)
+	}
+
+	rc, e := tabula.OpenFile(file)
+	if e != nil {
+		t.Fatal(e)
+	}
+
+	got, e := ioutil.ReadAll(rc)
+	if e != nil {
+		t.Fatal(e)
+	}
+
+	assert(t, nil, rc.Close(), true)
+	assert(t, exp, string(got), true)
+}
+
+func TestNewDecompressReader(t *testing.T) {
+	exp := "a,b\n1,x\n"
+
+	var gz bytes.Buffer
+
+	gw := gzip.NewWriter(&gz)
+	_, _ = gw.Write([]byte(exp))
+	_ = gw.Close()
+
+	inputs := [][]byte{
+		[]byte(exp),
+		gz.Bytes(),
+	}
+
+	for _, in := range inputs {
+		rc, e := tabula.NewDecompressReader(bytes.NewReader(in))
+		if e != nil {
+			t.Fatal(e)
+		}
+
+		got, e := ioutil.ReadAll(rc)
+		if e != nil {
+			t.Fatal(e)
+		}
+
+		assert(t, nil, rc.Close(), true)
+		assert(t, exp, string(got), true)
+	}
+}
+
+func TestOpenColumnarFileCompressed(t *testing.T) {
+	dir, e := ioutil.TempDir("", "tabula")
+	if e != nil {
+		t.Fatal(e)
+	}
+	defer os.RemoveAll(dir)
+
+	ds := tabula.NewDataset(tabula.DatasetModeRows, datasetTypes,
+		datasetNames)
+	e = populateWithRows(ds)
+	if e != nil {
+		t.Fatal(e)
+	}
+
+	file := filepath.Join(dir, "data.col.gz")
+
+	e = tabula.WriteColumnarFile(file, ds)
+	if e != nil {
+		t.Fatal(e)
+	}
+
+	reader, e := tabula.OpenColumnarFile(file)
+	if e != nil {
+		t.Fatal(e)
+	}
+
+	got := &tabula.Dataset{}
+
+	e = reader.Read(got)
+	if e != nil {
+		t.Fatal(e)
+	}
+
+	assert(t, nil, reader.Close(), true)
+	assert(t, ds.Rows.String(), got.Rows.String(), true)
+}
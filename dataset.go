@@ -5,6 +5,8 @@
 package tabula
 
 import (
+	"bytes"
+	"encoding/json"
 	"errors"
 	"math"
 )
@@ -79,9 +81,14 @@ func (dataset *Dataset) Clone() interface{} {
 
 	for _, col := range dataset.Columns {
 		newcol := Column{
-			Type:       col.Type,
-			Name:       col.Name,
-			ValueSpace: col.ValueSpace,
+			Type:           col.Type,
+			Name:           col.Name,
+			ValueSpace:     col.ValueSpace,
+			TimeFormat:     col.TimeFormat,
+			MissingValues:  col.MissingValues,
+			Scale:          col.Scale,
+			Categorical:    col.Categorical,
+			CategoryPolicy: col.CategoryPolicy,
 		}
 		clone.PushColumn(newcol)
 	}
@@ -191,6 +198,20 @@ func (dataset *Dataset) SetColumnsType(types []int) {
 	dataset.Columns.SetTypes(types)
 }
 
+//
+// SetColumnsScale set the scale of TDecimal columns, see Column.Scale. For
+// example, to create dataset with decimal column that has two digits after
+// decimal point,
+//
+//	ds := tabula.NewDataset(tabula.DatasetModeRows,
+//		[]int{tabula.TString, tabula.TDecimal},
+//		[]string{"item", "price"})
+//	ds.SetColumnsScale([]int{0, 2})
+//
+func (dataset *Dataset) SetColumnsScale(scales []int) {
+	dataset.Columns.SetScales(scales)
+}
+
 //
 // GetColumnTypeAt return type of column in index `colidx` in dataset.
 //
@@ -264,6 +285,9 @@ func (dataset *Dataset) AddColumn(tipe int, name string, vs []string) {
 		Name:       name,
 		ValueSpace: vs,
 	}
+	if tipe == TDecimal {
+		col.Scale = DecimalAutoScale
+	}
 	dataset.PushColumn(col)
 }
 
@@ -499,11 +523,13 @@ func (dataset *Dataset) TransposeToRows() {
 }
 
 //
-// PushRow save the data, which is already in row object, to Rows.
+// PushRow save the data, which is already in row object, to Rows. Each
+// record is encoded or rescaled using its column, as in Column.PushBack.
 //
 func (dataset *Dataset) PushRow(row *Row) {
 	switch dataset.GetMode() {
 	case DatasetModeRows:
+		dataset.normalizeRow(row)
 		dataset.Rows = append(dataset.Rows, row)
 	case DatasetModeColumns:
 		dataset.PushRowToColumns(row)
@@ -513,6 +539,18 @@ func (dataset *Dataset) PushRow(row *Row) {
 	}
 }
 
+//
+// normalizeRow encode or rescale each record in `row` using its column.
+//
+func (dataset *Dataset) normalizeRow(row *Row) {
+	for x, rec := range *row {
+		if x >= len(dataset.Columns) {
+			return
+		}
+		dataset.Columns[x].normalize(rec)
+	}
+}
+
 //
 // PushRowToColumns push each data in Row to Columns.
 //
@@ -745,3 +783,521 @@ func (dataset *Dataset) DeleteRow(i int) (row *Row) {
 
 	return row
 }
+
+//
+// CountNull return number of null record in each column.
+//
+func (dataset *Dataset) CountNull() (counts []int) {
+	ncol := dataset.GetNColumn()
+	nrow := dataset.Len()
+	counts = make([]int, ncol)
+
+	for r := 0; r < nrow; r++ {
+		for x := 0; x < ncol; x++ {
+			rec := getRecordAt(dataset, r, x)
+			if rec == nil || rec.IsNull() {
+				counts[x]++
+			}
+		}
+	}
+
+	return counts
+}
+
+//
+// SumDecimal return the exact sum of values in column `idx` as decimal with
+// column scale, using the dataset mode, see Column.SumDecimal. If `idx` is
+// out of range, it will return ErrColIdxOutOfRange.
+//
+func (dataset *Dataset) SumDecimal(idx int) (sum Decimal, e error) {
+	if idx < 0 || idx >= len(dataset.Columns) {
+		return sum, ErrColIdxOutOfRange
+	}
+
+	sum.Scale = dataset.Columns[idx].decimalScale()
+	nrow := dataset.Len()
+
+	for r := 0; r < nrow; r++ {
+		sum, e = addDecimal(sum, getRecordAt(dataset, r, idx))
+		if e != nil {
+			return Decimal{}, e
+		}
+	}
+
+	return sum, nil
+}
+
+//
+// NullRows return the index of rows that contain at least one null record.
+//
+func (dataset *Dataset) NullRows() (idxs []int) {
+	ncol := dataset.GetNColumn()
+	nrow := dataset.Len()
+
+	for r := 0; r < nrow; r++ {
+		for x := 0; x < ncol; x++ {
+			rec := getRecordAt(dataset, r, x)
+			if rec == nil || rec.IsNull() {
+				idxs = append(idxs, r)
+				break
+			}
+		}
+	}
+
+	return idxs
+}
+
+//
+// DropNullRows delete all rows that contain at least one null record, and
+// return the number of deleted rows.
+//
+func (dataset *Dataset) DropNullRows() int {
+	idxs := dataset.NullRows()
+	if len(idxs) == 0 {
+		return 0
+	}
+
+	isNull := make([]bool, dataset.Len())
+	for _, idx := range idxs {
+		isNull[idx] = true
+	}
+
+	if dataset.Mode != DatasetModeColumns {
+		rows := dataset.Rows[:0]
+		for r, row := range dataset.Rows {
+			if !isNull[r] {
+				rows = append(rows, row)
+			}
+		}
+		dataset.Rows = rows
+	}
+
+	if dataset.Mode != DatasetModeRows {
+		for x := range dataset.Columns {
+			recs := dataset.Columns[x].Records
+			n := 0
+			for r, rec := range recs {
+				if r < len(isNull) && isNull[r] {
+					continue
+				}
+				recs[n] = rec
+				n++
+			}
+			dataset.Columns[x].Records = recs[:n]
+		}
+	}
+
+	return len(idxs)
+}
+
+//
+// datasetJSON define the JSON representation of dataset.
+//
+type datasetJSON struct {
+	Mode    int
+	Columns []Column
+	Rows    []json.RawMessage
+}
+
+//
+// MarshalJSON convert dataset into JSON object which contain the mode,
+// columns metadata without records, and all rows, independent of dataset
+// mode.
+//
+func (dataset *Dataset) MarshalJSON() ([]byte, error) {
+	out, e := dataset.toJSON()
+	if e != nil {
+		return nil, e
+	}
+	return json.Marshal(out)
+}
+
+//
+// toJSON convert dataset into its JSON representation.
+//
+func (dataset *Dataset) toJSON() (out *datasetJSON, e error) {
+	nrow := dataset.Len()
+	ncol := dataset.GetNColumn()
+
+	out = &datasetJSON{
+		Mode:    dataset.Mode,
+		Columns: make([]Column, len(dataset.Columns)),
+		Rows:    make([]json.RawMessage, nrow),
+	}
+
+	for x, col := range dataset.Columns {
+		out.Columns[x] = Column{
+			Name:           col.Name,
+			Type:           col.Type,
+			Flag:           col.Flag,
+			ValueSpace:     col.ValueSpace,
+			TimeFormat:     col.TimeFormat,
+			MissingValues:  col.MissingValues,
+			Scale:          col.Scale,
+			Categorical:    col.Categorical,
+			CategoryPolicy: col.CategoryPolicy,
+		}
+	}
+
+	row := make(Row, ncol)
+
+	for r := 0; r < nrow; r++ {
+		for x := 0; x < ncol; x++ {
+			row[x] = getRecordAt(dataset, r, x)
+		}
+		out.Rows[r], e = row.appendJSON(nil)
+		if e != nil {
+			return nil, e
+		}
+	}
+
+	return out, nil
+}
+
+//
+// UnmarshalJSON set the dataset from JSON object. Mode and columns is
+// replaced only if its exist in JSON. Each row is converted using the column
+// type and pushed into dataset based on mode.
+//
+func (dataset *Dataset) UnmarshalJSON(b []byte) (e error) {
+	in := datasetJSON{
+		Mode: dataset.Mode,
+	}
+
+	e = json.Unmarshal(b, &in)
+	if e != nil {
+		return e
+	}
+
+	return dataset.fromJSON(&in)
+}
+
+//
+// fromJSON set the dataset from its JSON representation.
+//
+func (dataset *Dataset) fromJSON(in *datasetJSON) (e error) {
+	dataset.Mode = in.Mode
+	if in.Columns != nil {
+		dataset.Columns = in.Columns
+	}
+	if in.Rows == nil {
+		return nil
+	}
+
+	dataset.Rows = nil
+	types := dataset.GetColumnsType()
+
+	for _, raw := range in.Rows {
+		row := make(Row, 0)
+
+		e = row.unmarshalJSON(raw, types)
+		if e != nil {
+			return e
+		}
+
+		dataset.PushRow(&row)
+	}
+
+	return nil
+}
+
+//
+// MarshalBinary convert dataset into binary, which contain the mode,
+// columns metadata, and all rows, independent of dataset mode.
+//
+func (dataset *Dataset) MarshalBinary() ([]byte, error) {
+	return dataset.appendBinary(nil), nil
+}
+
+//
+// UnmarshalBinary set the dataset from binary that is created by
+// MarshalBinary. Each row is pushed into dataset based on the mode, so in
+// matrix mode the record in rows and columns is shared.
+//
+func (dataset *Dataset) UnmarshalBinary(b []byte) (e error) {
+	br := bytes.NewReader(b)
+
+	e = dataset.readBinary(br)
+	if e != nil {
+		return
+	}
+	if br.Len() > 0 {
+		return ErrInvalidBinary
+	}
+
+	return nil
+}
+
+//
+// GobEncode implement the gob.GobEncoder interface, see MarshalBinary.
+//
+func (dataset *Dataset) GobEncode() ([]byte, error) {
+	return dataset.MarshalBinary()
+}
+
+//
+// GobDecode implement the gob.GobDecoder interface, see UnmarshalBinary.
+//
+func (dataset *Dataset) GobDecode(b []byte) error {
+	return dataset.UnmarshalBinary(b)
+}
+
+//
+// appendBinary convert dataset into binary and append it to `b`.
+//
+func (dataset *Dataset) appendBinary(b []byte) []byte {
+	b = appendVarint(b, int64(dataset.Mode))
+
+	b = appendUvarint(b, uint64(len(dataset.Columns)))
+	for x := range dataset.Columns {
+		b = dataset.Columns[x].appendBinaryMeta(b)
+	}
+
+	nrow := dataset.Len()
+	ncol := dataset.GetNColumn()
+
+	b = appendUvarint(b, uint64(nrow))
+	b = appendUvarint(b, uint64(ncol))
+
+	for r := 0; r < nrow; r++ {
+		for x := 0; x < ncol; x++ {
+			rec := getRecordAt(dataset, r, x)
+			if rec == nil {
+				rec = NewRecord()
+			}
+			b = rec.appendBinary(b)
+		}
+	}
+
+	return b
+}
+
+//
+// readBinary read dataset from `br`.
+//
+func (dataset *Dataset) readBinary(br *bytes.Reader) (e error) {
+	mode, e := readVarint(br, ErrInvalidBinary)
+	if e != nil {
+		return
+	}
+
+	ncol, e := readLength(br, ErrInvalidBinary)
+	if e != nil {
+		return
+	}
+
+	cols := make(Columns, ncol)
+	for x := range cols {
+		e = cols[x].readBinaryMeta(br, ErrInvalidBinary)
+		if e != nil {
+			return
+		}
+	}
+
+	nrow, e := readUvarint(br, ErrInvalidBinary)
+	if e != nil {
+		return
+	}
+	ncol, e = readLength(br, ErrInvalidBinary)
+	if e != nil {
+		return
+	}
+	if ncol > 0 && nrow > uint64(br.Len()) {
+		return ErrInvalidBinary
+	}
+
+	dataset.Mode = int(mode)
+	dataset.Columns = cols
+	dataset.Rows = nil
+
+	for r := uint64(0); r < nrow; r++ {
+		row := make(Row, ncol)
+		for x := range row {
+			row[x], e = readRecordBinary(br)
+			if e != nil {
+				return
+			}
+		}
+		dataset.PushRow(&row)
+	}
+
+	return nil
+}
+
+//
+// MarshalMsgpack convert dataset into MessagePack map which contain the
+// mode, columns metadata without records, and all rows as array of record
+// values, independent of dataset mode.
+//
+func (dataset *Dataset) MarshalMsgpack() ([]byte, error) {
+	return dataset.appendMsgpack(nil, 0), nil
+}
+
+//
+// UnmarshalMsgpack set the dataset from MessagePack map that is created by
+// MarshalMsgpack. Mode and columns is replaced only if its exist in map.
+// Each row is pushed into dataset based on mode, where nil value is converted
+// to null based on the column type, see NewRecordNull.
+//
+func (dataset *Dataset) UnmarshalMsgpack(b []byte) (e error) {
+	br := bytes.NewReader(b)
+
+	e = dataset.readMsgpack(br, nil)
+	if e != nil {
+		return
+	}
+	if br.Len() > 0 {
+		return ErrInvalidMsgpack
+	}
+
+	return nil
+}
+
+//
+// appendMsgpack convert dataset into MessagePack map and append it to `b`.
+// The map header will include `nextra` pairs that will be appended later by
+// caller.
+//
+func (dataset *Dataset) appendMsgpack(b []byte, nextra int) []byte {
+	b = appendMsgpackMap(b, 3+nextra)
+
+	b = appendMsgpackString(b, "Mode")
+	b = appendMsgpackInt(b, int64(dataset.Mode))
+
+	b = appendMsgpackString(b, "Columns")
+	b = appendMsgpackArray(b, len(dataset.Columns))
+	for x := range dataset.Columns {
+		b = dataset.Columns[x].appendMsgpackMeta(b)
+	}
+
+	nrow := dataset.Len()
+	ncol := dataset.GetNColumn()
+	row := make(Row, ncol)
+
+	b = appendMsgpackString(b, "Rows")
+	b = appendMsgpackArray(b, nrow)
+
+	for r := 0; r < nrow; r++ {
+		for x := 0; x < ncol; x++ {
+			row[x] = getRecordAt(dataset, r, x)
+		}
+		b = row.appendMsgpack(b)
+	}
+
+	return b
+}
+
+//
+// readMsgpack read dataset from MessagePack map in `br`. Key that is not
+// known by dataset is passed to `readKey`, or ignored if its nil.
+//
+func (dataset *Dataset) readMsgpack(br *bytes.Reader,
+	readKey func(key string) error,
+) (e error) {
+	n, e := readMsgpackMap(br)
+	if e != nil {
+		return
+	}
+	if n < 0 {
+		return ErrInvalidMsgpack
+	}
+
+	var rows []Row
+
+	for ; n > 0; n-- {
+		key, e := readMsgpackString(br)
+		if e != nil {
+			return e
+		}
+
+		switch key {
+		case "Mode":
+			var mode int64
+
+			mode, e = readMsgpackInt(br)
+			dataset.Mode = int(mode)
+		case "Columns":
+			var cols Columns
+
+			cols, e = readColumnsMsgpack(br)
+			if cols != nil {
+				dataset.Columns = cols
+			}
+		case "Rows":
+			rows, e = readRowsMsgpack(br)
+		default:
+			if readKey != nil {
+				e = readKey(key)
+			} else {
+				e = skipMsgpack(br)
+			}
+		}
+		if e != nil {
+			return e
+		}
+	}
+
+	if rows == nil {
+		return nil
+	}
+
+	dataset.Rows = nil
+	ncol := dataset.GetNColumn()
+
+	for x := range rows {
+		if ncol > 0 && len(rows[x]) != ncol {
+			return ErrMisColLength
+		}
+		// Nil value is converted to null based on the column type.
+		for y, rec := range rows[x] {
+			if y < len(dataset.Columns) && rec.IsNil() {
+				rows[x][y] = NewRecordNull(dataset.Columns[y].Type)
+			}
+		}
+		dataset.PushRow(&rows[x])
+	}
+
+	return nil
+}
+
+//
+// readColumnsMsgpack read MessagePack array of columns metadata from `br`.
+// Nil array is returned as nil columns.
+//
+func readColumnsMsgpack(br *bytes.Reader) (cols Columns, e error) {
+	n, e := readMsgpackArray(br)
+	if e != nil || n < 0 {
+		return nil, e
+	}
+
+	cols = make(Columns, n)
+	for x := range cols {
+		e = cols[x].readMsgpackMeta(br)
+		if e != nil {
+			return nil, e
+		}
+	}
+
+	return cols, nil
+}
+
+//
+// readRowsMsgpack read MessagePack array of rows from `br`. Nil array is
+// returned as nil rows.
+//
+func readRowsMsgpack(br *bytes.Reader) (rows []Row, e error) {
+	n, e := readMsgpackArray(br)
+	if e != nil || n < 0 {
+		return nil, e
+	}
+
+	rows = make([]Row, n)
+	for x := range rows {
+		rows[x], e = readRowMsgpack(br)
+		if e != nil {
+			return nil, e
+		}
+	}
+
+	return rows, nil
+}
@@ -5,9 +5,14 @@
 package tabula_test
 
 import (
+	"bytes"
+	"encoding/gob"
+	"encoding/json"
 	"fmt"
 	"github.com/shuLhan/tabula"
+	"strings"
 	"testing"
+	"time"
 )
 
 var datasetRows = [][]string{
@@ -164,6 +169,93 @@ func TestSplitRowsByCategorical(t *testing.T) {
 	assert(t, exp, got, true)
 }
 
+func TestSplitRowsByValueBool(t *testing.T) {
+	ds := tabula.NewDataset(tabula.DatasetModeColumns,
+		[]int{tabula.TInteger, tabula.TBool}, []string{"id", "ok"})
+
+	for x, v := range []string{"yes", "no", "true", "0"} {
+		rec, e := tabula.NewRecordBy(v, tabula.TBool)
+		if e != nil {
+			t.Fatal(e)
+		}
+		ds.PushRow(&tabula.Row{tabula.NewRecordInt(int64(x)), rec})
+	}
+
+	ds.Columns[1].SetValueByNumericAt(1, 2)
+
+	splitL, splitR, e := tabula.SplitRowsByValue(ds, 1, true)
+	if e != nil {
+		t.Fatal(e)
+	}
+
+	assert(t, "&[0 true]&[1 true]&[2 true]",
+		splitL.GetDataAsRows().String(), true)
+	assert(t, "&[3 false]", splitR.GetDataAsRows().String(), true)
+}
+
+func TestSplitRowsByTime(t *testing.T) {
+	ds := tabula.NewDataset(tabula.DatasetModeRows,
+		[]int{tabula.TInteger, tabula.TTime}, []string{"id", "at"})
+
+	for x, v := range []string{"2017-01-02", "2016-12-31", "?",
+		"2017-01-01"} {
+		rec := tabula.NewRecord()
+		if v == "?" {
+			rec.SetMissingValue(tabula.TTime)
+		} else if e := rec.SetValue(v, tabula.TTime); e != nil {
+			t.Fatal(e)
+		}
+		ds.PushRow(&tabula.Row{tabula.NewRecordInt(int64(x)), rec})
+	}
+
+	splitL, splitR, e := tabula.SplitRowsByTime(ds, 1,
+		time.Date(2017, 1, 1, 0, 0, 0, 0, time.UTC))
+	if e != nil {
+		t.Fatal(e)
+	}
+
+	assert(t, "&[1 2016-12-31T00:00:00Z]",
+		splitL.GetDataAsRows().String(), true)
+	assert(t, "&[0 2017-01-02T00:00:00Z]&[2 ?]&[3 2017-01-01T00:00:00Z]",
+		splitR.GetDataAsRows().String(), true)
+
+	_, _, e = tabula.SplitRowsByTime(ds, 0, time.Time{})
+
+	assert(t, tabula.ErrInvalidColType, e, true)
+}
+
+func TestDatasetNull(t *testing.T) {
+	input := "id,score,name\n1,NA,a\n2,1.5,\n3,2.5,c\n?,null,d\n"
+
+	reader := tabula.NewDSVReader()
+	reader.Header = true
+
+	modes := []int{
+		tabula.DatasetModeRows,
+		tabula.DatasetModeColumns,
+		tabula.DatasetModeMatrix,
+	}
+
+	for _, mode := range modes {
+		ds := tabula.NewDataset(mode, []int{tabula.TInteger,
+			tabula.TReal, tabula.TString}, nil)
+		ds.Columns[1].MissingValues = []string{"NA", "null"}
+		ds.Columns[2].MissingValues = []string{""}
+
+		e := reader.Read(strings.NewReader(input), ds)
+		if e != nil {
+			t.Fatal(e)
+		}
+
+		assert(t, []int{0, 2, 1}, ds.CountNull(), true)
+		assert(t, []int{0, 1, 3}, ds.NullRows(), true)
+		assert(t, 3, ds.DropNullRows(), true)
+		assert(t, 1, ds.Len(), true)
+		assert(t, "&[3 2.5 c]", ds.GetDataAsRows().String(), true)
+		assert(t, 0, ds.DropNullRows(), true)
+	}
+}
+
 func TestModeColumnsPushColumn(t *testing.T) {
 	dataset := tabula.NewDataset(tabula.DatasetModeColumns, nil, nil)
 
@@ -202,7 +294,9 @@ func TestModeRowsPushColumn(t *testing.T) {
 	assert(t, exp, got, true)
 
 	// Check columns
-	exp = "[{int 1 0 [] []} {real 2 0 [] []} {string 0 0 [] []}]"
+	exp = "[{int 1 0 [] <nil> [] 0 false 0 [] <nil>}" +
+		" {real 2 0 [] <nil> [] 0 false 0 [] <nil>}" +
+		" {string 0 0 [] <nil> [] 0 false 0 [] <nil>}]"
 	got = fmt.Sprint(dataset.Columns)
 
 	assert(t, exp, got, true)
@@ -362,3 +456,188 @@ func TestDeleteRow(t *testing.T) {
 		assert(t, exp, got, true)
 	}
 }
+
+func TestDatasetJSON(t *testing.T) {
+	modes := []int{
+		tabula.DatasetModeRows,
+		tabula.DatasetModeColumns,
+		tabula.DatasetModeMatrix,
+	}
+
+	for _, mode := range modes {
+		dataset := tabula.NewDataset(mode, datasetTypes, datasetNames)
+
+		e := populateWithRows(dataset)
+		if e != nil {
+			t.Fatal(e)
+		}
+
+		b, e := json.Marshal(dataset)
+		if e != nil {
+			t.Fatal(e)
+		}
+
+		got := &tabula.Dataset{}
+
+		e = json.Unmarshal(b, got)
+		if e != nil {
+			t.Fatal(e)
+		}
+
+		assert(t, dataset, got, true)
+	}
+}
+
+func TestDatasetGob(t *testing.T) {
+	modes := []int{
+		tabula.DatasetModeRows,
+		tabula.DatasetModeColumns,
+		tabula.DatasetModeMatrix,
+	}
+
+	for _, mode := range modes {
+		dataset := tabula.NewDataset(mode, datasetTypes, datasetNames)
+
+		e := populateWithRows(dataset)
+		if e != nil {
+			t.Fatal(e)
+		}
+
+		var buf bytes.Buffer
+
+		e = gob.NewEncoder(&buf).Encode(dataset)
+		if e != nil {
+			t.Fatal(e)
+		}
+
+		got := &tabula.Dataset{}
+
+		e = gob.NewDecoder(&buf).Decode(got)
+		if e != nil {
+			t.Fatal(e)
+		}
+
+		assert(t, dataset, got, true)
+
+		if mode == tabula.DatasetModeMatrix {
+			// Record in rows and columns should be shared.
+			got.Rows[0].GetRecord(0).SetInteger(100)
+
+			assert(t, int64(100), got.Columns[0].Records[0].Integer(),
+				true)
+		}
+	}
+}
+
+func TestDatasetCategorical(t *testing.T) {
+	ds := tabula.NewDataset(tabula.DatasetModeColumns,
+		[]int{tabula.TInteger, tabula.TString}, []string{"id", "class"})
+
+	class := ds.GetColumn(1)
+	class.ValueSpace = []string{"a", "b"}
+	class.Categorical = true
+	class.CategoryPolicy = tabula.CategoryExtend
+
+	for x, v := range []string{"b", "a", "c", "b"} {
+		ds.PushRow(&tabula.Row{
+			tabula.NewRecordInt(int64(x)),
+			tabula.NewRecordString(v),
+		})
+	}
+
+	assert(t, []string{"a", "b", "c"}, class.ValueSpace, true)
+	assert(t, []string{"b", "a", "c", "b"}, class.ToStringSlice(), true)
+
+	b, e := json.Marshal(ds)
+	if e != nil {
+		t.Fatal(e)
+	}
+
+	got := tabula.NewDataset(tabula.DatasetModeColumns, nil, nil)
+
+	e = json.Unmarshal(b, got)
+	if e != nil {
+		t.Fatal(e)
+	}
+
+	gotClass := got.GetColumn(1)
+
+	assert(t, true, gotClass.Categorical, true)
+	assert(t, tabula.CategoryExtend, gotClass.CategoryPolicy, true)
+	assert(t, []string{"b", "a", "c", "b"}, gotClass.ToStringSlice(), true)
+	assert(t, 2, gotClass.Records[2].Code(), true)
+
+	var buf bytes.Buffer
+
+	e = gob.NewEncoder(&buf).Encode(ds)
+	if e != nil {
+		t.Fatal(e)
+	}
+
+	got = &tabula.Dataset{}
+
+	e = gob.NewDecoder(&buf).Decode(got)
+	if e != nil {
+		t.Fatal(e)
+	}
+
+	assert(t, ds, got, true)
+	assert(t, 2, got.GetColumn(1).Records[2].Code(), true)
+
+	clone := ds.Clone().(tabula.DatasetInterface)
+	clone.PushRow(&tabula.Row{
+		tabula.NewRecordInt(4),
+		tabula.NewRecordString("d"),
+	})
+
+	assert(t, []string{"a", "b", "c"}, class.ValueSpace, true)
+	assert(t, []string{"a", "b", "c", "d"},
+		clone.GetColumn(1).ValueSpace, true)
+}
+
+func TestDatasetCategoricalRows(t *testing.T) {
+	ds := tabula.NewDataset(tabula.DatasetModeRows,
+		[]int{tabula.TInteger, tabula.TString}, []string{"id", "class"})
+
+	class := ds.GetColumn(1)
+	class.ValueSpace = []string{"a", "b"}
+	class.Categorical = true
+
+	ds.PushRow(&tabula.Row{
+		tabula.NewRecordInt(0),
+		tabula.NewRecordString("b"),
+	})
+	ds.PushRow(&tabula.Row{
+		tabula.NewRecordInt(1),
+		tabula.NewRecordString("c"),
+	})
+
+	assert(t, 1, ds.Rows[0].GetRecord(1).Code(), true)
+	assert(t, true, ds.Rows[1].GetRecord(1).IsNull(), true)
+
+	ds.Rows = nil
+
+	e := tabula.NewDSVReader().Read(strings.NewReader("1,a\n2,c\n3,b\n"),
+		ds)
+
+	re, ok := e.(*tabula.ReadError)
+	assert(t, true, ok, true)
+	assert(t, 2, re.Line, true)
+	assert(t, 2, re.Column, true)
+	assert(t, "c", re.Value, true)
+	assert(t, tabula.ErrNotInValueSpace, re.Err, true)
+	assert(t, 1, ds.Len(), true)
+	assert(t, 0, ds.Rows[0].GetRecord(1).Code(), true)
+
+	class.CategoryPolicy = tabula.CategoryExtend
+	ds.Rows = nil
+
+	e = tabula.NewDSVReader().Read(strings.NewReader("1,a\n2,c\n3,b\n"),
+		ds)
+	if e != nil {
+		t.Fatal(e)
+	}
+
+	assert(t, []string{"a", "b", "c"}, class.ValueSpace, true)
+	assert(t, 2, ds.Rows[1].GetRecord(1).Code(), true)
+}
@@ -8,6 +8,8 @@ import (
 	"encoding/json"
 	"fmt"
 	"io/ioutil"
+	"strconv"
+	"time"
 )
 
 //
@@ -120,10 +122,53 @@ func SplitRowsByNumeric(di DatasetInterface, colidx int, splitVal float64) (
 		return
 	}
 
-	if !(coltype == TInteger || coltype == TReal) {
+	if !(coltype == TInteger || coltype == TReal || coltype == TBool) {
 		return splitLess, splitGreater, ErrInvalidColType
 	}
 
+	splitLess, splitGreater = splitRowsByLess(di, func(row *Row) bool {
+		return (*row)[colidx].Float() < splitVal
+	})
+
+	return splitLess, splitGreater, nil
+}
+
+//
+// SplitRowsByTime will split the data using time threshold `splitVal` in
+// column `colidx`, which must be a TTime column. Rows with time before
+// `splitVal` is returned in `splitLess`, and the rest, including missing
+// value, in `splitGreater`.
+//
+func SplitRowsByTime(di DatasetInterface, colidx int, splitVal time.Time) (
+	splitLess DatasetInterface,
+	splitGreater DatasetInterface,
+	e error,
+) {
+	coltype, e := di.GetColumnTypeAt(colidx)
+	if e != nil {
+		return
+	}
+
+	if coltype != TTime {
+		return splitLess, splitGreater, ErrInvalidColType
+	}
+
+	splitLess, splitGreater = splitRowsByLess(di, func(row *Row) bool {
+		t := (*row)[colidx].Time()
+		return !t.IsZero() && t.Before(splitVal)
+	})
+
+	return splitLess, splitGreater, nil
+}
+
+//
+// splitRowsByLess split the rows in dataset into `splitLess`, where `isLess`
+// return true for the row, and `splitGreater` for the rest.
+//
+func splitRowsByLess(di DatasetInterface, isLess func(row *Row) bool) (
+	splitLess DatasetInterface,
+	splitGreater DatasetInterface,
+) {
 	// Should we convert the data mode back later.
 	orgmode := di.GetMode()
 
@@ -140,7 +185,7 @@ func SplitRowsByNumeric(di DatasetInterface, colidx int, splitVal float64) (
 
 	rows := di.GetRows()
 	for _, row := range *rows {
-		if (*row)[colidx].Float() < splitVal {
+		if isLess(row) {
 			splitLess.PushRow(row)
 		} else {
 			splitGreater.PushRow(row)
@@ -196,7 +241,7 @@ func SplitRowsByCategorical(di DatasetInterface, colidx int,
 		return
 	}
 
-	if coltype != TString {
+	if coltype != TString && coltype != TBool {
 		return splitIn, splitEx, ErrInvalidColType
 	}
 
@@ -244,6 +289,12 @@ func SplitRowsByCategorical(di DatasetInterface, colidx int,
 // any rows that have column value less than `value` in `splitL`, and any column
 // value greater or equal to `value` in `splitR`.
 //
+// If column type is boolean and value is bool or []string, the data is split
+// by category, where rows with the same value is returned in `splitL`.
+//
+// If column type is time and value is time.Time, the data is split by
+// SplitRowsByTime.
+//
 func SplitRowsByValue(di DatasetInterface, colidx int, value interface{}) (
 	splitL DatasetInterface,
 	splitR DatasetInterface,
@@ -254,7 +305,16 @@ func SplitRowsByValue(di DatasetInterface, colidx int, value interface{}) (
 		return
 	}
 
-	if coltype == TString {
+	boolval, isBool := value.(bool)
+	_, isStrings := value.([]string)
+	timeval, isTime := value.(time.Time)
+
+	if coltype == TTime && isTime {
+		splitL, splitR, e = SplitRowsByTime(di, colidx, timeval)
+	} else if coltype == TBool && isBool {
+		splitL, splitR, e = SplitRowsByCategorical(di, colidx,
+			[]string{strconv.FormatBool(boolval)})
+	} else if coltype == TString || (coltype == TBool && isStrings) {
 		splitL, splitR, e = SplitRowsByCategorical(di, colidx,
 			value.([]string))
 	} else {
@@ -0,0 +1,292 @@
+// Copyright 2017 M. Shulhan <ms@kilabit.info>. All rights reserved.
+// Use of this source code is governed by a BSD-style license that can be found
+// in the LICENSE file.
+
+package tabula
+
+import (
+	"errors"
+	"math"
+	"strconv"
+	"strings"
+)
+
+const (
+	// DecimalMaxScale is the maximum number of digits after decimal point.
+	DecimalMaxScale = 18
+	// DecimalAutoScale is the column scale that is taken from the first
+	// decimal value that is pushed or set into column.
+	DecimalAutoScale = -1
+)
+
+var (
+	// ErrInvalidDecimal returned when string can not be converted into
+	// decimal, or when the value has more digits after decimal point than
+	// the scale.
+	ErrInvalidDecimal = errors.New("tabula: invalid decimal value")
+	// ErrDecimalOverflow returned when decimal value is out of range.
+	ErrDecimalOverflow = errors.New("tabula: decimal value out of range")
+)
+
+//
+// Decimal is a fixed-point number, where the value is Value * 10^-Scale.
+// For example, "12.50" is stored as Value 1250 and Scale 2.
+//
+// The missing value of decimal has Value math.MinInt64.
+//
+type Decimal struct {
+	// Value is the unscaled value.
+	Value int64
+	// Scale is the number of digits after decimal point, between zero
+	// and DecimalMaxScale.
+	Scale int
+}
+
+//
+// ParseDecimal convert string `v` into decimal with `scale` digits after
+// decimal point, without rounding. Surrounding spaces is ignored. If
+// `scale` is negative, the scale is the number of digits after decimal
+// point in `v`.
+//
+// If `v` is not a decimal number, or has non zero digits after `scale`, it
+// will return ErrInvalidDecimal.
+//
+func ParseDecimal(v string, scale int) (d Decimal, e error) {
+	v = strings.TrimSpace(v)
+
+	neg := false
+	if len(v) > 0 && (v[0] == '-' || v[0] == '+') {
+		neg = v[0] == '-'
+		v = v[1:]
+	}
+
+	ipart, fpart := v, ""
+	if x := strings.IndexByte(v, '.'); x >= 0 {
+		ipart, fpart = v[:x], v[x+1:]
+	}
+	if len(ipart)+len(fpart) == 0 || !isDigits(ipart) || !isDigits(fpart) {
+		return d, ErrInvalidDecimal
+	}
+
+	if scale < 0 {
+		scale = len(fpart)
+	}
+	if scale > DecimalMaxScale {
+		return d, ErrInvalidDecimal
+	}
+	if len(fpart) > scale {
+		if strings.Trim(fpart[scale:], "0") != "" {
+			return d, ErrInvalidDecimal
+		}
+		fpart = fpart[:scale]
+	}
+
+	digits := ipart + fpart + strings.Repeat("0", scale-len(fpart))
+	if neg {
+		digits = "-" + digits
+	}
+
+	d.Value, e = strconv.ParseInt(digits, 10, 64)
+	if e != nil || d.Value == math.MinInt64 {
+		return Decimal{}, ErrDecimalOverflow
+	}
+	d.Scale = scale
+
+	return d, nil
+}
+
+//
+// isDigits return true if `s` contain only ASCII digits.
+//
+func isDigits(s string) bool {
+	for x := 0; x < len(s); x++ {
+		if s[x] < '0' || s[x] > '9' {
+			return false
+		}
+	}
+	return true
+}
+
+//
+// decimalPow10 return 10^n.
+//
+func decimalPow10(n int) int64 {
+	p := int64(1)
+	for ; n > 0; n-- {
+		p *= 10
+	}
+	return p
+}
+
+//
+// IsMissing return true if decimal is missing value.
+//
+func (d Decimal) IsMissing() bool {
+	return d.Value == math.MinInt64
+}
+
+//
+// Rescale convert decimal into `scale` digits after decimal point. If the
+// new scale is less than current scale, the dropped digits must be zero,
+// otherwise it will return ErrInvalidDecimal. Missing value is kept as
+// missing value.
+//
+func (d Decimal) Rescale(scale int) (Decimal, error) {
+	if scale < 0 || scale > DecimalMaxScale {
+		return d, ErrInvalidDecimal
+	}
+	if d.IsMissing() || scale == d.Scale {
+		d.Scale = scale
+		return d, nil
+	}
+
+	if scale < d.Scale {
+		p := decimalPow10(d.Scale - scale)
+		if d.Value%p != 0 {
+			return d, ErrInvalidDecimal
+		}
+		return Decimal{Value: d.Value / p, Scale: scale}, nil
+	}
+
+	p := decimalPow10(scale - d.Scale)
+	v := d.Value * p
+	if v/p != d.Value || v == math.MinInt64 {
+		return d, ErrDecimalOverflow
+	}
+
+	return Decimal{Value: v, Scale: scale}, nil
+}
+
+//
+// Add return the exact sum of `d` and `o`, using the larger scale of both.
+// If one of them is missing value, it will return missing value.
+//
+func (d Decimal) Add(o Decimal) (sum Decimal, e error) {
+	scale := d.Scale
+	if o.Scale > scale {
+		scale = o.Scale
+	}
+	if d.IsMissing() || o.IsMissing() {
+		return Decimal{Value: math.MinInt64, Scale: scale}, nil
+	}
+
+	d, e = d.Rescale(scale)
+	if e != nil {
+		return sum, e
+	}
+	o, e = o.Rescale(scale)
+	if e != nil {
+		return sum, e
+	}
+
+	sum.Value = d.Value + o.Value
+	sum.Scale = scale
+
+	// Overflow if both operands have the same sign and the sign of sum
+	// is different.
+	if (d.Value >= 0) == (o.Value >= 0) &&
+		(sum.Value >= 0) != (d.Value >= 0) || sum.IsMissing() {
+		return Decimal{}, ErrDecimalOverflow
+	}
+
+	return sum, nil
+}
+
+//
+// Cmp compare decimal with `o` and return -1 if its less than `o`, 0 if its
+// equal, and 1 if its greater than `o`. Missing value is less than any
+// other value.
+//
+func (d Decimal) Cmp(o Decimal) int {
+	if d.IsMissing() || o.IsMissing() {
+		return compareInt(d.Value, o.Value, d.IsMissing(), o.IsMissing())
+	}
+
+	// Compare the integer part and then the fraction in the larger
+	// scale, so the value is never overflow.
+	scale := d.Scale
+	if o.Scale > scale {
+		scale = o.Scale
+	}
+
+	dp, op := decimalPow10(d.Scale), decimalPow10(o.Scale)
+
+	if d.Value/dp != o.Value/op {
+		return compareInt(d.Value/dp, o.Value/op, false, false)
+	}
+
+	return compareInt(d.Value%dp*decimalPow10(scale-d.Scale),
+		o.Value%op*decimalPow10(scale-o.Scale), false, false)
+}
+
+//
+// compareInt compare integer `a` and `b`, where missing value is less than
+// any other value.
+//
+func compareInt(a, b int64, aMissing, bMissing bool) int {
+	switch {
+	case aMissing && bMissing:
+		return 0
+	case aMissing:
+		return -1
+	case bMissing:
+		return 1
+	case a < b:
+		return -1
+	case a > b:
+		return 1
+	}
+	return 0
+}
+
+//
+// Float return the decimal as float, which may not be exact. Missing value
+// is returned as -Inf.
+//
+func (d Decimal) Float() float64 {
+	if d.IsMissing() {
+		return math.Inf(-1)
+	}
+	f64, _ := strconv.ParseFloat(d.String(), 64)
+	return f64
+}
+
+//
+// Integer return the integer part of decimal. Missing value is returned as
+// math.MinInt64.
+//
+func (d Decimal) Integer() int64 {
+	if d.IsMissing() {
+		return d.Value
+	}
+	return d.Value / decimalPow10(d.Scale)
+}
+
+//
+// String convert decimal into string with exactly Scale digits after
+// decimal point, including the trailing zeros. Missing value is converted
+// to "?".
+//
+func (d Decimal) String() string {
+	if d.IsMissing() {
+		return "?"
+	}
+
+	s := strconv.FormatInt(d.Value, 10)
+	if d.Scale <= 0 {
+		return s
+	}
+
+	sign := ""
+	if d.Value < 0 {
+		sign = "-"
+		s = s[1:]
+	}
+	if len(s) <= d.Scale {
+		s = strings.Repeat("0", d.Scale-len(s)+1) + s
+	}
+
+	x := len(s) - d.Scale
+
+	return sign + s[:x] + "." + s[x:]
+}
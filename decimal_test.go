@@ -0,0 +1,290 @@
+// Copyright 2017 M. Shulhan <ms@kilabit.info>. All rights reserved.
+// Use of this source code is governed by a BSD-style license that can be found
+// in the LICENSE file.
+
+package tabula_test
+
+import (
+	"bytes"
+	"encoding/json"
+	"github.com/shuLhan/tabula"
+	"strings"
+	"testing"
+)
+
+func TestParseDecimal(t *testing.T) {
+	cases := []struct {
+		in    string
+		scale int
+		exp   string
+		err   error
+	}{{
+		in:    "12.5",
+		scale: 2,
+		exp:   "12.50",
+	}, {
+		in:    " -0.05 ",
+		scale: -1,
+		exp:   "-0.05",
+	}, {
+		in:    "1.2300",
+		scale: 2,
+		exp:   "1.23",
+	}, {
+		in:    "1.234",
+		scale: 2,
+		err:   tabula.ErrInvalidDecimal,
+	}, {
+		in:    ".5",
+		scale: 0,
+		err:   tabula.ErrInvalidDecimal,
+	}, {
+		in:    "1e5",
+		scale: -1,
+		err:   tabula.ErrInvalidDecimal,
+	}, {
+		in:    "92233720368547758.08",
+		scale: 2,
+		err:   tabula.ErrDecimalOverflow,
+	}}
+
+	for _, c := range cases {
+		got, e := tabula.ParseDecimal(c.in, c.scale)
+		assert(t, c.err, e, true)
+		if e == nil {
+			assert(t, c.exp, got.String(), true)
+		}
+	}
+}
+
+func TestDecimalAdd(t *testing.T) {
+	var sum tabula.Decimal
+
+	for x := 0; x < 10; x++ {
+		d, _ := tabula.ParseDecimal("0.1", -1)
+		sum, _ = sum.Add(d)
+	}
+
+	one, _ := tabula.ParseDecimal("1.00", -1)
+
+	assert(t, "1.0", sum.String(), true)
+	assert(t, 0, sum.Cmp(one), true)
+
+	big, _ := tabula.ParseDecimal("9223372036854775807", 0)
+	_, e := big.Add(one)
+	assert(t, tabula.ErrDecimalOverflow, e, true)
+
+	a, _ := tabula.ParseDecimal("-1.5", -1)
+	b, _ := tabula.ParseDecimal("-1.25", -1)
+	assert(t, -1, a.Cmp(b), true)
+	assert(t, 1, b.Cmp(a), true)
+}
+
+func TestColumnDecimal(t *testing.T) {
+	col := tabula.NewColumn(tabula.TDecimal, "amount")
+	col.Scale = 2
+
+	for _, v := range []string{"10.1", "20.25", "0.1"} {
+		rec, e := tabula.NewRecordBy(v, tabula.TDecimal)
+		if e != nil {
+			t.Fatal(e)
+		}
+		col.PushBack(rec)
+	}
+
+	rec := tabula.NewRecord()
+	rec.SetMissingValue(tabula.TDecimal)
+	col.PushBack(rec)
+
+	// Decimal that can not be rescaled is kept as is.
+	e := col.PushBack(tabula.NewRecordDecimal(tabula.Decimal{
+		Value: 1,
+		Scale: 3,
+	}))
+	assert(t, tabula.ErrInvalidDecimal, e, true)
+
+	assert(t, []string{"10.10", "20.25", "0.10", "?", "0.001"},
+		col.ToStringSlice(), true)
+
+	sum, e := col.SumDecimal()
+	if e != nil {
+		t.Fatal(e)
+	}
+
+	assert(t, "30.451", sum.String(), true)
+
+	b, e := json.Marshal(col)
+	if e != nil {
+		t.Fatal(e)
+	}
+
+	assert(t, `{"Name":"amount","Type":5,"Flag":0,"ValueSpace":null,`+
+		`"Scale":2,"Records":[10.10,20.25,0.10,null,0.001]}`,
+		string(b), true)
+	b, e = col.MarshalBinary()
+	if e != nil {
+		t.Fatal(e)
+	}
+
+	got := tabula.Column{}
+
+	e = got.UnmarshalBinary(b)
+	if e != nil {
+		t.Fatal(e)
+	}
+
+	assert(t, col, &got, true)
+	assert(t, 2, got.Scale, true)
+}
+
+func TestDSVDecimal(t *testing.T) {
+	input := "id,amount\n1,1.5\n2,-0.25\n3,?\n"
+
+	ds := tabula.NewDataset(tabula.DatasetModeRows,
+		[]int{tabula.TInteger, tabula.TDecimal}, nil)
+	ds.Columns[1].Scale = 2
+
+	reader := tabula.NewDSVReader()
+	reader.Header = true
+
+	e := reader.Read(strings.NewReader(input), ds)
+	if e != nil {
+		t.Fatal(e)
+	}
+
+	assert(t, "&[1 1.50]&[2 -0.25]&[3 ?]",
+		ds.GetDataAsRows().String(), true)
+
+	var out bytes.Buffer
+
+	e = tabula.NewDSVWriter().Write(&out, ds)
+	if e != nil {
+		t.Fatal(e)
+	}
+
+	assert(t, "1,1.50\n2,-0.25\n3,?\n", out.String(), true)
+
+	e = reader.Read(strings.NewReader("id,amount\n1,1.005\n"), ds)
+
+	re, ok := e.(*tabula.ReadError)
+	assert(t, true, ok, true)
+	assert(t, tabula.ErrInvalidDecimal, re.Err, true)
+}
+
+func TestDatasetDecimalScale(t *testing.T) {
+	modes := []int{
+		tabula.DatasetModeRows,
+		tabula.DatasetModeColumns,
+		tabula.DatasetModeMatrix,
+	}
+
+	for _, mode := range modes {
+		ds := tabula.NewDataset(mode, []int{tabula.TDecimal}, nil)
+
+		assert(t, tabula.DecimalAutoScale, ds.Columns[0].Scale, true)
+
+		for _, d := range []tabula.Decimal{
+			{Value: 1250, Scale: 2},
+			{Value: 3, Scale: 0},
+		} {
+			ds.PushRow(&tabula.Row{tabula.NewRecordDecimal(d)})
+		}
+
+		assert(t, 2, ds.Columns[0].Scale, true)
+		assert(t, "&[12.50]&[3.00]", ds.GetDataAsRows().String(), true)
+
+		ds = tabula.NewDataset(mode, []int{tabula.TInteger,
+			tabula.TDecimal}, nil)
+
+		reader := tabula.NewDSVReader()
+
+		e := reader.Read(strings.NewReader("1,12.50\n2,0.5\n"), ds)
+		if e != nil {
+			t.Fatal(e)
+		}
+
+		assert(t, 2, ds.Columns[1].Scale, true)
+		assert(t, "&[1 12.50]&[2 0.50]", ds.GetDataAsRows().String(),
+			true)
+
+		ds.SetColumnsScale([]int{0, 1})
+
+		e = reader.Read(strings.NewReader("1,12.55\n"), ds)
+
+		re, ok := e.(*tabula.ReadError)
+		assert(t, true, ok, true)
+		assert(t, tabula.ErrInvalidDecimal, re.Err, true)
+	}
+}
+
+func TestDatasetSumDecimal(t *testing.T) {
+	modes := []int{
+		tabula.DatasetModeRows,
+		tabula.DatasetModeColumns,
+		tabula.DatasetModeMatrix,
+	}
+
+	for _, mode := range modes {
+		ds := tabula.NewDataset(mode, []int{tabula.TString,
+			tabula.TDecimal}, nil)
+		ds.SetColumnsScale([]int{0, 2})
+
+		reader := tabula.NewDSVReader()
+
+		e := reader.Read(strings.NewReader("a,0.1\nb,?\nc,20.25\n"), ds)
+		if e != nil {
+			t.Fatal(e)
+		}
+
+		sum, e := ds.SumDecimal(1)
+		if e != nil {
+			t.Fatal(e)
+		}
+
+		assert(t, "20.35", sum.String(), true)
+
+		_, e = ds.SumDecimal(0)
+		assert(t, tabula.ErrInvalidDecimal, e, true)
+
+		_, e = ds.SumDecimal(2)
+		assert(t, tabula.ErrColIdxOutOfRange, e, true)
+	}
+}
+
+func TestColumnarDecimal(t *testing.T) {
+	ds := tabula.NewDataset(tabula.DatasetModeColumns,
+		[]int{tabula.TDecimal}, []string{"amount"})
+	ds.Columns[0].Scale = 3
+
+	missing := tabula.NewRecord()
+	missing.SetMissingValue(tabula.TDecimal)
+
+	ds.PushRow(&tabula.Row{
+		tabula.NewRecordDecimal(tabula.Decimal{Value: -1050, Scale: 3}),
+	})
+	ds.PushRow(&tabula.Row{missing})
+
+	var out bytes.Buffer
+
+	e := tabula.WriteColumnar(&out, ds)
+	if e != nil {
+		t.Fatal(e)
+	}
+
+	reader, e := tabula.NewColumnarReader(bytes.NewReader(out.Bytes()),
+		int64(out.Len()))
+	if e != nil {
+		t.Fatal(e)
+	}
+
+	got := tabula.NewDataset(tabula.DatasetModeRows, nil, nil)
+
+	e = reader.Read(got)
+	if e != nil {
+		t.Fatal(e)
+	}
+
+	assert(t, ds.GetDataAsRows(), got.GetDataAsRows(), true)
+	assert(t, "&[-1.050]&[?]", got.GetDataAsRows().String(), true)
+	assert(t, 3, got.Columns[0].Scale, true)
+}
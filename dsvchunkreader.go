@@ -0,0 +1,239 @@
+// Copyright 2017 M. Shulhan <ms@kilabit.info>. All rights reserved.
+// Use of this source code is governed by a BSD-style license that can be found
+// in the LICENSE file.
+
+package tabula
+
+import (
+	"io"
+)
+
+//
+// DSVChunkReader read DSV input in chunks, where each chunk is a new dataset
+// that contain at most `size` rows. This allow processing input that is
+// larger than available memory.
+//
+// All chunks have the same mode and columns metadata: name, type, flag,
+// value space, time format, and missing values, which is taken from schema
+// dataset. If schema does not have any columns, the columns will be created
+// with string type, using name from header if its exist.
+//
+type DSVChunkReader struct {
+	reader *DSVReader
+	p      *dsvParser
+	schema *Dataset
+	cols   Columns
+	size   int
+	nrow   int
+	done   bool
+	closer io.Closer
+}
+
+//
+// OpenDSVChunkFile open DSV `file` and return the chunk reader. Caller must
+// close the reader after its not used anymore.
+//
+func OpenDSVChunkFile(reader *DSVReader, file string, schema DatasetInterface,
+	size int,
+) (cr *DSVChunkReader, e error) {
+	f, e := OpenFile(file)
+	if e != nil {
+		return nil, e
+	}
+
+	cr, e = NewDSVChunkReader(reader, f, schema, size)
+	if e != nil {
+		_ = f.Close()
+		return nil, e
+	}
+
+	cr.closer = f
+
+	return cr, nil
+}
+
+//
+// NewDSVChunkReader create new chunk reader that read `r` using options in
+// `reader`, and return at most `size` rows on each chunk. If `schema` is
+// nil, the chunk will use rows mode. The skipped lines and header is read
+// immediately.
+//
+func NewDSVChunkReader(reader *DSVReader, r io.Reader, schema DatasetInterface,
+	size int,
+) (cr *DSVChunkReader, e error) {
+	if size <= 0 {
+		size = 1
+	}
+
+	r, e = NewDecodeReader(r, reader.Encoding)
+	if e != nil {
+		return nil, e
+	}
+
+	cr = &DSVChunkReader{
+		reader: reader,
+		p:      newDSVParser(reader, r),
+		size:   size,
+	}
+
+	if schema == nil {
+		cr.schema = NewDataset(DatasetModeRows, nil, nil)
+	} else {
+		cr.schema = newDatasetFromSchema(schema)
+	}
+
+	e = cr.p.skipLines(reader.Skip)
+	if e != nil {
+		return nil, e
+	}
+
+	if reader.Header {
+		names, _, e := cr.p.readFields()
+		if e == io.EOF {
+			cr.done = true
+			return cr, nil
+		}
+		if e != nil {
+			return nil, e
+		}
+		initDatasetColumns(cr.schema, names, len(names))
+	}
+
+	cr.cols = cr.schema.Columns
+
+	return cr, nil
+}
+
+//
+// newDatasetFromSchema create new empty dataset with the same mode and
+// columns metadata as `schema`.
+//
+func newDatasetFromSchema(schema DatasetInterface) (ds *Dataset) {
+	ds = NewDataset(schema.GetMode(), schema.GetColumnsType(),
+		schema.GetColumnsName())
+
+	cols := schema.GetColumns()
+	for x := range ds.Columns {
+		ds.Columns[x].Flag = (*cols)[x].Flag
+		ds.Columns[x].ValueSpace = (*cols)[x].ValueSpace
+		ds.Columns[x].TimeFormat = (*cols)[x].TimeFormat
+		ds.Columns[x].MissingValues = (*cols)[x].MissingValues
+		ds.Columns[x].Scale = (*cols)[x].Scale
+		ds.Columns[x].Categorical = (*cols)[x].Categorical
+		ds.Columns[x].CategoryPolicy = (*cols)[x].CategoryPolicy
+	}
+
+	return ds
+}
+
+//
+// Close the underlying file, if the reader is created using
+// OpenDSVChunkFile.
+//
+func (cr *DSVChunkReader) Close() error {
+	if cr.closer == nil {
+		return nil
+	}
+	return cr.closer.Close()
+}
+
+//
+// Schema return the empty dataset that contain the columns metadata of
+// each chunk.
+//
+func (cr *DSVChunkReader) Schema() *Dataset {
+	return newDatasetFromSchema(cr.schema)
+}
+
+//
+// Next read the next chunk of rows. It will return io.EOF if there is no
+// more rows to be read, or MaxRows in reader has been reached.
+//
+// If error happened while reading, it will return the chunk that contain
+// rows before error, if its not empty, and the error. The next call will
+// return io.EOF.
+//
+func (cr *DSVChunkReader) Next() (chunk *Dataset, e error) {
+	if cr.done {
+		return nil, io.EOF
+	}
+
+	var fields []string
+	var line int
+	var row *Row
+
+	for n := 0; n < cr.size; n++ {
+		if cr.reader.MaxRows > 0 && cr.nrow >= cr.reader.MaxRows {
+			cr.done = true
+			break
+		}
+
+		fields, line, e = cr.p.readFields()
+		if e == io.EOF {
+			cr.done = true
+			e = nil
+			break
+		}
+		if e != nil {
+			cr.done = true
+			return chunk, e
+		}
+
+		if cr.schema.GetNColumn() <= 0 {
+			initDatasetColumns(cr.schema, nil, len(fields))
+			cr.cols = cr.schema.Columns
+		}
+
+		row, e = newRowFromStrings(fields, cr.cols,
+			cr.reader.MissingValue, cr.reader.NumberFormat, line)
+		if e != nil {
+			cr.done = true
+			return chunk, e
+		}
+
+		if chunk == nil {
+			chunk = newDatasetFromSchema(cr.schema)
+		}
+		chunk.PushRow(row)
+		cr.nrow++
+	}
+
+	if chunk == nil {
+		return nil, io.EOF
+	}
+
+	return chunk, nil
+}
+
+//
+// Stream read all chunks in the background and send each of them to the
+// first channel, which is closed after the last chunk. When reading is
+// finished, the second channel receive nil or the error that stop the
+// reading.
+//
+func (cr *DSVChunkReader) Stream() (<-chan *Dataset, <-chan error) {
+	chunks := make(chan *Dataset)
+	errc := make(chan error, 1)
+
+	go func() {
+		defer close(errc)
+		defer close(chunks)
+
+		for {
+			chunk, e := cr.Next()
+			if chunk != nil {
+				chunks <- chunk
+			}
+			if e == io.EOF {
+				errc <- nil
+				return
+			}
+			if e != nil {
+				errc <- e
+				return
+			}
+		}
+	}()
+
+	return chunks, errc
+}
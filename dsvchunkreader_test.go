@@ -0,0 +1,111 @@
+// Copyright 2017 M. Shulhan <ms@kilabit.info>. All rights reserved.
+// Use of this source code is governed by a BSD-style license that can be found
+// in the LICENSE file.
+
+package tabula_test
+
+import (
+	"fmt"
+	"github.com/shuLhan/tabula"
+	"io"
+	"strings"
+	"testing"
+)
+
+func TestDSVChunkReaderNext(t *testing.T) {
+	claset := tabula.Claset{}
+
+	e := tabula.ReadDatasetConfig(&claset, "testdata/claset.json")
+	if e != nil {
+		t.Fatal(e)
+	}
+	claset.Columns[3].Flag = 1
+
+	cr, e := tabula.OpenDSVChunkFile(newTestDSVReader(),
+		"testdata/claset.csv", &claset, 3)
+	if e != nil {
+		t.Fatal(e)
+	}
+	defer cr.Close()
+
+	exp := []string{
+		"[1 2 3][0.5 1.25 2][a, quoted escaped,comma multi\nline][+ - -]",
+		"[4][-3.75][with \"quote\"][+]",
+	}
+
+	for x := range exp {
+		chunk, e := cr.Next()
+		if e != nil {
+			t.Fatal(e)
+		}
+
+		assert(t, tabula.DatasetModeColumns, chunk.GetMode(), true)
+		assert(t, claset.GetColumnsName(), chunk.GetColumnsName(), true)
+		assert(t, claset.GetColumnsType(), chunk.GetColumnsType(), true)
+		assert(t, 1, chunk.Columns[3].Flag, true)
+
+		got := ""
+		for _, col := range chunk.Columns {
+			got += fmt.Sprint(col.Records)
+		}
+
+		assert(t, exp[x], got, true)
+	}
+
+	_, e = cr.Next()
+	assert(t, io.EOF, e, true)
+}
+
+func TestDSVChunkReaderStream(t *testing.T) {
+	input := "A,B\n1,x\n2,y\n3,z\n4,w\n5,v\n"
+	reader := tabula.NewDSVReader()
+	reader.Header = true
+	reader.MaxRows = 4
+
+	cr, e := tabula.NewDSVChunkReader(reader, strings.NewReader(input),
+		nil, 2)
+	if e != nil {
+		t.Fatal(e)
+	}
+
+	assert(t, []string{"A", "B"}, cr.Schema().GetColumnsName(), true)
+
+	chunks, errc := cr.Stream()
+	got := []int{}
+
+	for chunk := range chunks {
+		assert(t, tabula.DatasetModeRows, chunk.GetMode(), true)
+		assert(t, []string{"A", "B"}, chunk.GetColumnsName(), true)
+		got = append(got, chunk.Len())
+	}
+
+	assert(t, []int{2, 2}, got, true)
+	assert(t, nil, <-errc, true)
+}
+
+func TestDSVChunkReaderError(t *testing.T) {
+	input := "1,x\n2,y\n3\n4,w\n"
+	types := []int{tabula.TInteger, tabula.TString}
+	schema := tabula.NewDataset(tabula.DatasetModeRows, types, nil)
+
+	cr, e := tabula.NewDSVChunkReader(tabula.NewDSVReader(),
+		strings.NewReader(input), schema, 10)
+	if e != nil {
+		t.Fatal(e)
+	}
+
+	chunks, errc := cr.Stream()
+	got := 0
+
+	for chunk := range chunks {
+		got += chunk.Len()
+	}
+
+	e = <-errc
+	rerr, ok := e.(*tabula.ReadError)
+
+	assert(t, 2, got, true)
+	assert(t, true, ok, true)
+	assert(t, 3, rerr.Line, true)
+	assert(t, tabula.ErrMisColLength, rerr.Err, true)
+}
@@ -0,0 +1,421 @@
+// Copyright 2017 M. Shulhan <ms@kilabit.info>. All rights reserved.
+// Use of this source code is governed by a BSD-style license that can be found
+// in the LICENSE file.
+
+package tabula
+
+import (
+	"bufio"
+	"bytes"
+	"io"
+	"unicode/utf8"
+)
+
+const (
+	// DefaultDelimiter is the default value for separating fields.
+	DefaultDelimiter = ","
+	// DefaultQuote is the default value for enclosing field value.
+	DefaultQuote = "\""
+	// DefaultEscape is the default value for escaping special character
+	// in field value, which is empty. Quote inside quoted value is
+	// escaped by doubling it, as in RFC 4180.
+	DefaultEscape = ""
+	// DefaultMissingValue is the default value for indicating missing
+	// value in field.
+	DefaultMissingValue = "?"
+)
+
+//
+// DSVReader read DSV (delimiter separated values), e.g. CSV, and load the
+// rows into dataset.
+//
+// The column names and types is taken from dataset, which can be loaded
+// using ReadDatasetConfig before reading. The reader options itself can be
+// loaded from configuration file using ReadDatasetConfig.
+//
+type DSVReader struct {
+	// Delimiter separate each field in line. If its empty, it will
+	// default to DefaultDelimiter.
+	Delimiter string
+	// Quote enclose the field value. Delimiter inside quoted value is
+	// read as is. If its empty, field value will not be unquoted.
+	Quote string
+	// Escape will make the next character read as is, except "n" and "r"
+	// which is read as new line and carriage return. If its empty, the
+	// default, or equal to quote, value is not escaped. Double quote
+	// inside quoted value is always read as one quote.
+	Escape string
+	// Header if its true, the first line after skipped lines contain the
+	// name of columns.
+	Header bool
+	// Skip number of lines at the beginning of input.
+	Skip int
+	// MaxRows maximum number of rows to be read. If its zero or negative,
+	// all rows will be read.
+	MaxRows int
+	// MissingValue if its not empty, field with this value will be set
+	// to missing value based on the column type. Field with one of column
+	// MissingValues is set to null.
+	MissingValue string
+	// Encoding is the character encoding of input, see NewDecodeReader.
+	// If its empty, input is read as UTF-8.
+	Encoding string
+	// NumberFormat if its not nil, will be used to convert the field
+	// value for integer and real column.
+	NumberFormat *NumberFormat
+}
+
+//
+// NewDSVReader create and return new DSV reader with default delimiter,
+// quote, escape, and missing value.
+//
+func NewDSVReader() *DSVReader {
+	return &DSVReader{
+		Delimiter:    DefaultDelimiter,
+		Quote:        DefaultQuote,
+		Escape:       DefaultEscape,
+		MissingValue: DefaultMissingValue,
+	}
+}
+
+//
+// ReadFile open DSV file and read all of its rows into dataset `ds`.
+//
+func (reader *DSVReader) ReadFile(file string, ds DatasetInterface) (e error) {
+	f, e := OpenFile(file)
+	if e != nil {
+		return e
+	}
+
+	e = reader.Read(f, ds)
+
+	errClose := f.Close()
+	if e == nil {
+		e = errClose
+	}
+
+	return
+}
+
+//
+// Read all rows from `r` into dataset `ds`.
+//
+// If dataset does not have any columns, the columns will be created with
+// string type, using name from header if its exist.
+// If dataset columns does not have a name and header is exist, the column
+// names will be set from header.
+//
+// If number of fields in line does not match with number of columns, or
+// field value can not be converted to column type, it will return ReadError
+// which contain the line and column number.
+//
+func (reader *DSVReader) Read(r io.Reader, ds DatasetInterface) (e error) {
+	r, e = NewDecodeReader(r, reader.Encoding)
+	if e != nil {
+		return
+	}
+
+	p := newDSVParser(reader, r)
+
+	e = p.skipLines(reader.Skip)
+	if e != nil {
+		return
+	}
+
+	if reader.Header {
+		names, _, e := p.readFields()
+		if e == io.EOF {
+			return nil
+		}
+		if e != nil {
+			return e
+		}
+		initDatasetColumns(ds, names, len(names))
+	}
+
+	var fields []string
+	var line int
+	var row *Row
+	var cols Columns
+
+	for n := 0; reader.MaxRows <= 0 || n < reader.MaxRows; n++ {
+		fields, line, e = p.readFields()
+		if e == io.EOF {
+			return nil
+		}
+		if e != nil {
+			return
+		}
+
+		if n == 0 {
+			initDatasetColumns(ds, nil, len(fields))
+			cols = *ds.GetColumns()
+		}
+
+		row, e = newRowFromStrings(fields, cols, reader.MissingValue,
+			reader.NumberFormat, line)
+		if e != nil {
+			return
+		}
+
+		ds.PushRow(row)
+	}
+
+	return
+}
+
+//
+// initDatasetColumns will create `ncol` string columns in dataset if its
+// does not have one. If column names is empty, it will be set to `names`.
+//
+func initDatasetColumns(ds DatasetInterface, names []string, ncol int) {
+	if ds.GetNColumn() <= 0 {
+		types := make([]int, ncol)
+		for x := range types {
+			types[x] = TString
+		}
+		ds.Init(ds.GetMode(), types, names)
+		return
+	}
+
+	for _, name := range ds.GetColumnsName() {
+		if name != "" {
+			return
+		}
+	}
+
+	ds.SetColumnsName(names)
+}
+
+//
+// newRowFromStrings create new row by converting each value in `fields` into
+// record using the type and time format of columns in `cols`. Value that is
+// one of column missing values is converted to null, value that equal to
+// `missing` is converted to missing value, numeric value is normalized
+// using `nf`, and value on categorical column is encoded. Parameter `line`
+// is used to report the position of invalid value.
+//
+func newRowFromStrings(fields []string, cols Columns, missing string,
+	nf *NumberFormat, line int,
+) (
+	row *Row, e error,
+) {
+	if len(fields) != len(cols) {
+		return nil, &ReadError{
+			Line: line,
+			Err:  ErrMisColLength,
+		}
+	}
+
+	newrow := make(Row, len(fields))
+
+	for x, v := range fields {
+		col := &cols[x]
+
+		if col.IsMissingValue(v) {
+			newrow[x] = NewRecordNull(col.Type)
+			continue
+		}
+		if len(missing) > 0 && v == missing {
+			rec := NewRecord()
+			rec.SetMissingValue(col.Type)
+			newrow[x] = rec
+			continue
+		}
+
+		rec, e := col.newRecord(v, nf)
+		if e == nil {
+			e = col.Encode(rec)
+		}
+		if e != nil {
+			return nil, &ReadError{
+				Line:   line,
+				Column: x + 1,
+				Value:  v,
+				Err:    e,
+			}
+		}
+		newrow[x] = rec
+	}
+
+	return &newrow, nil
+}
+
+//
+// dsvParser split the input into fields using the reader options.
+//
+type dsvParser struct {
+	br    *bufio.Reader
+	delim []byte
+	quote []byte
+	esc   []byte
+	// line contain the number of last line that has been read.
+	line int
+	// cr is true if the last line that has been read is ended with
+	// carriage return.
+	cr bool
+}
+
+func newDSVParser(reader *DSVReader, r io.Reader) (p *dsvParser) {
+	p = &dsvParser{
+		br:    bufio.NewReader(r),
+		delim: []byte(reader.Delimiter),
+		quote: []byte(reader.Quote),
+		esc:   []byte(reader.Escape),
+	}
+	if len(p.delim) == 0 {
+		p.delim = []byte(DefaultDelimiter)
+	}
+	if bytes.Equal(p.esc, p.quote) && len(p.quote) > 0 {
+		// Escaping is done by doubling the quote.
+		p.esc = nil
+	}
+	return
+}
+
+//
+// readLine read one line from input without the end of line characters.
+// It will return io.EOF if no more line to be read.
+//
+func (p *dsvParser) readLine() (line []byte, e error) {
+	line, e = p.br.ReadBytes('\n')
+	if len(line) == 0 {
+		if e == nil {
+			e = io.EOF
+		}
+		return nil, e
+	}
+
+	p.line++
+
+	line = bytes.TrimSuffix(line, []byte{'\n'})
+	p.cr = bytes.HasSuffix(line, []byte{'\r'})
+	if p.cr {
+		line = line[:len(line)-1]
+	}
+
+	return line, nil
+}
+
+//
+// skipLines read and ignore `n` lines from input.
+//
+func (p *dsvParser) skipLines(n int) (e error) {
+	for ; n > 0; n-- {
+		_, e = p.readLine()
+		if e == io.EOF {
+			return nil
+		}
+		if e != nil {
+			return
+		}
+	}
+	return
+}
+
+//
+// readFields read one record from input, which may span multiple lines if
+// quoted value contain new line, and split it into fields. Empty line is
+// ignored. It will return the fields and the line number where the record
+// started.
+//
+func (p *dsvParser) readFields() (fields []string, start int, e error) {
+	var line []byte
+
+	for len(line) == 0 {
+		line, e = p.readLine()
+		if e != nil {
+			return
+		}
+	}
+	start = p.line
+
+	var field []byte
+	quoted := false
+	atStart := true
+	hasQuote := len(p.quote) > 0
+	hasEsc := len(p.esc) > 0
+
+	for x := 0; ; {
+		if x >= len(line) {
+			if !quoted {
+				break
+			}
+			cr := p.cr
+			line, e = p.readLine()
+			if e != nil {
+				if e == io.EOF {
+					e = &ReadError{
+						Line:   start,
+						Column: len(fields) + 1,
+						Value:  string(field),
+						Err:    ErrUnclosedQuote,
+					}
+				}
+				return nil, start, e
+			}
+			if cr {
+				field = append(field, '\r')
+			}
+			field = append(field, '\n')
+			x = 0
+			continue
+		}
+
+		rest := line[x:]
+
+		switch {
+		case hasEsc && bytes.HasPrefix(rest, p.esc):
+			x += len(p.esc)
+			if x >= len(line) {
+				break
+			}
+			switch line[x] {
+			case 'n':
+				field = append(field, '\n')
+				x++
+			case 'r':
+				field = append(field, '\r')
+				x++
+			default:
+				_, size := utf8.DecodeRune(line[x:])
+				field = append(field, line[x:x+size]...)
+				x += size
+			}
+
+		case quoted && bytes.HasPrefix(rest, p.quote):
+			x += len(p.quote)
+			if bytes.HasPrefix(line[x:], p.quote) {
+				field = append(field, p.quote...)
+				x += len(p.quote)
+			} else {
+				quoted = false
+			}
+
+		case quoted:
+			field = append(field, line[x])
+			x++
+
+		case atStart && hasQuote && bytes.HasPrefix(rest, p.quote):
+			quoted = true
+			x += len(p.quote)
+
+		case bytes.HasPrefix(rest, p.delim):
+			fields = append(fields, string(field))
+			field = field[:0]
+			x += len(p.delim)
+			atStart = true
+			continue
+
+		default:
+			field = append(field, line[x])
+			x++
+		}
+
+		atStart = false
+	}
+
+	fields = append(fields, string(field))
+
+	return fields, start, nil
+}
@@ -0,0 +1,189 @@
+// Copyright 2017 M. Shulhan <ms@kilabit.info>. All rights reserved.
+// Use of this source code is governed by a BSD-style license that can be found
+// in the LICENSE file.
+
+package tabula_test
+
+import (
+	"fmt"
+	"github.com/shuLhan/tabula"
+	"strings"
+	"testing"
+)
+
+func newTestDSVReader() *tabula.DSVReader {
+	reader := tabula.NewDSVReader()
+	reader.Escape = "\\"
+	reader.Skip = 1
+	reader.Header = true
+	return reader
+}
+
+func TestDSVReaderReadFile(t *testing.T) {
+	claset := tabula.Claset{}
+
+	e := tabula.ReadDatasetConfig(&claset, "testdata/claset.json")
+	if e != nil {
+		t.Fatal(e)
+	}
+
+	e = newTestDSVReader().ReadFile("testdata/claset.csv", &claset)
+	if e != nil {
+		t.Fatal(e)
+	}
+
+	assert(t, tabula.DatasetModeColumns, claset.GetMode(), true)
+	assert(t, 3, claset.GetClassIndex(), true)
+	assert(t, 4, claset.Len(), true)
+
+	exp := "[1 2 3 4][0.5 1.25 2 -3.75][a, quoted escaped,comma multi\nline with \"quote\"][+ - - +]"
+	got := ""
+	for _, col := range claset.Columns {
+		got += fmt.Sprint(col.Records)
+	}
+
+	assert(t, exp, got, true)
+
+	exp = "[1 2 3 4]"
+	got = fmt.Sprint(claset.GetColumn(0).ToIntegers())
+
+	assert(t, exp, got, true)
+
+	claset.RecountMajorMinor()
+
+	assert(t, []int{2, 2}, claset.Counts(), true)
+}
+
+func TestDSVReaderModes(t *testing.T) {
+	input := "A;B\n1;x\n2;y\n3;z\n"
+	reader := tabula.NewDSVReader()
+	reader.Delimiter = ";"
+	reader.Header = true
+	reader.MaxRows = 2
+
+	modes := []int{
+		tabula.DatasetModeRows,
+		tabula.DatasetModeColumns,
+		tabula.DatasetModeMatrix,
+	}
+
+	for _, mode := range modes {
+		dataset := tabula.NewDataset(mode, []int{tabula.TInteger,
+			tabula.TString}, nil)
+
+		e := reader.Read(strings.NewReader(input), dataset)
+		if e != nil {
+			t.Fatal(e)
+		}
+
+		assert(t, []string{"A", "B"}, dataset.GetColumnsName(), true)
+		assert(t, 2, dataset.Len(), true)
+
+		exp := "&[1 x]&[2 y]"
+		got := fmt.Sprint(*dataset.GetDataAsRows())
+
+		assert(t, exp, got, true)
+	}
+}
+
+func TestDSVReaderNoColumns(t *testing.T) {
+	input := "\"a\"\"b\",c\n\nd,e\n"
+	reader := tabula.NewDSVReader()
+	reader.Escape = reader.Quote
+
+	dataset := tabula.NewDataset(tabula.DatasetModeRows, nil, nil)
+
+	e := reader.Read(strings.NewReader(input), dataset)
+	if e != nil {
+		t.Fatal(e)
+	}
+
+	assert(t, []int{tabula.TString, tabula.TString},
+		dataset.GetColumnsType(), true)
+
+	exp := "&[a\"b c]&[d e]"
+	got := fmt.Sprint(dataset.Rows)
+
+	assert(t, exp, got, true)
+}
+
+func TestDSVReaderEscape(t *testing.T) {
+	input := `C:\new,"say ""hi""",a\,b` + "\n"
+
+	reader := tabula.NewDSVReader()
+	dataset := tabula.NewDataset(tabula.DatasetModeRows, nil, nil)
+
+	e := reader.Read(strings.NewReader(input), dataset)
+	if e != nil {
+		t.Fatal(e)
+	}
+
+	exp := tabula.Row{
+		tabula.NewRecordString(`C:\new`),
+		tabula.NewRecordString(`say "hi"`),
+		tabula.NewRecordString(`a\`),
+		tabula.NewRecordString("b"),
+	}
+
+	assert(t, &exp, dataset.GetRow(0), true)
+
+	// Escape is only decoded if its set.
+	reader.Escape = `\`
+	dataset = tabula.NewDataset(tabula.DatasetModeRows, nil, nil)
+
+	e = reader.Read(strings.NewReader(input), dataset)
+	if e != nil {
+		t.Fatal(e)
+	}
+
+	exp = tabula.Row{
+		tabula.NewRecordString("C:\new"),
+		tabula.NewRecordString(`say "hi"`),
+		tabula.NewRecordString("a,b"),
+	}
+
+	assert(t, &exp, dataset.GetRow(0), true)
+}
+
+func TestDSVReaderError(t *testing.T) {
+	types := []int{tabula.TInteger, tabula.TReal}
+
+	inputs := []string{
+		"1,1.5\n2,x\n",
+		"1,1.5\n\n2\n",
+		"1,\"1.5\n",
+	}
+	exps := []tabula.ReadError{{
+		Line:   2,
+		Column: 2,
+		Value:  "x",
+	}, {
+		Line: 3,
+		Err:  tabula.ErrMisColLength,
+	}, {
+		Line:   1,
+		Column: 2,
+		Value:  "1.5",
+		Err:    tabula.ErrUnclosedQuote,
+	}}
+
+	for x, input := range inputs {
+		dataset := tabula.NewDataset(tabula.DatasetModeRows, types,
+			nil)
+
+		e := tabula.NewDSVReader().Read(strings.NewReader(input),
+			dataset)
+
+		got, ok := e.(*tabula.ReadError)
+		if !ok {
+			t.Fatalf("expecting ReadError, got %v", e)
+		}
+
+		assert(t, exps[x].Line, got.Line, true)
+		assert(t, exps[x].Column, got.Column, true)
+		assert(t, exps[x].Value, got.Value, true)
+		if exps[x].Err != nil {
+			assert(t, exps[x].Err, got.Err, true)
+		}
+	}
+}
@@ -0,0 +1,242 @@
+// Copyright 2017 M. Shulhan <ms@kilabit.info>. All rights reserved.
+// Use of this source code is governed by a BSD-style license that can be found
+// in the LICENSE file.
+
+package tabula
+
+import (
+	"bufio"
+	"bytes"
+	"io"
+	"strconv"
+
+	"github.com/shuLhan/tekstus"
+)
+
+//
+// DSVWriter write dataset into DSV (delimiter separated values) format, e.g.
+// CSV.
+//
+// String value is escaped using the same way as Columns.Join, where each
+// delimiter in value is prefixed with escape, and new line and carriage
+// return is written as escape followed by "n" and "r". If quote is not
+// empty, string value is enclosed with quote and only quote and escape
+// characters is escaped.
+//
+// Output from writer can be read back using DSVReader with the same
+// delimiter, quote, escape, and missing value options. Null record is read
+// back as null if NullValue is one of column missing values.
+//
+type DSVWriter struct {
+	// Delimiter separate each field in line. If its empty, it will
+	// default to DefaultDelimiter.
+	Delimiter string
+	// Quote enclose string value. If its empty, string value will not be
+	// quoted.
+	Quote string
+	// Escape will be inserted before special character in string value.
+	// If its empty or equal to quote, quote in value will be doubled.
+	Escape string
+	// Header if its true, the first line will contain the name of
+	// columns.
+	Header bool
+	// FloatFormat define the format for real value, see
+	// strconv.FormatFloat. Default to 'f'.
+	FloatFormat byte
+	// FloatPrecision define the number of digits for real value, see
+	// strconv.FormatFloat. Default to -1, which is the smallest number of
+	// digits necessary to represent the value.
+	FloatPrecision int
+	// MissingValue will be written for record that contain missing value.
+	MissingValue string
+	// NullValue will be written for null record, see Record.SetNull.
+	// Default to empty string.
+	NullValue string
+}
+
+//
+// NewDSVWriter create and return new DSV writer with default delimiter,
+// quote, escape, float format, and missing value.
+//
+func NewDSVWriter() *DSVWriter {
+	return &DSVWriter{
+		Delimiter:      DefaultDelimiter,
+		Quote:          DefaultQuote,
+		Escape:         DefaultEscape,
+		FloatFormat:    'f',
+		FloatPrecision: -1,
+		MissingValue:   DefaultMissingValue,
+	}
+}
+
+//
+// WriteFile create or truncate `file` and write dataset `ds` into it.
+//
+func (writer *DSVWriter) WriteFile(file string, ds DatasetInterface) (
+	e error,
+) {
+	f, e := CreateFile(file)
+	if e != nil {
+		return e
+	}
+
+	e = writer.Write(f, ds)
+
+	errClose := f.Close()
+	if e == nil {
+		e = errClose
+	}
+
+	return
+}
+
+//
+// Write all rows in dataset `ds` into `w`. Dataset is read based on its mode
+// without transposing.
+//
+func (writer *DSVWriter) Write(w io.Writer, ds DatasetInterface) (e error) {
+	bw := bufio.NewWriter(w)
+	delim := []byte(writer.Delimiter)
+	if len(delim) == 0 {
+		delim = []byte(DefaultDelimiter)
+	}
+
+	var line []byte
+
+	if writer.Header {
+		for x, name := range ds.GetColumnsName() {
+			if x > 0 {
+				line = append(line, delim...)
+			}
+			line = writer.appendString(line, []byte(name), delim)
+		}
+		line = append(line, '\n')
+
+		_, e = bw.Write(line)
+		if e != nil {
+			return
+		}
+	}
+
+	nrow := ds.Len()
+	ncol := ds.GetNColumn()
+	types := ds.GetColumnsType()
+	tfs := columnsTimeFormat(ds)
+
+	for r := 0; r < nrow; r++ {
+		line = line[:0]
+
+		for x := 0; x < ncol; x++ {
+			if x > 0 {
+				line = append(line, delim...)
+			}
+			t := TString
+			if x < len(types) {
+				t = types[x]
+			}
+			line = writer.appendRecord(line, getRecordAt(ds, r, x), t,
+				timeFormatAt(tfs, x), delim)
+		}
+		line = append(line, '\n')
+
+		_, e = bw.Write(line)
+		if e != nil {
+			return
+		}
+	}
+
+	return bw.Flush()
+}
+
+//
+// getRecordAt return record at row `r` and column `x`, based on dataset mode,
+// or nil if its not exist.
+//
+func getRecordAt(ds DatasetInterface, r, x int) *Record {
+	if ds.GetMode() == DatasetModeColumns {
+		cols := ds.GetColumns()
+		if x >= cols.Len() || r >= (*cols)[x].Len() {
+			return nil
+		}
+		return (*cols)[x].Records[r]
+	}
+
+	row := ds.GetRow(r)
+	if row == nil {
+		return nil
+	}
+	return row.GetRecord(x)
+}
+
+//
+// appendRecord convert record into bytes and append it to `line`. Time is
+// formatted using `tf`. Nil record is written as empty string if column type
+// `t` is string, otherwise as missing value. Null record is written as null
+// value.
+//
+func (writer *DSVWriter) appendRecord(line []byte, rec *Record, t int,
+	tf *TimeFormat, delim []byte,
+) []byte {
+	if rec == nil || rec.IsNil() {
+		if t == TString {
+			return line
+		}
+		return append(line, writer.MissingValue...)
+	}
+	if rec.IsNull() {
+		return append(line, writer.NullValue...)
+	}
+	if rec.IsMissingValue() {
+		return append(line, writer.MissingValue...)
+	}
+
+	switch rec.Type() {
+	case TInteger:
+		return strconv.AppendInt(line, rec.Integer(), 10)
+	case TReal:
+		format := writer.FloatFormat
+		if format == 0 {
+			format = 'f'
+		}
+		return strconv.AppendFloat(line, rec.Float(), format,
+			writer.FloatPrecision, 64)
+	case TTime:
+		return writer.appendString(line, []byte(tf.Format(rec.Time())),
+			delim)
+	case TDecimal:
+		return append(line, rec.String()...)
+	}
+
+	return writer.appendString(line, rec.Bytes(), delim)
+}
+
+//
+// appendString escape and quote string value `v` and append it to `line`.
+//
+func (writer *DSVWriter) appendString(line, v, delim []byte) []byte {
+	quote := []byte(writer.Quote)
+	esc := []byte(writer.Escape)
+
+	if len(quote) == 0 {
+		if len(esc) > 0 {
+			v, _ = tekstus.BytesEncapsulate(esc, v, esc, nil)
+			v, _ = tekstus.BytesEncapsulate(delim, v, esc, nil)
+			v = bytes.Replace(v, []byte{'\n'},
+				[]byte(writer.Escape+"n"), -1)
+			v = bytes.Replace(v, []byte{'\r'},
+				[]byte(writer.Escape+"r"), -1)
+		}
+		return append(line, v...)
+	}
+
+	if len(esc) == 0 || writer.Escape == writer.Quote {
+		v, _ = tekstus.BytesEncapsulate(quote, v, quote, nil)
+	} else {
+		v, _ = tekstus.BytesEncapsulate(esc, v, esc, nil)
+		v, _ = tekstus.BytesEncapsulate(quote, v, esc, nil)
+	}
+
+	line = append(line, quote...)
+	line = append(line, v...)
+	return append(line, quote...)
+}
@@ -0,0 +1,198 @@
+// Copyright 2017 M. Shulhan <ms@kilabit.info>. All rights reserved.
+// Use of this source code is governed by a BSD-style license that can be found
+// in the LICENSE file.
+
+package tabula_test
+
+import (
+	"bytes"
+	"github.com/shuLhan/tabula"
+	"math"
+	"strings"
+	"testing"
+)
+
+func createDSVWriterDataset(t *testing.T, mode int, withNewLine bool) (
+	*tabula.Dataset,
+) {
+	dataset := tabula.NewDataset(mode, datasetTypes, datasetNames)
+
+	e := populateWithRows(dataset)
+	if e != nil {
+		t.Fatal(e)
+	}
+
+	missing := tabula.Row{
+		tabula.NewRecordInt(math.MinInt64),
+		tabula.NewRecordReal(math.Inf(-1)),
+		tabula.NewRecordString("?"),
+	}
+	dataset.PushRow(&missing)
+
+	special := tabula.Row{
+		tabula.NewRecordInt(-10),
+		tabula.NewRecordReal(0.1),
+		tabula.NewRecordString("a,\"b\"\\c"),
+	}
+	if withNewLine {
+		special[2].SetString("a,\"b\"\\c\nd\r\ne\\n")
+	}
+	dataset.PushRow(&special)
+
+	return dataset
+}
+
+func TestDSVWriterWrite(t *testing.T) {
+	dataset := createDSVWriterDataset(t, tabula.DatasetModeRows, true)
+	writer := tabula.NewDSVWriter()
+	writer.Header = true
+
+	var out bytes.Buffer
+
+	e := writer.Write(&out, dataset)
+	if e != nil {
+		t.Fatal(e)
+	}
+
+	exp := `"int","real","string"
+0,1,"A"
+1,1.1,"B"
+2,1.2,"A"
+3,1.3,"B"
+4,1.4,"C"
+5,1.5,"D"
+6,1.6,"C"
+7,1.7,"D"
+8,1.8,"E"
+9,1.9,"F"
+?,?,?
+-10,0.1,"a,""b""\c
+d` + "\r\n" + `e\n"
+`
+	assert(t, exp, out.String(), true)
+
+	writer.Escape = "\\"
+	out.Reset()
+
+	e = writer.Write(&out, dataset)
+	if e != nil {
+		t.Fatal(e)
+	}
+
+	exp = strings.Replace(exp, `"a,""b""\c`, `"a,\"b\"\\c`, 1)
+	exp = strings.Replace(exp, `e\n"`, `e\\n"`, 1)
+
+	assert(t, exp, out.String(), true)
+}
+
+func TestDSVWriterRoundTrip(t *testing.T) {
+	modes := []int{
+		tabula.DatasetModeRows,
+		tabula.DatasetModeColumns,
+		tabula.DatasetModeMatrix,
+	}
+	options := []struct {
+		quote  string
+		escape string
+	}{
+		{quote: tabula.DefaultQuote},
+		{quote: tabula.DefaultQuote, escape: "\\"},
+		{escape: "\\"},
+	}
+
+	for _, mode := range modes {
+		for _, opt := range options {
+			dataset := createDSVWriterDataset(t, mode, true)
+
+			// Nil record in numeric column is written as missing
+			// value.
+			nilRow := tabula.Row{
+				tabula.NewRecord(),
+				tabula.NewRecord(),
+				tabula.NewRecordString(""),
+			}
+			dataset.PushRow(&nilRow)
+
+			writer := tabula.NewDSVWriter()
+			writer.Header = true
+			writer.Quote = opt.quote
+			writer.Escape = opt.escape
+			writer.Delimiter = "\t"
+
+			var out bytes.Buffer
+
+			e := writer.Write(&out, dataset)
+			if e != nil {
+				t.Fatal(e)
+			}
+
+			reader := tabula.NewDSVReader()
+			reader.Header = true
+			reader.Quote = opt.quote
+			reader.Escape = opt.escape
+			reader.Delimiter = "\t"
+
+			got := tabula.NewDataset(mode, datasetTypes, nil)
+
+			e = reader.Read(&out, got)
+			if e != nil {
+				t.Fatal(e)
+			}
+
+			nilRow[0].SetMissingValue(tabula.TInteger)
+			nilRow[1].SetMissingValue(tabula.TReal)
+
+			assert(t, dataset.GetColumnsName(),
+				got.GetColumnsName(), true)
+			assert(t, dataset.GetDataAsRows(), got.GetDataAsRows(),
+				true)
+		}
+	}
+}
+
+func TestDSVWriterNull(t *testing.T) {
+	types := []int{tabula.TInteger, tabula.TString}
+	dataset := tabula.NewDataset(tabula.DatasetModeRows, types, nil)
+
+	dataset.PushRow(&tabula.Row{
+		tabula.NewRecordInt(1),
+		tabula.NewRecordString("a"),
+	})
+	dataset.PushRow(&tabula.Row{
+		tabula.NewRecordNull(tabula.TInteger),
+		tabula.NewRecordNull(tabula.TString),
+	})
+
+	writer := tabula.NewDSVWriter()
+
+	var out bytes.Buffer
+
+	e := writer.Write(&out, dataset)
+	if e != nil {
+		t.Fatal(e)
+	}
+
+	assert(t, "1,\"a\"\n,\n", out.String(), true)
+
+	out.Reset()
+	writer.NullValue = "NULL"
+
+	e = writer.Write(&out, dataset)
+	if e != nil {
+		t.Fatal(e)
+	}
+
+	assert(t, "1,\"a\"\nNULL,NULL\n", out.String(), true)
+
+	got := tabula.NewDataset(tabula.DatasetModeRows, types, nil)
+	for x := range got.Columns {
+		got.Columns[x].MissingValues = []string{"NULL"}
+	}
+
+	e = tabula.NewDSVReader().Read(&out, got)
+	if e != nil {
+		t.Fatal(e)
+	}
+
+	assert(t, dataset.GetDataAsRows(), got.GetDataAsRows(), true)
+}
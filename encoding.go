@@ -0,0 +1,243 @@
+// Copyright 2017 M. Shulhan <ms@kilabit.info>. All rights reserved.
+// Use of this source code is governed by a BSD-style license that can be found
+// in the LICENSE file.
+
+package tabula
+
+import (
+	"bufio"
+	"errors"
+	"io"
+	"strings"
+	"unicode/utf16"
+	"unicode/utf8"
+)
+
+const (
+	// EncodingUTF8 read input as UTF-8, with the byte order mark
+	// removed.
+	EncodingUTF8 = "utf-8"
+	// EncodingLatin1 read input as ISO-8859-1.
+	EncodingLatin1 = "iso-8859-1"
+	// EncodingWindows1252 read input as Windows-1252, the superset of
+	// ISO-8859-1 that define printable characters in 0x80-0x9F.
+	EncodingWindows1252 = "windows-1252"
+	// EncodingUTF16 read input as UTF-16 with byte order detected from
+	// the byte order mark. If input does not have it, big endian is
+	// used.
+	EncodingUTF16 = "utf-16"
+	// EncodingUTF16LE read input as UTF-16 little endian.
+	EncodingUTF16LE = "utf-16le"
+	// EncodingUTF16BE read input as UTF-16 big endian.
+	EncodingUTF16BE = "utf-16be"
+)
+
+var (
+	// ErrUnknownEncoding returned when the character encoding is not
+	// supported.
+	ErrUnknownEncoding = errors.New("tabula: unknown character encoding")
+)
+
+//
+// windows1252 map the characters 0x80-0x9F in Windows-1252 into Unicode.
+// Undefined character is mapped to its ISO-8859-1 control character.
+//
+var windows1252 = [32]rune{
+	0x20AC, 0x0081, 0x201A, 0x0192, 0x201E, 0x2026, 0x2020, 0x2021,
+	0x02C6, 0x2030, 0x0160, 0x2039, 0x0152, 0x008D, 0x017D, 0x008F,
+	0x0090, 0x2018, 0x2019, 0x201C, 0x201D, 0x2022, 0x2013, 0x2014,
+	0x02DC, 0x2122, 0x0161, 0x203A, 0x0153, 0x009D, 0x017E, 0x0178,
+}
+
+//
+// decodeReader convert the input from other character encoding into UTF-8.
+//
+type decodeReader struct {
+	br *bufio.Reader
+	// next read one character from input.
+	next func() (rune, error)
+	// bigEndian is the byte order of UTF-16 input.
+	bigEndian bool
+	// unit contain the UTF-16 code unit that has been read but not
+	// decoded yet.
+	unit    rune
+	hasUnit bool
+	buf     [utf8.UTFMax]byte
+	// pending contain the encoded character that does not fit in the
+	// last read.
+	pending []byte
+	e       error
+}
+
+//
+// NewDecodeReader return the reader that convert `r` from character
+// `encoding` into UTF-8. The encoding name is case insensitive, and must be
+// one of the Encoding constant or their common alias: "utf8", "latin1",
+// "iso-8859-1", "cp1252", "windows-1252", "utf-16", "utf-16le", or
+// "utf-16be". Empty encoding will return `r` as is.
+//
+// Byte order mark in the beginning of input is removed.
+//
+func NewDecodeReader(r io.Reader, encoding string) (io.Reader, error) {
+	dr := &decodeReader{
+		br: bufio.NewReader(r),
+	}
+
+	switch strings.ToLower(encoding) {
+	case "":
+		return r, nil
+	case EncodingUTF8, "utf8":
+		dr.next = dr.nextUTF8
+		if bom, _ := dr.br.Peek(3); string(bom) == "\xef\xbb\xbf" {
+			_, _ = dr.br.Discard(3)
+		}
+	case EncodingLatin1, "latin1", "latin-1", "iso8859-1":
+		dr.next = dr.nextLatin1
+	case EncodingWindows1252, "cp1252":
+		dr.next = dr.nextWindows1252
+	case EncodingUTF16, "utf16":
+		dr.next = dr.nextUTF16
+		dr.bigEndian = true
+		dr.discardBOM()
+	case EncodingUTF16LE:
+		dr.next = dr.nextUTF16
+		dr.discardBOM()
+	case EncodingUTF16BE:
+		dr.next = dr.nextUTF16
+		dr.bigEndian = true
+		dr.discardBOM()
+	default:
+		return nil, ErrUnknownEncoding
+	}
+
+	return dr, nil
+}
+
+//
+// discardBOM remove the UTF-16 byte order mark, and set the byte order
+// based on it.
+//
+func (dr *decodeReader) discardBOM() {
+	bom, _ := dr.br.Peek(2)
+	if len(bom) < 2 {
+		return
+	}
+	if bom[0] == 0xFE && bom[1] == 0xFF {
+		dr.bigEndian = true
+	} else if bom[0] == 0xFF && bom[1] == 0xFE {
+		dr.bigEndian = false
+	} else {
+		return
+	}
+	_, _ = dr.br.Discard(2)
+}
+
+//
+// Read the decoded input into `p`.
+//
+func (dr *decodeReader) Read(p []byte) (n int, e error) {
+	for n < len(p) {
+		if len(dr.pending) > 0 {
+			c := copy(p[n:], dr.pending)
+			dr.pending = dr.pending[c:]
+			n += c
+			continue
+		}
+		if dr.e != nil {
+			break
+		}
+
+		var r rune
+
+		r, dr.e = dr.next()
+		if dr.e != nil {
+			break
+		}
+
+		c := utf8.EncodeRune(dr.buf[:], r)
+		dr.pending = dr.buf[:c]
+	}
+
+	if n == 0 && len(p) > 0 {
+		return 0, dr.e
+	}
+
+	return n, nil
+}
+
+func (dr *decodeReader) nextUTF8() (r rune, e error) {
+	r, _, e = dr.br.ReadRune()
+	return
+}
+
+func (dr *decodeReader) nextLatin1() (r rune, e error) {
+	b, e := dr.br.ReadByte()
+	return rune(b), e
+}
+
+func (dr *decodeReader) nextWindows1252() (r rune, e error) {
+	b, e := dr.br.ReadByte()
+	if b >= 0x80 && b <= 0x9F {
+		return windows1252[b-0x80], e
+	}
+	return rune(b), e
+}
+
+func (dr *decodeReader) nextUTF16() (r rune, e error) {
+	r1, e := dr.readUnit()
+	if e != nil {
+		return 0, e
+	}
+	if !utf16.IsSurrogate(r1) {
+		return r1, nil
+	}
+	if r1 >= 0xDC00 {
+		// Low surrogate without high surrogate.
+		return utf8.RuneError, nil
+	}
+
+	r2, e := dr.readUnit()
+	if e == io.EOF {
+		return utf8.RuneError, nil
+	}
+	if e != nil {
+		return 0, e
+	}
+
+	r = utf16.DecodeRune(r1, r2)
+	if r == utf8.RuneError {
+		// Keep the second unit to be read on the next call.
+		dr.unit = r2
+		dr.hasUnit = true
+	}
+
+	return r, nil
+}
+
+//
+// readUnit read one UTF-16 code unit from input. Incomplete code unit in the
+// end of input is returned as io.ErrUnexpectedEOF.
+//
+func (dr *decodeReader) readUnit() (r rune, e error) {
+	if dr.hasUnit {
+		dr.hasUnit = false
+		return dr.unit, nil
+	}
+
+	b1, e := dr.br.ReadByte()
+	if e != nil {
+		return 0, e
+	}
+	b2, e := dr.br.ReadByte()
+	if e == io.EOF {
+		return 0, io.ErrUnexpectedEOF
+	}
+	if e != nil {
+		return 0, e
+	}
+
+	if dr.bigEndian {
+		return rune(b1)<<8 | rune(b2), nil
+	}
+	return rune(b2)<<8 | rune(b1), nil
+}
@@ -0,0 +1,97 @@
+// Copyright 2017 M. Shulhan <ms@kilabit.info>. All rights reserved.
+// Use of this source code is governed by a BSD-style license that can be found
+// in the LICENSE file.
+
+package tabula_test
+
+import (
+	"bytes"
+	"github.com/shuLhan/tabula"
+	"io/ioutil"
+	"testing"
+)
+
+func TestNewDecodeReader(t *testing.T) {
+	cases := []struct {
+		encoding string
+		in       []byte
+		exp      string
+	}{{
+		encoding: "",
+		in:       []byte("caf\xc3\xa9"),
+		exp:      "café",
+	}, {
+		encoding: tabula.EncodingUTF8,
+		in:       []byte("\xef\xbb\xbfcaf\xc3\xa9"),
+		exp:      "café",
+	}, {
+		encoding: "Latin1",
+		in:       []byte("caf\xe9 \x80"),
+		exp:      "café \u0080",
+	}, {
+		encoding: tabula.EncodingWindows1252,
+		in:       []byte("caf\xe9 \x80\x96"),
+		exp:      "café €–",
+	}, {
+		encoding: tabula.EncodingUTF16,
+		in:       []byte{0xff, 0xfe, 'a', 0, 0xe9, 0, 0x3d, 0xd8, 0x00, 0xde},
+		exp:      "aé😀",
+	}, {
+		encoding: tabula.EncodingUTF16,
+		in:       []byte{0xfe, 0xff, 0, 'a', 0, 0xe9},
+		exp:      "aé",
+	}, {
+		encoding: tabula.EncodingUTF16LE,
+		in:       []byte{'a', 0, 0x3d, 0xd8, 'b', 0},
+		exp:      "a�b",
+	}, {
+		encoding: tabula.EncodingUTF16BE,
+		in:       []byte{0, 'a', 0, 'b'},
+		exp:      "ab",
+	}}
+
+	for _, c := range cases {
+		r, e := tabula.NewDecodeReader(bytes.NewReader(c.in), c.encoding)
+		if e != nil {
+			t.Fatal(e)
+		}
+
+		got, e := ioutil.ReadAll(r)
+		if e != nil {
+			t.Fatal(e)
+		}
+
+		assert(t, c.exp, string(got), true)
+	}
+
+	_, e := tabula.NewDecodeReader(nil, "ebcdic")
+
+	assert(t, tabula.ErrUnknownEncoding, e, true)
+}
+
+func TestDSVReaderEncoding(t *testing.T) {
+	// "name;price\nCrème;€ 1.234,50\n" in Windows-1252.
+	input := []byte("name;price\nCr\xe8me;\x80 1.234,50\n")
+
+	reader := tabula.NewDSVReader()
+	reader.Delimiter = ";"
+	reader.Header = true
+	reader.Encoding = tabula.EncodingWindows1252
+	reader.NumberFormat = &tabula.NumberFormat{
+		Decimal:   ",",
+		Thousands: ".",
+		Symbols:   []string{"€"},
+	}
+
+	ds := tabula.NewDataset(tabula.DatasetModeRows,
+		[]int{tabula.TString, tabula.TReal}, nil)
+
+	e := reader.Read(bytes.NewReader(input), ds)
+	if e != nil {
+		t.Fatal(e)
+	}
+
+	assert(t, []string{"name", "price"}, ds.GetColumnsName(), true)
+	assert(t, "Crème", (*ds.Rows[0])[0].String(), true)
+	assert(t, 1234.5, (*ds.Rows[0])[1].Float(), true)
+}
@@ -0,0 +1,286 @@
+// Copyright 2017 M. Shulhan <ms@kilabit.info>. All rights reserved.
+// Use of this source code is governed by a BSD-style license that can be found
+// in the LICENSE file.
+
+package tabula
+
+import (
+	"bufio"
+	"bytes"
+	"errors"
+	"io"
+	"unicode/utf8"
+)
+
+const (
+	// FixedWidthAlignDefault align numeric value to the right and string
+	// value to the left.
+	FixedWidthAlignDefault = 0
+	// FixedWidthAlignLeft align value to the left, the padding is added
+	// after the value.
+	FixedWidthAlignLeft = 1
+	// FixedWidthAlignRight align value to the right, the padding is added
+	// before the value.
+	FixedWidthAlignRight = 2
+)
+
+var (
+	// ErrInvalidFixedWidth returned when the field position is not
+	// valid.
+	ErrInvalidFixedWidth = errors.New("tabula: invalid fixed-width field")
+	// ErrValueTooLong returned when the value is longer than field
+	// width.
+	ErrValueTooLong = errors.New("tabula: value is longer than field width")
+)
+
+//
+// FixedWidthField define the name, type, and position of column in
+// fixed-width line. Start and Width is in bytes, or in characters if
+// FixedWidthReader.Encoding is set, where Start is started from zero.
+//
+type FixedWidthField struct {
+	// Name of column.
+	Name string
+	// Type of column.
+	Type int
+	// Start is the position of the first byte or character of field.
+	Start int
+	// Width is the number of bytes or characters of field.
+	Width int
+	// Align of value when writing, one of FixedWidthAlign constant.
+	Align int
+	// TimeFormat define the layouts and time zone of TTime field. If its
+	// nil, DefaultTimeLayouts in UTC is used.
+	TimeFormat *TimeFormat
+	// MissingValues contain the values, after trimmed, that is set to
+	// null.
+	MissingValues []string
+	// Scale is the number of digits after decimal point of TDecimal
+	// field, see Column.Scale.
+	Scale int
+}
+
+//
+// FixedWidthReader read fixed-width text, where each column is located at
+// fixed position in line, and load the rows into dataset.
+//
+// The dataset columns will be replaced with Fields, in the same order, and
+// the mode is not changed.
+//
+type FixedWidthReader struct {
+	// Fields contain the schema of line.
+	Fields []FixedWidthField
+	// Pad is the character used to fill the string field. It will be
+	// trimmed from both side of string value. Default to space.
+	Pad byte
+	// NoTrim if its true, string value will not be trimmed. Numeric
+	// value is always trimmed from spaces.
+	NoTrim bool
+	// Skip number of lines at the beginning of input.
+	Skip int
+	// MaxRows maximum number of rows to be read. If its zero or negative,
+	// all rows will be read.
+	MaxRows int
+	// MissingValue if its not empty, field with this value, after
+	// trimmed, will be set to missing value based on the column type.
+	// Empty numeric value is always set to missing value.
+	MissingValue string
+	// Encoding is the character encoding of input, see NewDecodeReader.
+	// If its empty, input is read as UTF-8 and the field Start and Width
+	// is in bytes, otherwise its in characters, so the field position in
+	// single byte encoding, like ISO-8859-1, is not changed.
+	Encoding string
+	// NumberFormat if its not nil, will be used to convert the value for
+	// integer and real field.
+	NumberFormat *NumberFormat
+}
+
+//
+// NewFixedWidthReader create and return new fixed-width reader with
+// `fields` as schema.
+//
+func NewFixedWidthReader(fields []FixedWidthField) *FixedWidthReader {
+	return &FixedWidthReader{
+		Fields:       fields,
+		Pad:          ' ',
+		MissingValue: DefaultMissingValue,
+	}
+}
+
+//
+// ReadFile open fixed-width file and read all of its rows into dataset
+// `ds`.
+//
+func (reader *FixedWidthReader) ReadFile(file string, ds DatasetInterface) (
+	e error,
+) {
+	f, e := OpenFile(file)
+	if e != nil {
+		return e
+	}
+
+	e = reader.Read(f, ds)
+
+	errClose := f.Close()
+	if e == nil {
+		e = errClose
+	}
+
+	return
+}
+
+//
+// Read all lines from `r` into dataset `ds`. Empty line is ignored. Line
+// that is shorter than the end of field is read as is, and field that start
+// after the end of line is read as empty.
+//
+func (reader *FixedWidthReader) Read(r io.Reader, ds DatasetInterface) (
+	e error,
+) {
+	types := make([]int, len(reader.Fields))
+	names := make([]string, len(reader.Fields))
+
+	for x, field := range reader.Fields {
+		if field.Start < 0 || field.Width <= 0 {
+			return ErrInvalidFixedWidth
+		}
+		types[x] = field.Type
+		names[x] = field.Name
+	}
+
+	r, e = NewDecodeReader(r, reader.Encoding)
+	if e != nil {
+		return
+	}
+
+	ds.Init(ds.GetMode(), types, names)
+
+	cols := ds.GetColumns()
+	for x := range *cols {
+		(*cols)[x].TimeFormat = reader.Fields[x].TimeFormat
+		(*cols)[x].MissingValues = reader.Fields[x].MissingValues
+		(*cols)[x].Scale = reader.Fields[x].Scale
+	}
+
+	pad := reader.Pad
+	if pad == 0 {
+		pad = ' '
+	}
+	cutset := string(pad)
+
+	cut := fixedWidthCut
+	if reader.Encoding != "" {
+		cut = fixedWidthCutRunes
+	}
+
+	scanner := bufio.NewScanner(r)
+	scanner.Buffer(nil, 1024*1024*1024)
+
+	n := 0
+	nrow := 0
+
+	for scanner.Scan() {
+		n++
+		if n <= reader.Skip {
+			continue
+		}
+		if reader.MaxRows > 0 && nrow >= reader.MaxRows {
+			break
+		}
+
+		line := bytes.TrimSuffix(scanner.Bytes(), []byte{'\r'})
+		if len(line) == 0 {
+			continue
+		}
+
+		row := make(Row, len(reader.Fields))
+
+		for x, field := range reader.Fields {
+			v := cut(line, field.Start, field.Width)
+			if field.Type != TString {
+				v = bytes.TrimSpace(v)
+			} else if !reader.NoTrim {
+				v = bytes.Trim(v, cutset)
+			}
+
+			col := &(*cols)[x]
+
+			row[x], e = reader.newRecord(string(v), col)
+			if e == nil {
+				e = col.Encode(row[x])
+			}
+			if e != nil {
+				return &ReadError{
+					Line:   n,
+					Column: x + 1,
+					Value:  string(v),
+					Err:    e,
+				}
+			}
+		}
+
+		ds.PushRow(&row)
+		nrow++
+	}
+
+	return scanner.Err()
+}
+
+//
+// newRecord create new record from value `v` using the type, time format,
+// missing values, and scale of column `col`.
+//
+func (reader *FixedWidthReader) newRecord(v string, col *Column) (
+	*Record, error,
+) {
+	t := col.Type
+	if col.IsMissingValue(v) {
+		return NewRecordNull(t), nil
+	}
+	if (t != TString && len(v) == 0) ||
+		(len(reader.MissingValue) > 0 && v == reader.MissingValue) {
+		rec := NewRecord()
+		rec.SetMissingValue(t)
+		return rec, nil
+	}
+
+	return col.newRecord(v, reader.NumberFormat)
+}
+
+//
+// fixedWidthCut return the bytes in `line` from `start` until `start+width`
+// or until the end of line.
+//
+func fixedWidthCut(line []byte, start, width int) []byte {
+	if start >= len(line) {
+		return nil
+	}
+
+	end := start + width
+	if end > len(line) {
+		end = len(line)
+	}
+
+	return line[start:end]
+}
+
+//
+// fixedWidthCutRunes return the bytes in `line` from character `start`
+// until `start+width` or until the end of line.
+//
+func fixedWidthCutRunes(line []byte, start, width int) []byte {
+	line = line[runesOffset(line, start):]
+	return line[:runesOffset(line, width)]
+}
+
+//
+// runesOffset return the offset of `n`-th character in `b`, or the length of
+// `b` if it has less than `n` characters.
+//
+func runesOffset(b []byte, n int) (off int) {
+	for ; n > 0 && off < len(b); n-- {
+		_, size := utf8.DecodeRune(b[off:])
+		off += size
+	}
+	return off
+}
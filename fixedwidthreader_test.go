@@ -0,0 +1,154 @@
+// Copyright 2017 M. Shulhan <ms@kilabit.info>. All rights reserved.
+// Use of this source code is governed by a BSD-style license that can be found
+// in the LICENSE file.
+
+package tabula_test
+
+import (
+	"fmt"
+	"github.com/shuLhan/tabula"
+	"strings"
+	"testing"
+)
+
+var fixedWidthFields = []tabula.FixedWidthField{{
+	Name:  "id",
+	Type:  tabula.TInteger,
+	Start: 0,
+	Width: 4,
+}, {
+	Name:  "name",
+	Type:  tabula.TString,
+	Start: 5,
+	Width: 8,
+}, {
+	Name:  "amount",
+	Type:  tabula.TReal,
+	Start: 13,
+	Width: 7,
+}}
+
+func TestFixedWidthReader(t *testing.T) {
+	input := "HEADER\n" +
+		"0001 alpha     12.5\n" +
+		"  22 beta  b  -0.25\r\n" +
+		"\n" +
+		"0333 ?            \n" +
+		"   4 short\n"
+
+	modes := []int{
+		tabula.DatasetModeRows,
+		tabula.DatasetModeColumns,
+		tabula.DatasetModeMatrix,
+	}
+
+	exp := "[1 22 333 4][alpha beta  b ? short][12.5 -0.25 -Inf -Inf]"
+
+	for _, mode := range modes {
+		reader := tabula.NewFixedWidthReader(fixedWidthFields)
+		reader.Skip = 1
+
+		ds := tabula.NewDataset(mode, nil, nil)
+
+		e := reader.Read(strings.NewReader(input), ds)
+		if e != nil {
+			t.Fatal(e)
+		}
+
+		assert(t, mode, ds.GetMode(), true)
+		assert(t, []string{"id", "name", "amount"}, ds.GetColumnsName(),
+			true)
+		assert(t, 4, ds.Len(), true)
+
+		got := ""
+		for _, col := range *ds.GetDataAsColumns() {
+			got += fmt.Sprint(col.Records)
+		}
+
+		assert(t, exp, got, true)
+		cols := ds.GetDataAsColumns()
+		assert(t, true, (*cols)[1].Records[2].IsMissingValue(), true)
+	}
+}
+
+func TestFixedWidthReaderError(t *testing.T) {
+	input := "0001 alpha     12.5\n" +
+		"000x beta       1.0\n"
+
+	ds := tabula.NewDataset(tabula.DatasetModeRows, nil, nil)
+
+	e := tabula.NewFixedWidthReader(fixedWidthFields).Read(
+		strings.NewReader(input), ds)
+
+	rerr, ok := e.(*tabula.ReadError)
+
+	assert(t, true, ok, true)
+	assert(t, 2, rerr.Line, true)
+	assert(t, 1, rerr.Column, true)
+	assert(t, "000x", rerr.Value, true)
+
+	fields := []tabula.FixedWidthField{{Name: "x", Width: 0}}
+
+	e = tabula.NewFixedWidthReader(fields).Read(strings.NewReader(input), ds)
+
+	assert(t, tabula.ErrInvalidFixedWidth, e, true)
+}
+
+func TestFixedWidthReaderDecimal(t *testing.T) {
+	input := "0001   12.5\n0002  -0.25\n0003       \n"
+
+	fields := []tabula.FixedWidthField{{
+		Name:  "id",
+		Type:  tabula.TInteger,
+		Width: 4,
+	}, {
+		Name:  "amount",
+		Type:  tabula.TDecimal,
+		Start: 4,
+		Width: 7,
+		Scale: 2,
+	}}
+
+	ds := tabula.NewDataset(tabula.DatasetModeRows, nil, nil)
+
+	e := tabula.NewFixedWidthReader(fields).Read(strings.NewReader(input),
+		ds)
+	if e != nil {
+		t.Fatal(e)
+	}
+
+	assert(t, 2, ds.Columns[1].Scale, true)
+	assert(t, "&[1 12.50]&[2 -0.25]&[3 ?]", ds.Rows.String(), true)
+
+	fields[1].Scale = 1
+
+	e = tabula.NewFixedWidthReader(fields).Read(strings.NewReader(input),
+		ds)
+
+	re, ok := e.(*tabula.ReadError)
+	assert(t, true, ok, true)
+	assert(t, 2, re.Line, true)
+	assert(t, tabula.ErrInvalidDecimal, re.Err, true)
+}
+
+func TestFixedWidthReaderEncoding(t *testing.T) {
+	input := "0001 Cr\xe8me    12.5\n0002 na\xefve    -1.0\n"
+
+	ds := tabula.NewDataset(tabula.DatasetModeRows, nil, nil)
+
+	reader := tabula.NewFixedWidthReader(fixedWidthFields)
+	reader.Encoding = tabula.EncodingLatin1
+
+	e := reader.Read(strings.NewReader(input), ds)
+	if e != nil {
+		t.Fatal(e)
+	}
+
+	assert(t, "&[1 Crème 12.5]&[2 naïve -1]", ds.Rows.String(), true)
+
+	reader.Encoding = "ebcdic"
+
+	e = reader.Read(strings.NewReader(input), ds)
+
+	assert(t, tabula.ErrUnknownEncoding, e, true)
+}
@@ -0,0 +1,249 @@
+// Copyright 2017 M. Shulhan <ms@kilabit.info>. All rights reserved.
+// Use of this source code is governed by a BSD-style license that can be found
+// in the LICENSE file.
+
+package tabula
+
+import (
+	"bufio"
+	"io"
+	"strconv"
+)
+
+//
+// FixedWidthWriter write dataset as fixed-width text, where each column is
+// written at fixed position in line.
+//
+type FixedWidthWriter struct {
+	// Fields contain the position of each column, in the same order as
+	// dataset columns. If its empty, the fields will be created using
+	// dataset columns, where the width is the longest value in column,
+	// separated by one pad character.
+	Fields []FixedWidthField
+	// Pad is the character used to fill the field and the gap between
+	// fields. Default to space.
+	Pad byte
+	// ZeroPad if its true, numeric value that is aligned to the right is
+	// filled with zero, after the sign.
+	ZeroPad bool
+	// Truncate if its true, value that is longer than field width will
+	// be truncated, otherwise writing will stop and return
+	// ErrValueTooLong.
+	Truncate bool
+	// FloatFormat and FloatPrecision is used to format real value, see
+	// strconv.FormatFloat. Default to 'f' and -1.
+	FloatFormat    byte
+	FloatPrecision int
+	// MissingValue is the value written for missing record.
+	MissingValue string
+}
+
+//
+// NewFixedWidthWriter create and return new fixed-width writer with
+// `fields` as schema.
+//
+func NewFixedWidthWriter(fields []FixedWidthField) *FixedWidthWriter {
+	return &FixedWidthWriter{
+		Fields:         fields,
+		Pad:            ' ',
+		FloatFormat:    'f',
+		FloatPrecision: -1,
+		MissingValue:   DefaultMissingValue,
+	}
+}
+
+//
+// WriteFile create or truncate `file` and write dataset `ds` into it.
+//
+func (writer *FixedWidthWriter) WriteFile(file string, ds DatasetInterface) (
+	e error,
+) {
+	f, e := CreateFile(file)
+	if e != nil {
+		return e
+	}
+
+	e = writer.Write(f, ds)
+
+	errClose := f.Close()
+	if e == nil {
+		e = errClose
+	}
+
+	return
+}
+
+//
+// Write all rows in dataset `ds` into `w`, one line per row. Dataset is read
+// based on its mode without transposing.
+//
+func (writer *FixedWidthWriter) Write(w io.Writer, ds DatasetInterface) (
+	e error,
+) {
+	fields := writer.Fields
+	if len(fields) == 0 {
+		fields = writer.newFields(ds)
+	}
+	if len(fields) != ds.GetNColumn() {
+		return ErrMisColLength
+	}
+
+	pad := writer.Pad
+	if pad == 0 {
+		pad = ' '
+	}
+
+	lineLen := 0
+	for _, field := range fields {
+		if field.Start < 0 || field.Width <= 0 {
+			return ErrInvalidFixedWidth
+		}
+		if field.Start+field.Width > lineLen {
+			lineLen = field.Start + field.Width
+		}
+	}
+
+	bw := bufio.NewWriter(w)
+	types := ds.GetColumnsType()
+	tfs := columnsTimeFormat(ds)
+	line := make([]byte, lineLen+1)
+	nrow := ds.Len()
+
+	for r := 0; r < nrow; r++ {
+		for x := 0; x < lineLen; x++ {
+			line[x] = pad
+		}
+		line[lineLen] = '\n'
+
+		for x, field := range fields {
+			tf := field.TimeFormat
+			if tf == nil {
+				tf = timeFormatAt(tfs, x)
+			}
+
+			rec := getRecordAt(ds, r, x)
+			v := writer.format(rec, tf)
+
+			if len(v) > field.Width {
+				if !writer.Truncate {
+					return ErrValueTooLong
+				}
+				v = v[:field.Width]
+			}
+
+			dst := line[field.Start : field.Start+field.Width]
+			align := field.Align
+			if align == FixedWidthAlignDefault {
+				align = FixedWidthAlignLeft
+				if types[x] == TInteger || types[x] == TReal ||
+					types[x] == TDecimal {
+					align = FixedWidthAlignRight
+				}
+			}
+
+			fixedWidthPut(dst, v, align,
+				writer.ZeroPad && isNumericRecord(rec))
+		}
+
+		_, e = bw.Write(line)
+		if e != nil {
+			return
+		}
+	}
+
+	return bw.Flush()
+}
+
+//
+// newFields create the fields from dataset columns, where the width of
+// each field is the longest value in column.
+//
+func (writer *FixedWidthWriter) newFields(ds DatasetInterface) (
+	fields []FixedWidthField,
+) {
+	cols := ds.GetColumns()
+	fields = make([]FixedWidthField, len(*cols))
+	nrow := ds.Len()
+
+	for x, col := range *cols {
+		fields[x].Name = col.Name
+		fields[x].Type = col.Type
+		fields[x].TimeFormat = col.TimeFormat
+		fields[x].Width = 1
+		for r := 0; r < nrow; r++ {
+			v := writer.format(getRecordAt(ds, r, x),
+				col.TimeFormat)
+			if len(v) > fields[x].Width {
+				fields[x].Width = len(v)
+			}
+		}
+		if x > 0 {
+			fields[x].Start = fields[x-1].Start + fields[x-1].Width + 1
+		}
+	}
+
+	return fields
+}
+
+//
+// format convert the record into text, where time is formatted using `tf`.
+//
+func (writer *FixedWidthWriter) format(rec *Record, tf *TimeFormat) []byte {
+	if rec == nil || rec.IsNil() || rec.IsMissingValue() {
+		return []byte(writer.MissingValue)
+	}
+
+	switch rec.Type() {
+	case TInteger:
+		return strconv.AppendInt(nil, rec.Integer(), 10)
+	case TReal:
+		format := writer.FloatFormat
+		if format == 0 {
+			format = 'f'
+		}
+		return strconv.AppendFloat(nil, rec.Float(), format,
+			writer.FloatPrecision, 64)
+	}
+
+	return []byte(formatRecordTime(rec, tf))
+}
+
+//
+// fixedWidthPut copy the value `v` into field `dst` based on alignment. The
+// `dst` is already filled with pad character. If `zeroPad` is true and
+// value is aligned to the right, the padding is replaced with zero after
+// the sign.
+//
+func fixedWidthPut(dst, v []byte, align int, zeroPad bool) {
+	if align != FixedWidthAlignRight {
+		copy(dst, v)
+		return
+	}
+
+	start := len(dst) - len(v)
+	copy(dst[start:], v)
+
+	if !zeroPad || start == 0 {
+		return
+	}
+
+	for x := 0; x < start; x++ {
+		dst[x] = '0'
+	}
+	if v[0] == '-' || v[0] == '+' {
+		dst[0] = v[0]
+		dst[start] = '0'
+	}
+}
+
+//
+// isNumericRecord return true if record is integer, real, or decimal and its
+// not a missing value.
+//
+func isNumericRecord(rec *Record) bool {
+	if rec == nil || rec.IsNil() || rec.IsMissingValue() {
+		return false
+	}
+	t := rec.Type()
+	return t == TInteger || t == TReal || t == TDecimal
+}
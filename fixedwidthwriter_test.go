@@ -0,0 +1,157 @@
+// Copyright 2017 M. Shulhan <ms@kilabit.info>. All rights reserved.
+// Use of this source code is governed by a BSD-style license that can be found
+// in the LICENSE file.
+
+package tabula_test
+
+import (
+	"bytes"
+	"github.com/shuLhan/tabula"
+	"math"
+	"strings"
+	"testing"
+)
+
+func createFixedWidthDataset() *tabula.Dataset {
+	ds := tabula.NewDataset(tabula.DatasetModeColumns,
+		[]int{tabula.TInteger, tabula.TString, tabula.TReal},
+		[]string{"id", "name", "amount"})
+
+	rows := []tabula.Row{{
+		tabula.NewRecordInt(1),
+		tabula.NewRecordString("alpha"),
+		tabula.NewRecordReal(12.5),
+	}, {
+		tabula.NewRecordInt(-22),
+		tabula.NewRecordString("beta"),
+		tabula.NewRecordReal(-0.25),
+	}, {
+		tabula.NewRecordInt(math.MinInt64),
+		tabula.NewRecordString("gamma"),
+		tabula.NewRecordReal(math.Inf(-1)),
+	}}
+
+	for x := range rows {
+		ds.PushRow(&rows[x])
+	}
+
+	return ds
+}
+
+func TestFixedWidthWriter(t *testing.T) {
+	ds := createFixedWidthDataset()
+
+	var out bytes.Buffer
+
+	writer := tabula.NewFixedWidthWriter(fixedWidthFields)
+
+	e := writer.Write(&out, ds)
+	if e != nil {
+		t.Fatal(e)
+	}
+
+	exp := "   1 alpha      12.5\n" +
+		" -22 beta      -0.25\n" +
+		"   ? gamma         ?\n"
+
+	assert(t, exp, out.String(), true)
+
+	// Read it back.
+	got := tabula.NewDataset(tabula.DatasetModeColumns, nil, nil)
+
+	e = tabula.NewFixedWidthReader(fixedWidthFields).Read(
+		strings.NewReader(out.String()), got)
+	if e != nil {
+		t.Fatal(e)
+	}
+
+	assert(t, ds.Columns, got.Columns, true)
+}
+
+func TestFixedWidthWriterAlign(t *testing.T) {
+	ds := createFixedWidthDataset()
+
+	fields := []tabula.FixedWidthField{{
+		Start: 0,
+		Width: 5,
+	}, {
+		Start: 6,
+		Width: 3,
+		Align: tabula.FixedWidthAlignRight,
+	}, {
+		Start: 9,
+		Width: 6,
+		Align: tabula.FixedWidthAlignLeft,
+	}}
+
+	writer := tabula.NewFixedWidthWriter(fields)
+	writer.ZeroPad = true
+	writer.Pad = '.'
+	writer.Truncate = true
+
+	var out bytes.Buffer
+
+	e := writer.Write(&out, ds)
+	if e != nil {
+		t.Fatal(e)
+	}
+
+	exp := "00001.alp12.5..\n" +
+		"-0022.bet-0.25.\n" +
+		"....?.gam?.....\n"
+
+	assert(t, exp, out.String(), true)
+
+	writer.Truncate = false
+
+	e = writer.Write(&out, ds)
+
+	assert(t, tabula.ErrValueTooLong, e, true)
+}
+
+func TestFixedWidthWriterNoFields(t *testing.T) {
+	ds := createFixedWidthDataset()
+
+	var out bytes.Buffer
+
+	e := tabula.NewFixedWidthWriter(nil).Write(&out, ds)
+	if e != nil {
+		t.Fatal(e)
+	}
+
+	exp := "  1 alpha  12.5\n" +
+		"-22 beta  -0.25\n" +
+		"  ? gamma     ?\n"
+
+	assert(t, exp, out.String(), true)
+}
+
+func TestFixedWidthWriterDecimal(t *testing.T) {
+	ds := tabula.NewDataset(tabula.DatasetModeRows,
+		[]int{tabula.TString, tabula.TDecimal}, []string{"name", "amount"})
+
+	e := tabula.NewDSVReader().Read(strings.NewReader(
+		"alpha,12.50\nbeta,-0.25\n"), ds)
+	if e != nil {
+		t.Fatal(e)
+	}
+
+	fields := []tabula.FixedWidthField{{
+		Width: 6,
+	}, {
+		Start: 6,
+		Width: 7,
+	}}
+
+	writer := tabula.NewFixedWidthWriter(fields)
+	writer.ZeroPad = true
+
+	var out bytes.Buffer
+
+	e = writer.Write(&out, ds)
+	if e != nil {
+		t.Fatal(e)
+	}
+
+	assert(t, "alpha 0012.50\nbeta  -000.25\n", out.String(), true)
+}
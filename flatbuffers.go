@@ -0,0 +1,400 @@
+// Copyright 2017 M. Shulhan <ms@kilabit.info>. All rights reserved.
+// Use of this source code is governed by a BSD-style license that can be found
+// in the LICENSE file.
+
+package tabula
+
+import (
+	"encoding/binary"
+)
+
+//
+// This file contain minimal implementation of FlatBuffers encoder and
+// decoder, which is used to encode and decode the Arrow IPC metadata.
+//
+// The encoder write the buffer from front to back: each table is written
+// after its vtable and before its child objects, so all offsets to child
+// objects is always pointing forward as required by FlatBuffers.
+//
+
+//
+// fbObject is the interface for object that can be encoded into FlatBuffers.
+//
+type fbObject interface {
+	// encode the object into builder and return its position.
+	encode(fb *fbBuilder) int
+}
+
+//
+// fbField define the table field. If `child` is nil, the field is a scalar
+// with `size` bytes, otherwise it's an offset to child object.
+//
+type fbField struct {
+	size   int
+	scalar uint64
+	child  fbObject
+}
+
+//
+// fbTable define FlatBuffers table, where the index of field is the field
+// id. Field with zero size and nil child is not written.
+//
+type fbTable []fbField
+
+//
+// fbString define FlatBuffers string.
+//
+type fbString string
+
+//
+// fbTables define FlatBuffers vector of tables.
+//
+type fbTables []fbTable
+
+//
+// fbStructs define FlatBuffers vector of `n` structs, which is encoded in
+// `data`, where each struct is aligned to `align` bytes.
+//
+type fbStructs struct {
+	n     int
+	align int
+	data  []byte
+}
+
+//
+// fbBuilder contain the buffer for encoding FlatBuffers.
+//
+type fbBuilder struct {
+	buf []byte
+}
+
+//
+// fbScalar return table field with scalar value `v` of `size` bytes.
+//
+func fbScalar(size int, v uint64) fbField {
+	return fbField{size: size, scalar: v}
+}
+
+//
+// fbChild return table field with offset to `child`.
+//
+func fbChild(child fbObject) fbField {
+	return fbField{child: child}
+}
+
+//
+// fbBool convert boolean value to table field.
+//
+func fbBool(v bool) fbField {
+	if v {
+		return fbScalar(1, 1)
+	}
+	return fbScalar(1, 0)
+}
+
+//
+// fbFinish encode the `root` table and return the FlatBuffers data, padded
+// to multiple of 8 bytes.
+//
+func fbFinish(root fbTable) []byte {
+	fb := &fbBuilder{
+		buf: make([]byte, 4),
+	}
+
+	pos := root.encode(fb)
+	binary.LittleEndian.PutUint32(fb.buf, uint32(pos))
+
+	fb.align(8)
+
+	return fb.buf
+}
+
+//
+// align pad the buffer with zero until its length is multiple of `n`.
+//
+func (fb *fbBuilder) align(n int) {
+	for len(fb.buf)%n != 0 {
+		fb.buf = append(fb.buf, 0)
+	}
+}
+
+//
+// putScalar write `size` bytes of `v` at position `pos`.
+//
+func (fb *fbBuilder) putScalar(pos, size int, v uint64) {
+	switch size {
+	case 1:
+		fb.buf[pos] = byte(v)
+	case 2:
+		binary.LittleEndian.PutUint16(fb.buf[pos:], uint16(v))
+	case 4:
+		binary.LittleEndian.PutUint32(fb.buf[pos:], uint32(v))
+	case 8:
+		binary.LittleEndian.PutUint64(fb.buf[pos:], v)
+	}
+}
+
+//
+// encode the table, its vtable, and its child objects.
+//
+func (t fbTable) encode(fb *fbBuilder) int {
+	offsets := make([]int, len(t))
+	cursor := 4
+	maxAlign := 4
+	nfield := 0
+
+	// Place the biggest field first to minimize padding.
+	for _, size := range []int{8, 4, 2, 1} {
+		for x, f := range t {
+			fsize := f.size
+			if f.child != nil {
+				fsize = 4
+			}
+			if fsize != size {
+				continue
+			}
+			for cursor%size != 0 {
+				cursor++
+			}
+			offsets[x] = cursor
+			cursor += size
+			if size > maxAlign {
+				maxAlign = size
+			}
+			if x+1 > nfield {
+				nfield = x + 1
+			}
+		}
+	}
+	for cursor%4 != 0 {
+		cursor++
+	}
+
+	fb.align(2)
+	vtable := len(fb.buf)
+	fb.buf = append(fb.buf, make([]byte, 4+2*nfield)...)
+	fb.putScalar(vtable, 2, uint64(4+2*nfield))
+	fb.putScalar(vtable+2, 2, uint64(cursor))
+	for x := 0; x < nfield; x++ {
+		fb.putScalar(vtable+4+2*x, 2, uint64(offsets[x]))
+	}
+
+	fb.align(maxAlign)
+	pos := len(fb.buf)
+	fb.buf = append(fb.buf, make([]byte, cursor)...)
+	fb.putScalar(pos, 4, uint64(pos-vtable))
+
+	for x, f := range t {
+		if f.child == nil && f.size > 0 {
+			fb.putScalar(pos+offsets[x], f.size, f.scalar)
+		}
+	}
+	for x, f := range t {
+		if f.child == nil {
+			continue
+		}
+		fpos := pos + offsets[x]
+		cpos := f.child.encode(fb)
+		fb.putScalar(fpos, 4, uint64(cpos-fpos))
+	}
+
+	return pos
+}
+
+//
+// encode the string as length, bytes, and zero terminator.
+//
+func (s fbString) encode(fb *fbBuilder) int {
+	fb.align(4)
+	pos := len(fb.buf)
+	fb.buf = append(fb.buf, 0, 0, 0, 0)
+	fb.putScalar(pos, 4, uint64(len(s)))
+	fb.buf = append(fb.buf, s...)
+	fb.buf = append(fb.buf, 0)
+	return pos
+}
+
+//
+// encode the vector of tables as length and offset to each table.
+//
+func (tables fbTables) encode(fb *fbBuilder) int {
+	fb.align(4)
+	pos := len(fb.buf)
+	fb.buf = append(fb.buf, make([]byte, 4+4*len(tables))...)
+	fb.putScalar(pos, 4, uint64(len(tables)))
+
+	for x, t := range tables {
+		fpos := pos + 4 + 4*x
+		cpos := t.encode(fb)
+		fb.putScalar(fpos, 4, uint64(cpos-fpos))
+	}
+
+	return pos
+}
+
+//
+// encode the vector of structs as length followed by the structs data.
+//
+func (structs fbStructs) encode(fb *fbBuilder) int {
+	for (len(fb.buf)+4)%structs.align != 0 {
+		fb.buf = append(fb.buf, 0)
+	}
+	pos := len(fb.buf)
+	fb.buf = append(fb.buf, 0, 0, 0, 0)
+	fb.putScalar(pos, 4, uint64(structs.n))
+	fb.buf = append(fb.buf, structs.data...)
+	return pos
+}
+
+//
+// fbReader decode the FlatBuffers data. Any out of range access will set
+// the `e` to `errInvalid` and return zero value.
+//
+type fbReader struct {
+	buf        []byte
+	errInvalid error
+	e          error
+}
+
+//
+// check return true if `n` bytes at `pos` is inside the buffer.
+//
+func (fr *fbReader) check(pos, n int) bool {
+	if pos < 0 || n < 0 || pos+n > len(fr.buf) {
+		fr.e = fr.errInvalid
+		return false
+	}
+	return true
+}
+
+func (fr *fbReader) u8(pos int) uint8 {
+	if !fr.check(pos, 1) {
+		return 0
+	}
+	return fr.buf[pos]
+}
+
+func (fr *fbReader) u16(pos int) uint16 {
+	if !fr.check(pos, 2) {
+		return 0
+	}
+	return binary.LittleEndian.Uint16(fr.buf[pos:])
+}
+
+func (fr *fbReader) u32(pos int) uint32 {
+	if !fr.check(pos, 4) {
+		return 0
+	}
+	return binary.LittleEndian.Uint32(fr.buf[pos:])
+}
+
+func (fr *fbReader) u64(pos int) uint64 {
+	if !fr.check(pos, 8) {
+		return 0
+	}
+	return binary.LittleEndian.Uint64(fr.buf[pos:])
+}
+
+//
+// root return the position of root table.
+//
+func (fr *fbReader) root() int {
+	return fr.indirect(0)
+}
+
+//
+// indirect return the position of object which offset is saved at `pos`.
+//
+func (fr *fbReader) indirect(pos int) int {
+	off := fr.u32(pos)
+	if off == 0 {
+		return 0
+	}
+	return pos + int(off)
+}
+
+//
+// field return the position of field `id` in table at `tbl`, or 0 if the
+// field is not exist.
+//
+func (fr *fbReader) field(tbl, id int) int {
+	if tbl <= 0 {
+		return 0
+	}
+
+	vtable := tbl - int(int32(fr.u32(tbl)))
+	vsize := int(fr.u16(vtable))
+
+	if 4+2*id+2 > vsize {
+		return 0
+	}
+
+	off := int(fr.u16(vtable + 4 + 2*id))
+	if off == 0 {
+		return 0
+	}
+
+	return tbl + off
+}
+
+//
+// scalar return the value of scalar field `id` with `size` bytes in table
+// at `tbl`, or `def` if the field is not exist.
+//
+func (fr *fbReader) scalar(tbl, id, size int, def uint64) uint64 {
+	pos := fr.field(tbl, id)
+	if pos == 0 {
+		return def
+	}
+
+	switch size {
+	case 1:
+		return uint64(fr.u8(pos))
+	case 2:
+		return uint64(fr.u16(pos))
+	case 4:
+		return uint64(fr.u32(pos))
+	}
+	return fr.u64(pos)
+}
+
+//
+// child return the position of object which referenced by field `id` in
+// table at `tbl`, or 0 if the field is not exist.
+//
+func (fr *fbReader) child(tbl, id int) int {
+	pos := fr.field(tbl, id)
+	if pos == 0 {
+		return 0
+	}
+	return fr.indirect(pos)
+}
+
+//
+// vector return the position of first element and the length of vector in
+// field `id` of table at `tbl`.
+//
+func (fr *fbReader) vector(tbl, id int) (start, n int) {
+	pos := fr.child(tbl, id)
+	if pos == 0 {
+		return 0, 0
+	}
+
+	n = int(fr.u32(pos))
+	if !fr.check(pos+4, n) {
+		return 0, 0
+	}
+
+	return pos + 4, n
+}
+
+//
+// string return the value of string field `id` in table at `tbl`.
+//
+func (fr *fbReader) string(tbl, id int) string {
+	start, n := fr.vector(tbl, id)
+	if n == 0 {
+		return ""
+	}
+	return string(fr.buf[start : start+n])
+}
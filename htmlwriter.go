@@ -0,0 +1,143 @@
+// Copyright 2017 M. Shulhan <ms@kilabit.info>. All rights reserved.
+// Use of this source code is governed by a BSD-style license that can be found
+// in the LICENSE file.
+
+package tabula
+
+import (
+	"bufio"
+	"html"
+	"io"
+	"strings"
+)
+
+//
+// HTMLWriter write dataset as HTML table, where the column names is written
+// in table head and the records in table body.
+//
+type HTMLWriter struct {
+	// Class is the CSS class of table element. Default to "tabula".
+	Class string
+	// TypeClass if its true, each cell will have CSS class based on column
+	// type: "int", "real", "string", "bool", "time", "decimal", or
+	// "undefined".
+	TypeClass bool
+	// MissingClass is the CSS class of cell that contain missing value.
+	// Default to "missing".
+	MissingClass string
+	// Head if its greater than zero, only the first Head rows is
+	// written.
+	Head int
+	// Sample if its greater than zero, only Sample rows picked randomly
+	// is written, in the same order as in dataset. Sample is used
+	// instead of Head if both is set.
+	Sample int
+	// MissingValue is the text written for missing value.
+	MissingValue string
+}
+
+//
+// NewHTMLWriter create and return new HTML writer.
+//
+func NewHTMLWriter() *HTMLWriter {
+	return &HTMLWriter{
+		Class:        "tabula",
+		MissingClass: "missing",
+		MissingValue: DefaultMissingValue,
+	}
+}
+
+//
+// WriteFile create or truncate `file` and write dataset `ds` into it.
+//
+func (writer *HTMLWriter) WriteFile(file string, ds DatasetInterface) (
+	e error,
+) {
+	f, e := CreateFile(file)
+	if e != nil {
+		return e
+	}
+
+	e = writer.Write(f, ds)
+
+	errClose := f.Close()
+	if e == nil {
+		e = errClose
+	}
+
+	return
+}
+
+//
+// Write dataset `ds` into `w` as HTML table. Dataset is read based on its
+// mode without transposing.
+//
+func (writer *HTMLWriter) Write(w io.Writer, ds DatasetInterface) (e error) {
+	bw := bufio.NewWriter(w)
+	names := ds.GetColumnsName()
+	types := ds.GetColumnsType()
+
+	classes := make([]string, len(types))
+	if writer.TypeClass {
+		for x, t := range types {
+			classes[x] = tableTypeName(t)
+		}
+	}
+
+	line := "<table"
+	if writer.Class != "" {
+		line += ` class="` + html.EscapeString(writer.Class) + `"`
+	}
+	line += ">\n<thead>\n<tr>"
+	for x, name := range names {
+		line += "<th" + htmlClassAttr(classes[x]) + ">" +
+			html.EscapeString(name) + "</th>"
+	}
+	line += "</tr>\n</thead>\n<tbody>\n"
+
+	_, e = bw.WriteString(line)
+	if e != nil {
+		return
+	}
+
+	for _, r := range selectRowsIndex(ds.Len(), writer.Head, writer.Sample) {
+		line = "<tr>"
+		for x := range names {
+			v, missing := formatRecord(getRecordAt(ds, r, x),
+				writer.MissingValue)
+
+			class := classes[x]
+			if missing && writer.MissingClass != "" {
+				class = strings.TrimSpace(class + " " +
+					writer.MissingClass)
+			}
+
+			line += "<td" + htmlClassAttr(class) + ">" +
+				html.EscapeString(v) + "</td>"
+		}
+		line += "</tr>\n"
+
+		_, e = bw.WriteString(line)
+		if e != nil {
+			return
+		}
+	}
+
+	_, e = bw.WriteString("</tbody>\n</table>\n")
+	if e != nil {
+		return
+	}
+
+	return bw.Flush()
+}
+
+//
+// htmlClassAttr return the class attribute of element, or empty string if
+// `class` is empty.
+//
+func htmlClassAttr(class string) string {
+	if class == "" {
+		return ""
+	}
+	return ` class="` + html.EscapeString(class) + `"`
+}
@@ -0,0 +1,74 @@
+// Copyright 2017 M. Shulhan <ms@kilabit.info>. All rights reserved.
+// Use of this source code is governed by a BSD-style license that can be found
+// in the LICENSE file.
+
+package tabula_test
+
+import (
+	"bytes"
+	"github.com/shuLhan/tabula"
+	"testing"
+)
+
+func TestHTMLWriter(t *testing.T) {
+	ds := createExportDataset()
+
+	exp := `<table class="tabula">
+<thead>
+<tr><th>id</th><th>score</th><th>a|b &lt;c&gt;</th></tr>
+</thead>
+<tbody>
+<tr><td>1</td><td>0.5</td><td>*bold* &amp; &lt;i&gt;
+next</td></tr>
+<tr><td class="missing">?</td><td>2</td><td class="missing">?</td></tr>
+<tr><td>3</td><td class="missing">?</td><td>c</td></tr>
+</tbody>
+</table>
+`
+
+	var out bytes.Buffer
+
+	e := tabula.NewHTMLWriter().Write(&out, ds)
+	if e != nil {
+		t.Fatal(e)
+	}
+
+	assert(t, exp, out.String(), true)
+}
+
+func TestHTMLWriterTypeClass(t *testing.T) {
+	ds := createExportDataset()
+
+	exp := `<table>
+<thead>
+<tr><th class="int">id</th><th class="real">score</th><th class="string">a|b &lt;c&gt;</th></tr>
+</thead>
+<tbody>
+<tr><td class="int na">-</td><td class="real">2</td><td class="string na">-</td></tr>
+</tbody>
+</table>
+`
+
+	writer := &tabula.HTMLWriter{
+		TypeClass:    true,
+		MissingClass: "na",
+		MissingValue: "-",
+		Head:         2,
+		Sample:       1,
+	}
+
+	for {
+		var out bytes.Buffer
+
+		e := writer.Write(&out, ds)
+		if e != nil {
+			t.Fatal(e)
+		}
+
+		// Sample is random, repeat until the second row is picked.
+		if bytes.Contains(out.Bytes(), []byte(`"real">2<`)) {
+			assert(t, exp, out.String(), true)
+			break
+		}
+	}
+}
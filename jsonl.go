@@ -0,0 +1,252 @@
+// Copyright 2017 M. Shulhan <ms@kilabit.info>. All rights reserved.
+// Use of this source code is governed by a BSD-style license that can be found
+// in the LICENSE file.
+
+package tabula
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/json"
+	"io"
+)
+
+//
+// ReadJSONLFile open JSON Lines file and read all of its rows into dataset
+// `ds`.
+//
+func ReadJSONLFile(file string, ds DatasetInterface) (e error) {
+	f, e := OpenFile(file)
+	if e != nil {
+		return e
+	}
+
+	e = ReadJSONL(f, ds)
+
+	errClose := f.Close()
+	if e == nil {
+		e = errClose
+	}
+
+	return
+}
+
+//
+// ReadJSONL read JSON Lines from `r`, where each line is a JSON object that
+// map column name to its value, and push each of them as row into dataset
+// `ds`.
+//
+// Value is converted based on column type, and column that does not exist
+// in object or has null value is set to missing value.
+// If dataset does not have any columns, the columns will be created using
+// the keys in the first object, with type detected from its value, see
+// Record.UnmarshalJSON.
+//
+func ReadJSONL(r io.Reader, ds DatasetInterface) (e error) {
+	scanner := bufio.NewScanner(r)
+	scanner.Buffer(nil, 1024*1024*1024)
+
+	var names []string
+	var raws []json.RawMessage
+	var colIdx map[string]int
+	var types []int
+	var cols Columns
+	n := 0
+
+	for scanner.Scan() {
+		n++
+		line := bytes.TrimSpace(scanner.Bytes())
+		if len(line) == 0 {
+			continue
+		}
+
+		names, raws, e = jsonlSplitObject(line)
+		if e != nil {
+			return &ReadError{Line: n, Value: string(line), Err: e}
+		}
+
+		if colIdx == nil {
+			if ds.GetNColumn() <= 0 {
+				initJSONLColumns(ds, names, raws)
+			}
+
+			types = ds.GetColumnsType()
+			cols = *ds.GetColumns()
+			colIdx = make(map[string]int, len(types))
+			for x, name := range ds.GetColumnsName() {
+				colIdx[name] = x
+			}
+		}
+
+		row := make(Row, len(types))
+
+		for x, name := range names {
+			idx, ok := colIdx[name]
+			if !ok {
+				continue
+			}
+
+			row[idx], e = NewRecordJSON(raws[x], types[idx])
+			if e == nil {
+				e = cols[idx].Encode(row[idx])
+			}
+			if e != nil {
+				return &ReadError{
+					Line:   n,
+					Column: idx + 1,
+					Value:  string(raws[x]),
+					Err:    e,
+				}
+			}
+		}
+
+		for x := range row {
+			if row[x] == nil {
+				row[x] = NewRecord()
+				row[x].SetMissingValue(types[x])
+			}
+		}
+
+		ds.PushRow(&row)
+	}
+
+	return scanner.Err()
+}
+
+//
+// initJSONLColumns create dataset columns using keys and type of values in
+// JSON object.
+//
+func initJSONLColumns(ds DatasetInterface, names []string,
+	raws []json.RawMessage,
+) {
+	types := make([]int, len(raws))
+
+	for x, raw := range raws {
+		types[x] = TString
+
+		rec, e := NewRecordJSON(raw, TUndefined)
+		if e != nil || rec.IsNil() {
+			continue
+		}
+		types[x] = rec.Type()
+	}
+
+	ds.Init(ds.GetMode(), types, names)
+}
+
+//
+// jsonlSplitObject split JSON object into list of keys and values, in the
+// same order as in object.
+//
+func jsonlSplitObject(b []byte) (keys []string, values []json.RawMessage,
+	e error,
+) {
+	dec := json.NewDecoder(bytes.NewReader(b))
+
+	tok, e := dec.Token()
+	if e != nil {
+		return nil, nil, e
+	}
+	if delim, ok := tok.(json.Delim); !ok || delim != '{' {
+		return nil, nil, ErrInvalidJSONValue
+	}
+
+	for dec.More() {
+		tok, e = dec.Token()
+		if e != nil {
+			return nil, nil, e
+		}
+
+		var v json.RawMessage
+
+		e = dec.Decode(&v)
+		if e != nil {
+			return nil, nil, e
+		}
+
+		keys = append(keys, tok.(string))
+		values = append(values, v)
+	}
+
+	_, e = dec.Token()
+	if e != nil {
+		return nil, nil, e
+	}
+
+	return keys, values, nil
+}
+
+//
+// WriteJSONLFile create or truncate `file` and write dataset `ds` into it
+// as JSON Lines.
+//
+func WriteJSONLFile(file string, ds DatasetInterface) (e error) {
+	f, e := CreateFile(file)
+	if e != nil {
+		return e
+	}
+
+	e = WriteJSONL(f, ds)
+
+	errClose := f.Close()
+	if e == nil {
+		e = errClose
+	}
+
+	return
+}
+
+//
+// WriteJSONL write each row in dataset `ds` into `w` as JSON object, where
+// the key is column name, one object per line. Missing value is written as
+// null. Dataset is read based on its mode without transposing.
+//
+func WriteJSONL(w io.Writer, ds DatasetInterface) (e error) {
+	bw := bufio.NewWriter(w)
+	names := ds.GetColumnsName()
+	keys := make([][]byte, len(names))
+
+	for x, name := range names {
+		keys[x], e = json.Marshal(name)
+		if e != nil {
+			return
+		}
+	}
+
+	var line, v []byte
+	nrow := ds.Len()
+
+	for r := 0; r < nrow; r++ {
+		line = append(line[:0], '{')
+
+		for x := range keys {
+			if x > 0 {
+				line = append(line, ',')
+			}
+			line = append(line, keys[x]...)
+			line = append(line, ':')
+
+			rec := getRecordAt(ds, r, x)
+			if rec == nil || rec.IsMissingValue() {
+				line = append(line, "null"...)
+				continue
+			}
+
+			v, e = rec.MarshalJSON()
+			if e != nil {
+				return
+			}
+			line = append(line, v...)
+		}
+
+		line = append(line, '}', '\n')
+
+		_, e = bw.Write(line)
+		if e != nil {
+			return
+		}
+	}
+
+	return bw.Flush()
+}
@@ -0,0 +1,121 @@
+// Copyright 2017 M. Shulhan <ms@kilabit.info>. All rights reserved.
+// Use of this source code is governed by a BSD-style license that can be found
+// in the LICENSE file.
+
+package tabula_test
+
+import (
+	"bytes"
+	"fmt"
+	"github.com/shuLhan/tabula"
+	"math"
+	"strings"
+	"testing"
+)
+
+func TestWriteJSONL(t *testing.T) {
+	dataset := tabula.NewDataset(tabula.DatasetModeColumns, datasetTypes,
+		datasetNames)
+
+	e := populateWithRows(dataset)
+	if e != nil {
+		t.Fatal(e)
+	}
+
+	missing := tabula.Row{
+		tabula.NewRecordInt(math.MinInt64),
+		tabula.NewRecordReal(math.Inf(-1)),
+		tabula.NewRecordString("?"),
+	}
+	dataset.PushRow(&missing)
+
+	var out bytes.Buffer
+
+	e = tabula.WriteJSONL(&out, dataset)
+	if e != nil {
+		t.Fatal(e)
+	}
+
+	lines := strings.Split(out.String(), "\n")
+
+	assert(t, 12, len(lines), true)
+	assert(t, `{"int":0,"real":1.0,"string":"A"}`, lines[0], true)
+	assert(t, `{"int":1,"real":1.1,"string":"B"}`, lines[1], true)
+	assert(t, `{"int":null,"real":null,"string":null}`, lines[10], true)
+
+	got := tabula.NewDataset(tabula.DatasetModeRows, datasetTypes,
+		datasetNames)
+
+	e = tabula.ReadJSONL(&out, got)
+	if e != nil {
+		t.Fatal(e)
+	}
+
+	exp := *dataset.GetDataAsRows()
+	rows := *got.GetDataAsRows()
+
+	assert(t, exp[:10], rows[:10], true)
+
+	// Missing value is written as null and read back as null.
+	for x, rec := range *rows[10] {
+		assert(t, true, rec.IsNull(), true)
+		assert(t, datasetTypes[x], rec.Type(), true)
+	}
+}
+
+func TestReadJSONL(t *testing.T) {
+	input := `{"b":1.0,"a":"x","c":2}
+
+{"c":3,"b":2,"d":"ignored"}
+`
+	dataset := tabula.NewDataset(tabula.DatasetModeMatrix, nil, nil)
+
+	e := tabula.ReadJSONL(strings.NewReader(input), dataset)
+	if e != nil {
+		t.Fatal(e)
+	}
+
+	assert(t, []string{"b", "a", "c"}, dataset.GetColumnsName(), true)
+	assert(t, []int{tabula.TReal, tabula.TString, tabula.TInteger},
+		dataset.GetColumnsType(), true)
+
+	exp := "&[1 x 2]&[2 ? 3]"
+	got := fmt.Sprint(dataset.Rows)
+
+	assert(t, exp, got, true)
+
+	input = "{\"b\":1.0,\"a\":\"x\",\"c\":2}\n{\"c\":3.5}\n"
+	dataset = tabula.NewDataset(tabula.DatasetModeRows, nil, nil)
+
+	e = tabula.ReadJSONL(strings.NewReader(input), dataset)
+
+	rerr, ok := e.(*tabula.ReadError)
+	if !ok {
+		t.Fatalf("expecting ReadError, got %v", e)
+	}
+
+	assert(t, 2, rerr.Line, true)
+	assert(t, 3, rerr.Column, true)
+	assert(t, "3.5", rerr.Value, true)
+
+	dataset = tabula.NewDataset(tabula.DatasetModeRows,
+		[]int{tabula.TString}, []string{"class"})
+
+	class := dataset.GetColumn(0)
+	class.Categorical = true
+	class.ValueSpace = []string{"a", "b"}
+
+	input = "{\"class\":\"a\"}\n{\"class\":null}\n{\"class\":\"c\"}\n"
+
+	e = tabula.ReadJSONL(strings.NewReader(input), dataset)
+
+	rerr, ok = e.(*tabula.ReadError)
+	if !ok {
+		t.Fatalf("expecting ReadError, got %v", e)
+	}
+
+	assert(t, 3, rerr.Line, true)
+	assert(t, 1, rerr.Column, true)
+	assert(t, `"c"`, rerr.Value, true)
+	assert(t, tabula.ErrNotInValueSpace, rerr.Err, true)
+}
@@ -0,0 +1,239 @@
+// Copyright 2017 M. Shulhan <ms@kilabit.info>. All rights reserved.
+// Use of this source code is governed by a BSD-style license that can be found
+// in the LICENSE file.
+
+package tabula
+
+import (
+	"bufio"
+	"errors"
+	"io"
+	"strconv"
+	"strings"
+)
+
+const (
+	// LIBSVMClassName is the name of class column in claset that is read
+	// from LIBSVM format.
+	LIBSVMClassName = "class"
+)
+
+var (
+	// ErrInvalidLIBSVM returned when input is not a valid LIBSVM format.
+	ErrInvalidLIBSVM = errors.New("tabula: invalid LIBSVM format")
+)
+
+//
+// LIBSVMReader read the LIBSVM or SVMlight sparse format, where each line is
+// in format "label idx:value idx:value ...", and load it into claset.
+//
+// Each feature index is mapped into column with type TReal and name is set
+// to the feature index. Columns is created on demand until the maximum
+// feature index in input, and feature that is not defined in line is set to
+// zero.
+// The label is saved in the last column, which become the class index.
+// If all labels are integer, the class column type is TInteger and its value
+// space contain all label values, otherwise the type is TReal.
+//
+type LIBSVMReader struct {
+	// ZeroBased if its true, the first feature index is 0, otherwise the
+	// first feature index is 1.
+	ZeroBased bool
+}
+
+//
+// NewLIBSVMReader create and return new LIBSVM reader.
+//
+func NewLIBSVMReader() *LIBSVMReader {
+	return &LIBSVMReader{}
+}
+
+//
+// libsvmLine contain the parsed line of LIBSVM input.
+//
+type libsvmLine struct {
+	label  string
+	idx    []int
+	values []float64
+}
+
+//
+// ReadFile open LIBSVM file and read all of its data into `claset`.
+//
+func (reader *LIBSVMReader) ReadFile(file string, claset ClasetInterface) (
+	e error,
+) {
+	f, e := OpenFile(file)
+	if e != nil {
+		return e
+	}
+
+	e = reader.Read(f, claset)
+
+	errClose := f.Close()
+	if e == nil {
+		e = errClose
+	}
+
+	return
+}
+
+//
+// Read LIBSVM data from `r` into `claset`. The claset columns will be
+// replaced, and the mode is not changed.
+//
+func (reader *LIBSVMReader) Read(r io.Reader, claset ClasetInterface) (
+	e error,
+) {
+	base := 1
+	if reader.ZeroBased {
+		base = 0
+	}
+
+	scanner := bufio.NewScanner(r)
+	scanner.Buffer(nil, 1024*1024*1024)
+
+	var lines []libsvmLine
+	ncol := 0
+	isInteger := true
+	n := 0
+
+	for scanner.Scan() {
+		n++
+		text := scanner.Text()
+		if x := strings.IndexByte(text, '#'); x >= 0 {
+			text = text[:x]
+		}
+
+		fields := strings.Fields(text)
+		if len(fields) == 0 {
+			continue
+		}
+
+		line := libsvmLine{
+			label: fields[0],
+		}
+
+		if isInteger {
+			_, e = strconv.ParseInt(line.label, 10, 64)
+			isInteger = e == nil
+		}
+		if !isInteger {
+			_, e = strconv.ParseFloat(line.label, 64)
+			if e != nil {
+				return &ReadError{Line: n, Value: line.label, Err: e}
+			}
+		}
+
+		for _, field := range fields[1:] {
+			sep := strings.IndexByte(field, ':')
+			if sep < 0 {
+				return &ReadError{Line: n, Value: field,
+					Err: ErrInvalidLIBSVM}
+			}
+			if field[:sep] == "qid" {
+				continue
+			}
+
+			idx, e := strconv.Atoi(field[:sep])
+			if e != nil || idx < base {
+				return &ReadError{Line: n, Value: field,
+					Err: ErrColIdxOutOfRange}
+			}
+			idx -= base
+
+			v, e := strconv.ParseFloat(field[sep+1:], 64)
+			if e != nil {
+				return &ReadError{Line: n, Column: idx + 1,
+					Value: field[sep+1:], Err: e}
+			}
+
+			if idx >= ncol {
+				ncol = idx + 1
+			}
+
+			line.idx = append(line.idx, idx)
+			line.values = append(line.values, v)
+		}
+
+		lines = append(lines, line)
+	}
+
+	e = scanner.Err()
+	if e != nil {
+		return
+	}
+
+	return reader.initClaset(claset, lines, ncol, isInteger)
+}
+
+//
+// initClaset create the columns in claset and push all parsed lines as rows.
+//
+func (reader *LIBSVMReader) initClaset(claset ClasetInterface,
+	lines []libsvmLine, ncol int, isInteger bool,
+) (e error) {
+	base := 1
+	if reader.ZeroBased {
+		base = 0
+	}
+
+	types := make([]int, ncol+1)
+	names := make([]string, ncol+1)
+
+	for x := 0; x < ncol; x++ {
+		types[x] = TReal
+		names[x] = strconv.Itoa(x + base)
+	}
+
+	classType := TReal
+	if isInteger {
+		classType = TInteger
+	}
+	types[ncol] = classType
+	names[ncol] = LIBSVMClassName
+
+	claset.Init(claset.GetMode(), types, names)
+	claset.SetClassIndex(ncol)
+
+	var vs []string
+
+	for _, line := range lines {
+		row := make(Row, ncol+1)
+
+		for x := 0; x < ncol; x++ {
+			row[x] = NewRecordReal(0)
+		}
+		for x, idx := range line.idx {
+			row[idx].SetFloat(line.values[x])
+		}
+
+		row[ncol], e = NewRecordBy(line.label, classType)
+		if e != nil {
+			return e
+		}
+
+		if isInteger {
+			vs = appendUniqueString(vs, row[ncol].String())
+		}
+
+		claset.PushRow(&row)
+	}
+
+	cols := claset.GetColumns()
+	(*cols)[ncol].ValueSpace = vs
+
+	return nil
+}
+
+//
+// appendUniqueString append `v` into `list` only if its not exist yet.
+//
+func appendUniqueString(list []string, v string) []string {
+	for _, s := range list {
+		if s == v {
+			return list
+		}
+	}
+	return append(list, v)
+}
@@ -0,0 +1,102 @@
+// Copyright 2017 M. Shulhan <ms@kilabit.info>. All rights reserved.
+// Use of this source code is governed by a BSD-style license that can be found
+// in the LICENSE file.
+
+package tabula_test
+
+import (
+	"fmt"
+	"github.com/shuLhan/tabula"
+	"strings"
+	"testing"
+)
+
+func TestLIBSVMReaderReadFile(t *testing.T) {
+	claset := tabula.NewClaset(tabula.DatasetModeMatrix, nil, nil)
+
+	e := tabula.NewLIBSVMReader().ReadFile("testdata/sample.libsvm", claset)
+	if e != nil {
+		t.Fatal(e)
+	}
+
+	assert(t, []string{"1", "2", "3", "4", "class"},
+		claset.GetColumnsName(), true)
+	assert(t, tabula.TInteger, claset.GetClassType(), true)
+	assert(t, 4, claset.GetClassIndex(), true)
+	assert(t, []string{"1", "-1"}, claset.GetClassValueSpace(), true)
+
+	exp := "&[0.5 0 2 0 1]&[0 -1.25 0 0 -1]&[0 0 0 7 1]"
+	got := fmt.Sprint(claset.Rows)
+
+	assert(t, exp, got, true)
+
+	claset.RecountMajorMinor()
+
+	assert(t, "1", claset.MajorityClass(), true)
+	assert(t, "-1", claset.MinorityClass(), true)
+}
+
+func TestLIBSVMReaderZeroBased(t *testing.T) {
+	input := "0.5 0:1 2:3\n1.5 1:2\n"
+	claset := tabula.NewClaset(tabula.DatasetModeColumns, nil, nil)
+	reader := tabula.NewLIBSVMReader()
+	reader.ZeroBased = true
+
+	e := reader.Read(strings.NewReader(input), claset)
+	if e != nil {
+		t.Fatal(e)
+	}
+
+	assert(t, []string{"0", "1", "2", "class"}, claset.GetColumnsName(),
+		true)
+	assert(t, tabula.TReal, claset.GetClassType(), true)
+
+	exp := "[0.5 1.5]"
+	got := fmt.Sprint(claset.GetClassAsReals())
+
+	assert(t, exp, got, true)
+}
+
+func TestLIBSVMReaderError(t *testing.T) {
+	inputs := []string{
+		"1 1:2\n1 0:1\n",
+		"1 1:2\nx 1:1\n",
+		"1 1:2 3:y\n",
+		"1 1\n",
+	}
+	exps := []tabula.ReadError{{
+		Line:  2,
+		Value: "0:1",
+		Err:   tabula.ErrColIdxOutOfRange,
+	}, {
+		Line:  2,
+		Value: "x",
+	}, {
+		Line:   1,
+		Column: 3,
+		Value:  "y",
+	}, {
+		Line:  1,
+		Value: "1",
+		Err:   tabula.ErrInvalidLIBSVM,
+	}}
+
+	for x, input := range inputs {
+		claset := tabula.NewClaset(tabula.DatasetModeRows, nil, nil)
+
+		e := tabula.NewLIBSVMReader().Read(strings.NewReader(input),
+			claset)
+
+		got, ok := e.(*tabula.ReadError)
+		if !ok {
+			t.Fatalf("expecting ReadError, got %v", e)
+		}
+
+		assert(t, exps[x].Line, got.Line, true)
+		assert(t, exps[x].Column, got.Column, true)
+		assert(t, exps[x].Value, got.Value, true)
+		if exps[x].Err != nil {
+			assert(t, exps[x].Err, got.Err, true)
+		}
+	}
+}
@@ -0,0 +1,180 @@
+// Copyright 2017 M. Shulhan <ms@kilabit.info>. All rights reserved.
+// Use of this source code is governed by a BSD-style license that can be found
+// in the LICENSE file.
+
+package tabula
+
+import (
+	"bufio"
+	"errors"
+	"io"
+	"strconv"
+)
+
+var (
+	// ErrInvalidLIBSVMLabel returned when class label is missing, or is
+	// not a number and not in the class value space.
+	ErrInvalidLIBSVMLabel = errors.New("tabula: invalid LIBSVM label")
+)
+
+//
+// LIBSVMWriter write claset into LIBSVM or SVMlight sparse format, where each
+// line is in format "label idx:value idx:value ...".
+//
+// The label is taken from class column. String label is written as its index
+// in the class value space, or as is if the class column does not have value
+// space and the label is a number. The feature index is the position
+// of column in claset, excluding the class column. Only numeric and boolean
+// column with non-zero and non-missing value is written, where boolean true
+// is written as 1.
+//
+type LIBSVMWriter struct {
+	// ZeroBased if its true, the first feature index is 0, otherwise the
+	// first feature index is 1.
+	ZeroBased bool
+}
+
+//
+// NewLIBSVMWriter create and return new LIBSVM writer.
+//
+func NewLIBSVMWriter() *LIBSVMWriter {
+	return &LIBSVMWriter{}
+}
+
+//
+// WriteFile create or truncate `file` and write `claset` into it.
+//
+func (writer *LIBSVMWriter) WriteFile(file string, claset ClasetInterface) (
+	e error,
+) {
+	f, e := CreateFile(file)
+	if e != nil {
+		return e
+	}
+
+	e = writer.Write(f, claset)
+
+	errClose := f.Close()
+	if e == nil {
+		e = errClose
+	}
+
+	return
+}
+
+//
+// Write all rows in `claset` into `w` using LIBSVM format. Claset is read
+// based on its mode without transposing.
+//
+func (writer *LIBSVMWriter) Write(w io.Writer, claset ClasetInterface) (
+	e error,
+) {
+	bw := bufio.NewWriter(w)
+	types := claset.GetColumnsType()
+	classIdx := claset.GetClassIndex()
+
+	var vs []string
+	if cols := claset.GetColumns(); classIdx >= 0 && classIdx < len(*cols) {
+		vs = (*cols)[classIdx].ValueSpace
+	}
+
+	base := 1
+	if writer.ZeroBased {
+		base = 0
+	}
+
+	var line []byte
+	nrow := claset.Len()
+
+	for r := 0; r < nrow; r++ {
+		line = line[:0]
+
+		rec := getRecordAt(claset, r, classIdx)
+
+		line, e = appendLIBSVMLabel(line, rec, vs)
+		if e != nil {
+			return
+		}
+
+		idx := base
+		for x, t := range types {
+			if x == classIdx {
+				continue
+			}
+
+			rec = getRecordAt(claset, r, x)
+
+			if (t == TInteger || t == TReal || t == TDecimal ||
+				t == TBool || t == TTime) &&
+				rec != nil && !rec.IsNil() &&
+				!rec.IsMissingValue() && rec.Float() != 0 {
+				line = append(line, ' ')
+				line = strconv.AppendInt(line, int64(idx), 10)
+				line = append(line, ':')
+				line = appendLIBSVMValue(line, rec)
+			}
+
+			idx++
+		}
+
+		line = append(line, '\n')
+
+		_, e = bw.Write(line)
+		if e != nil {
+			return
+		}
+	}
+
+	return bw.Flush()
+}
+
+//
+// appendLIBSVMLabel append the class label of record into `line`. Missing
+// label, or string label that is not a number and not in value space `vs`,
+// will return ErrInvalidLIBSVMLabel.
+//
+func appendLIBSVMLabel(line []byte, rec *Record, vs []string) (
+	[]byte, error,
+) {
+	if rec == nil || rec.IsNull() || rec.IsMissingValue() {
+		return line, ErrInvalidLIBSVMLabel
+	}
+	if rec.Type() != TString {
+		return appendLIBSVMValue(line, rec), nil
+	}
+
+	v := rec.String()
+
+	if len(vs) > 0 {
+		for x := range vs {
+			if vs[x] == v {
+				return strconv.AppendInt(line, int64(x), 10), nil
+			}
+		}
+		return line, ErrInvalidLIBSVMLabel
+	}
+
+	_, e := strconv.ParseFloat(v, 64)
+	if e != nil {
+		return line, ErrInvalidLIBSVMLabel
+	}
+
+	return append(line, v...), nil
+}
+
+//
+// appendLIBSVMValue append the record value into `line`, where boolean is
+// written as 1 or 0, and time as Unix time in seconds.
+//
+func appendLIBSVMValue(line []byte, rec *Record) []byte {
+	if rec.IsMissingValue() {
+		return append(line, rec.String()...)
+	}
+	switch rec.Type() {
+	case TBool:
+		return strconv.AppendInt(line, rec.Integer(), 10)
+	case TTime:
+		return strconv.AppendFloat(line, rec.Float(), 'f', -1, 64)
+	}
+	return append(line, rec.String()...)
+}
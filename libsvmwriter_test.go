@@ -0,0 +1,141 @@
+// Copyright 2017 M. Shulhan <ms@kilabit.info>. All rights reserved.
+// Use of this source code is governed by a BSD-style license that can be found
+// in the LICENSE file.
+
+package tabula_test
+
+import (
+	"bytes"
+	"github.com/shuLhan/tabula"
+	"strings"
+	"testing"
+)
+
+func TestLIBSVMWriterWrite(t *testing.T) {
+	claset := tabula.NewClaset(tabula.DatasetModeRows, testColTypes,
+		testColNames)
+	claset.SetClassIndex(testClassIdx)
+	claset.Columns[testClassIdx].ValueSpace = []string{"-", "+"}
+
+	rows, e := initRows()
+	if e != nil {
+		t.Fatal(e)
+	}
+	claset.SetRows(&rows)
+
+	writers := []*tabula.LIBSVMWriter{
+		tabula.NewLIBSVMWriter(),
+		{ZeroBased: true},
+	}
+	exps := []string{
+		"1 1:1 2:5 3:9\n0 1:2 2:6\n0 1:3 2:7 3:1\n1 1:4 2:8 3:2\n",
+		"1 0:1 1:5 2:9\n0 0:2 1:6\n0 0:3 1:7 2:1\n1 0:4 1:8 2:2\n",
+	}
+
+	for x, writer := range writers {
+		var out bytes.Buffer
+
+		e = writer.Write(&out, claset)
+		if e != nil {
+			t.Fatal(e)
+		}
+
+		assert(t, exps[x], out.String(), true)
+	}
+}
+
+func TestLIBSVMWriterLabel(t *testing.T) {
+	claset := tabula.Claset{}
+
+	e := tabula.ReadDatasetConfig(&claset, "testdata/claset.json")
+	if e != nil {
+		t.Fatal(e)
+	}
+
+	e = newTestDSVReader().ReadFile("testdata/claset.csv", &claset)
+	if e != nil {
+		t.Fatal(e)
+	}
+
+	var out bytes.Buffer
+
+	e = tabula.NewLIBSVMWriter().Write(&out, &claset)
+	if e != nil {
+		t.Fatal(e)
+	}
+
+	exp := "0 1:1 2:0.5\n1 1:2 2:1.25\n1 1:3 2:2\n0 1:4 2:-3.75\n"
+
+	assert(t, exp, out.String(), true)
+
+	// Label that is not in value space.
+	claset.Columns[3].ValueSpace = []string{"+"}
+
+	e = tabula.NewLIBSVMWriter().Write(&out, &claset)
+	assert(t, tabula.ErrInvalidLIBSVMLabel, e, true)
+
+	// Non-numeric label without value space.
+	claset.Columns[3].ValueSpace = nil
+
+	e = tabula.NewLIBSVMWriter().Write(&out, &claset)
+	assert(t, tabula.ErrInvalidLIBSVMLabel, e, true)
+
+	// Missing label.
+	claset.Columns[3].ValueSpace = []string{"+", "-"}
+	claset.Columns[3].Records[1].SetMissingValue(tabula.TString)
+
+	e = tabula.NewLIBSVMWriter().Write(&out, &claset)
+	assert(t, tabula.ErrInvalidLIBSVMLabel, e, true)
+}
+
+func TestLIBSVMWriterDecimal(t *testing.T) {
+	claset := tabula.NewClaset(tabula.DatasetModeRows, []int{
+		tabula.TDecimal, tabula.TInteger, tabula.TString,
+	}, []string{"amount", "n", "class"})
+	claset.SetClassIndex(2)
+	claset.Columns[2].ValueSpace = []string{"no", "yes"}
+
+	e := tabula.NewDSVReader().Read(strings.NewReader(
+		"12.50,1,yes\n0.00,2,no\n?,3,no\n"), claset)
+	if e != nil {
+		t.Fatal(e)
+	}
+
+	var out bytes.Buffer
+
+	e = tabula.NewLIBSVMWriter().Write(&out, claset)
+	if e != nil {
+		t.Fatal(e)
+	}
+
+	assert(t, "1 1:12.50 2:1\n0 2:2\n0 2:3\n", out.String(), true)
+}
+
+func TestLIBSVMWriterRoundTrip(t *testing.T) {
+	claset := tabula.NewClaset(tabula.DatasetModeColumns, nil, nil)
+
+	e := tabula.NewLIBSVMReader().ReadFile("testdata/sample.libsvm", claset)
+	if e != nil {
+		t.Fatal(e)
+	}
+
+	var out bytes.Buffer
+
+	e = tabula.NewLIBSVMWriter().Write(&out, claset)
+	if e != nil {
+		t.Fatal(e)
+	}
+
+	exp := "1 1:0.5 3:2\n-1 2:-1.25\n1 4:7\n"
+
+	assert(t, exp, out.String(), true)
+
+	got := tabula.NewClaset(tabula.DatasetModeColumns, nil, nil)
+
+	e = tabula.NewLIBSVMReader().Read(&out, got)
+	if e != nil {
+		t.Fatal(e)
+	}
+
+	assert(t, claset, got, true)
+}
@@ -0,0 +1,153 @@
+// Copyright 2017 M. Shulhan <ms@kilabit.info>. All rights reserved.
+// Use of this source code is governed by a BSD-style license that can be found
+// in the LICENSE file.
+
+package tabula
+
+import (
+	"bufio"
+	"io"
+	"math/rand"
+	"sort"
+	"strings"
+)
+
+var markdownEscaper = strings.NewReplacer(
+	`\`, `\\`, "`", "\\`", "*", `\*`, "_", `\_`, "[", `\[`, "]", `\]`,
+	"<", `\<`, ">", `\>`, "|", `\|`, "\r\n", "<br>", "\n", "<br>",
+)
+
+//
+// MarkdownWriter write dataset as GitHub Flavored Markdown table, where the
+// first row is the column names. Integer, real, and decimal columns is
+// aligned to the right. Missing value is written in emphasis.
+//
+type MarkdownWriter struct {
+	// Head if its greater than zero, only the first Head rows is
+	// written.
+	Head int
+	// Sample if its greater than zero, only Sample rows picked randomly
+	// is written, in the same order as in dataset. Sample is used
+	// instead of Head if both is set.
+	Sample int
+	// MissingValue is the text written for missing value.
+	MissingValue string
+}
+
+//
+// NewMarkdownWriter create and return new Markdown writer.
+//
+func NewMarkdownWriter() *MarkdownWriter {
+	return &MarkdownWriter{
+		MissingValue: DefaultMissingValue,
+	}
+}
+
+//
+// WriteFile create or truncate `file` and write dataset `ds` into it.
+//
+func (writer *MarkdownWriter) WriteFile(file string, ds DatasetInterface) (
+	e error,
+) {
+	f, e := CreateFile(file)
+	if e != nil {
+		return e
+	}
+
+	e = writer.Write(f, ds)
+
+	errClose := f.Close()
+	if e == nil {
+		e = errClose
+	}
+
+	return
+}
+
+//
+// Write dataset `ds` into `w` as Markdown table. Dataset is read based on
+// its mode without transposing.
+//
+func (writer *MarkdownWriter) Write(w io.Writer, ds DatasetInterface) (
+	e error,
+) {
+	bw := bufio.NewWriter(w)
+	names := ds.GetColumnsName()
+	types := ds.GetColumnsType()
+
+	line := "|"
+	for _, name := range names {
+		line += " " + markdownEscaper.Replace(name) + " |"
+	}
+	line += "\n|"
+	for _, t := range types {
+		if t == TInteger || t == TReal || t == TDecimal {
+			line += " ---: |"
+		} else {
+			line += " --- |"
+		}
+	}
+
+	_, e = bw.WriteString(line + "\n")
+	if e != nil {
+		return
+	}
+
+	for _, r := range selectRowsIndex(ds.Len(), writer.Head, writer.Sample) {
+		line = "|"
+		for x := range names {
+			v, missing := formatRecord(getRecordAt(ds, r, x),
+				writer.MissingValue)
+			v = markdownEscaper.Replace(v)
+			if missing && v != "" {
+				v = "*" + v + "*"
+			}
+			line += " " + v + " |"
+		}
+
+		_, e = bw.WriteString(line + "\n")
+		if e != nil {
+			return
+		}
+	}
+
+	return bw.Flush()
+}
+
+//
+// formatRecord convert the record into text. If record is missing value, it
+// will return `missing` and true.
+//
+func formatRecord(rec *Record, missing string) (string, bool) {
+	if rec == nil || rec.IsNil() {
+		return "", false
+	}
+	if rec.IsMissingValue() {
+		return missing, true
+	}
+	return rec.String(), false
+}
+
+//
+// selectRowsIndex return the index of `sample` rows picked randomly, or
+// the first `head` rows, from `nrow` rows. If both is zero or negative, it
+// will return the index of all rows.
+//
+func selectRowsIndex(nrow, head, sample int) (idx []int) {
+	if sample > 0 && sample < nrow {
+		idx = rand.Perm(nrow)[:sample]
+		sort.Ints(idx)
+		return idx
+	}
+
+	if head > 0 && head < nrow {
+		nrow = head
+	}
+
+	idx = make([]int, nrow)
+	for x := range idx {
+		idx[x] = x
+	}
+
+	return idx
+}
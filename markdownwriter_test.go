@@ -0,0 +1,105 @@
+// Copyright 2017 M. Shulhan <ms@kilabit.info>. All rights reserved.
+// Use of this source code is governed by a BSD-style license that can be found
+// in the LICENSE file.
+
+package tabula_test
+
+import (
+	"bytes"
+	"github.com/shuLhan/tabula"
+	"math"
+	"strings"
+	"testing"
+)
+
+func createExportDataset() *tabula.Dataset {
+	ds := tabula.NewDataset(tabula.DatasetModeRows, datasetTypes,
+		[]string{"id", "score", "a|b <c>"})
+
+	rows := []tabula.Row{{
+		tabula.NewRecordInt(1),
+		tabula.NewRecordReal(0.5),
+		tabula.NewRecordString("*bold* & <i>\nnext"),
+	}, {
+		tabula.NewRecordInt(math.MinInt64),
+		tabula.NewRecordReal(2),
+		tabula.NewRecordString("?"),
+	}, {
+		tabula.NewRecordInt(3),
+		tabula.NewRecordReal(math.Inf(-1)),
+		tabula.NewRecordString("c"),
+	}}
+
+	for x := range rows {
+		ds.PushRow(&rows[x])
+	}
+
+	return ds
+}
+
+func TestMarkdownWriter(t *testing.T) {
+	ds := createExportDataset()
+
+	exp := `| id | score | a\|b \<c\> |
+| ---: | ---: | --- |
+| 1 | 0.5 | \*bold\* & \<i\><br>next |
+| *?* | 2 | *?* |
+| 3 | *?* | c |
+`
+
+	var out bytes.Buffer
+
+	e := tabula.NewMarkdownWriter().Write(&out, ds)
+	if e != nil {
+		t.Fatal(e)
+	}
+
+	assert(t, exp, out.String(), true)
+}
+
+func TestMarkdownWriterDecimal(t *testing.T) {
+	ds := tabula.NewDataset(tabula.DatasetModeRows,
+		[]int{tabula.TString, tabula.TDecimal}, []string{"name", "amount"})
+
+	e := tabula.NewDSVReader().Read(strings.NewReader("a,12.50\n"), ds)
+	if e != nil {
+		t.Fatal(e)
+	}
+
+	exp := "| name | amount |\n| --- | ---: |\n| a | 12.50 |\n"
+
+	var out bytes.Buffer
+
+	e = tabula.NewMarkdownWriter().Write(&out, ds)
+	if e != nil {
+		t.Fatal(e)
+	}
+
+	assert(t, exp, out.String(), true)
+}
+
+func TestMarkdownWriterHeadSample(t *testing.T) {
+	ds := createExportDataset()
+
+	writer := tabula.NewMarkdownWriter()
+	writer.Head = 1
+
+	var out bytes.Buffer
+
+	e := writer.Write(&out, ds)
+	if e != nil {
+		t.Fatal(e)
+	}
+
+	assert(t, 3, strings.Count(out.String(), "\n"), true)
+
+	writer.Sample = 2
+	out.Reset()
+
+	e = writer.Write(&out, ds)
+	if e != nil {
+		t.Fatal(e)
+	}
+
+	assert(t, 4, strings.Count(out.String(), "\n"), true)
+}
@@ -0,0 +1,530 @@
+// Copyright 2017 M. Shulhan <ms@kilabit.info>. All rights reserved.
+// Use of this source code is governed by a BSD-style license that can be found
+// in the LICENSE file.
+
+package tabula
+
+import (
+	"bytes"
+	"encoding/binary"
+	"errors"
+	"io"
+	"math"
+	"time"
+)
+
+const (
+	// msgpackExtTimestamp is the type of MessagePack timestamp
+	// extension, -1 in two's complement.
+	msgpackExtTimestamp byte = 0xff
+	// msgpackExtDecimal is the type of application extension for
+	// decimal, which contain one byte of scale and eight bytes of
+	// unscaled value in big-endian.
+	msgpackExtDecimal byte = 0x01
+)
+
+var (
+	// ErrInvalidMsgpack returned when MessagePack data can not be
+	// decoded.
+	ErrInvalidMsgpack = errors.New("tabula: invalid MessagePack data")
+)
+
+//
+// appendMsgpackNil append MessagePack nil into `b`.
+//
+func appendMsgpackNil(b []byte) []byte {
+	return append(b, 0xc0)
+}
+
+//
+// appendMsgpackBool append boolean `v` into `b` as MessagePack true or
+// false.
+//
+func appendMsgpackBool(b []byte, v bool) []byte {
+	if v {
+		return append(b, 0xc3)
+	}
+	return append(b, 0xc2)
+}
+
+//
+// appendMsgpackTime append time `t` into `b` as MessagePack timestamp
+// extension, using the smallest format that can hold the value.
+//
+func appendMsgpackTime(b []byte, t time.Time) []byte {
+	sec := t.Unix()
+	nsec := uint64(t.Nanosecond())
+
+	switch {
+	case sec >= 0 && sec <= math.MaxUint32 && nsec == 0:
+		b = append(b, 0xd6, msgpackExtTimestamp)
+		return appendMsgpackUint(b, uint64(sec), 4)
+	case sec >= 0 && sec < 1<<34:
+		b = append(b, 0xd7, msgpackExtTimestamp)
+		return appendMsgpackUint(b, nsec<<34|uint64(sec), 8)
+	}
+
+	b = append(b, 0xc7, 12, msgpackExtTimestamp)
+	b = appendMsgpackUint(b, nsec, 4)
+	return appendMsgpackUint(b, uint64(sec), 8)
+}
+
+//
+// appendMsgpackDecimal append decimal `d` into `b` as MessagePack decimal
+// extension.
+//
+func appendMsgpackDecimal(b []byte, d Decimal) []byte {
+	b = append(b, 0xc7, 9, msgpackExtDecimal, byte(d.Scale))
+	return appendMsgpackUint(b, uint64(d.Value), 8)
+}
+
+//
+// readMsgpackTime read the data of MessagePack timestamp extension with
+// `size` bytes from `br`. The type of extension must be already read.
+//
+func readMsgpackTime(br *bytes.Reader, size int) (t time.Time, e error) {
+	var sec, nsec uint64
+
+	switch size {
+	case 4:
+		sec, e = readMsgpackUint(br, 4)
+	case 8:
+		sec, e = readMsgpackUint(br, 8)
+		nsec = sec >> 34
+		sec &= 1<<34 - 1
+	case 12:
+		nsec, e = readMsgpackUint(br, 4)
+		if e == nil {
+			sec, e = readMsgpackUint(br, 8)
+		}
+	default:
+		return t, ErrInvalidMsgpack
+	}
+	if e != nil {
+		return t, e
+	}
+	if nsec >= 1e9 {
+		return t, ErrInvalidMsgpack
+	}
+
+	return time.Unix(int64(sec), int64(nsec)).UTC(), nil
+}
+
+//
+// appendMsgpackInt append integer `v` into `b` using the smallest
+// MessagePack integer format.
+//
+func appendMsgpackInt(b []byte, v int64) []byte {
+	switch {
+	case v >= 0 && v <= math.MaxInt8:
+		return append(b, byte(v))
+	case v < 0 && v >= -32:
+		return append(b, byte(int8(v)))
+	case v >= math.MinInt8 && v <= math.MaxInt8:
+		return append(b, 0xd0, byte(int8(v)))
+	case v >= math.MinInt16 && v <= math.MaxInt16:
+		return appendMsgpackUint(append(b, 0xd1), uint64(int16(v)), 2)
+	case v >= math.MinInt32 && v <= math.MaxInt32:
+		return appendMsgpackUint(append(b, 0xd2), uint64(int32(v)), 4)
+	}
+	return appendMsgpackUint(append(b, 0xd3), uint64(v), 8)
+}
+
+//
+// appendMsgpackFloat append real `v` into `b` as MessagePack float 64, so
+// the value is always decoded as real.
+//
+func appendMsgpackFloat(b []byte, v float64) []byte {
+	return appendMsgpackUint(append(b, 0xcb), math.Float64bits(v), 8)
+}
+
+//
+// appendMsgpackString append string `s` into `b` as MessagePack str.
+//
+func appendMsgpackString(b []byte, s string) []byte {
+	n := len(s)
+	switch {
+	case n <= 31:
+		b = append(b, 0xa0|byte(n))
+	case n <= math.MaxUint8:
+		b = append(b, 0xd9, byte(n))
+	case n <= math.MaxUint16:
+		b = appendMsgpackUint(append(b, 0xda), uint64(n), 2)
+	default:
+		b = appendMsgpackUint(append(b, 0xdb), uint64(n), 4)
+	}
+	return append(b, s...)
+}
+
+//
+// appendMsgpackStrings append `list` into `b` as MessagePack array of str.
+// Nil list is encoded as nil.
+//
+func appendMsgpackStrings(b []byte, list []string) []byte {
+	if list == nil {
+		return appendMsgpackNil(b)
+	}
+	b = appendMsgpackArray(b, len(list))
+	for _, s := range list {
+		b = appendMsgpackString(b, s)
+	}
+	return b
+}
+
+//
+// appendMsgpackArray append the header of MessagePack array with `n` items
+// into `b`.
+//
+func appendMsgpackArray(b []byte, n int) []byte {
+	switch {
+	case n <= 15:
+		return append(b, 0x90|byte(n))
+	case n <= math.MaxUint16:
+		return appendMsgpackUint(append(b, 0xdc), uint64(n), 2)
+	}
+	return appendMsgpackUint(append(b, 0xdd), uint64(n), 4)
+}
+
+//
+// appendMsgpackMap append the header of MessagePack map with `n` pairs into
+// `b`.
+//
+func appendMsgpackMap(b []byte, n int) []byte {
+	switch {
+	case n <= 15:
+		return append(b, 0x80|byte(n))
+	case n <= math.MaxUint16:
+		return appendMsgpackUint(append(b, 0xde), uint64(n), 2)
+	}
+	return appendMsgpackUint(append(b, 0xdf), uint64(n), 4)
+}
+
+//
+// appendMsgpackUint append the `size` lower bytes of `v` into `b` in big
+// endian.
+//
+func appendMsgpackUint(b []byte, v uint64, size int) []byte {
+	for x := size - 1; x >= 0; x-- {
+		b = append(b, byte(v>>(uint(x)*8)))
+	}
+	return b
+}
+
+//
+// readMsgpackUint read unsigned integer with `size` bytes in big endian from
+// `br`.
+//
+func readMsgpackUint(br *bytes.Reader, size int) (uint64, error) {
+	var buf [8]byte
+
+	_, e := io.ReadFull(br, buf[8-size:])
+	if e != nil {
+		return 0, ErrInvalidMsgpack
+	}
+
+	return binary.BigEndian.Uint64(buf[:]), nil
+}
+
+//
+// readMsgpackBytes read `n` bytes from `br`, where `n` is read from
+// unsigned integer with `size` bytes if its not zero.
+//
+func readMsgpackBytes(br *bytes.Reader, n uint64, size int) (
+	[]byte, error,
+) {
+	if size > 0 {
+		var e error
+		n, e = readMsgpackUint(br, size)
+		if e != nil {
+			return nil, e
+		}
+	}
+	if n > uint64(br.Len()) {
+		return nil, ErrInvalidMsgpack
+	}
+
+	b := make([]byte, n)
+
+	_, e := io.ReadFull(br, b)
+	if e != nil {
+		return nil, ErrInvalidMsgpack
+	}
+
+	return b, nil
+}
+
+//
+// readMsgpackValue read MessagePack nil, boolean, integer, float, str, bin,
+// timestamp extension, or decimal extension from `br`. Integer is returned
+// as int64, float as float64, boolean as bool, str and bin as string,
+// timestamp as time.Time in UTC, and decimal as Decimal.
+//
+func readMsgpackValue(br *bytes.Reader) (v interface{}, e error) {
+	c, e := br.ReadByte()
+	if e != nil {
+		return nil, ErrInvalidMsgpack
+	}
+
+	switch {
+	case c <= 0x7f:
+		return int64(c), nil
+	case c >= 0xe0:
+		return int64(int8(c)), nil
+	case c >= 0xa0 && c <= 0xbf:
+		b, e := readMsgpackBytes(br, uint64(c&0x1f), 0)
+		return string(b), e
+	}
+
+	var u64 uint64
+
+	switch c {
+	case 0xc0:
+		return nil, nil
+	case 0xc2:
+		return false, nil
+	case 0xc3:
+		return true, nil
+	case 0xc4, 0xd9:
+		b, e := readMsgpackBytes(br, 0, 1)
+		return string(b), e
+	case 0xc5, 0xda:
+		b, e := readMsgpackBytes(br, 0, 2)
+		return string(b), e
+	case 0xc6, 0xdb:
+		b, e := readMsgpackBytes(br, 0, 4)
+		return string(b), e
+	case 0xca:
+		u64, e = readMsgpackUint(br, 4)
+		return float64(math.Float32frombits(uint32(u64))), e
+	case 0xcb:
+		u64, e = readMsgpackUint(br, 8)
+		return math.Float64frombits(u64), e
+	case 0xcc, 0xcd, 0xce, 0xcf:
+		u64, e = readMsgpackUint(br, 1<<(c-0xcc))
+		if u64 > math.MaxInt64 {
+			return nil, ErrInvalidMsgpack
+		}
+		return int64(u64), e
+	case 0xd0:
+		u64, e = readMsgpackUint(br, 1)
+		return int64(int8(u64)), e
+	case 0xd1:
+		u64, e = readMsgpackUint(br, 2)
+		return int64(int16(u64)), e
+	case 0xd2:
+		u64, e = readMsgpackUint(br, 4)
+		return int64(int32(u64)), e
+	case 0xd3:
+		u64, e = readMsgpackUint(br, 8)
+		return int64(u64), e
+	case 0xd6, 0xd7, 0xc7:
+		size := 4
+		if c == 0xd7 {
+			size = 8
+		} else if c == 0xc7 {
+			u64, e = readMsgpackUint(br, 1)
+			if e != nil {
+				return nil, e
+			}
+			size = int(u64)
+		}
+		ext, e := br.ReadByte()
+		if e != nil {
+			return nil, ErrInvalidMsgpack
+		}
+		if ext == msgpackExtDecimal && size == 9 {
+			return readMsgpackDecimal(br)
+		}
+		if ext != msgpackExtTimestamp {
+			return nil, ErrInvalidMsgpack
+		}
+		return readMsgpackTime(br, size)
+	}
+
+	return nil, ErrInvalidMsgpack
+}
+
+//
+// readMsgpackDecimal read the data of MessagePack decimal extension from
+// `br`. The type of extension must be already read.
+//
+func readMsgpackDecimal(br *bytes.Reader) (d Decimal, e error) {
+	scale, e := br.ReadByte()
+	if e != nil || scale > DecimalMaxScale {
+		return d, ErrInvalidMsgpack
+	}
+	v, e := readMsgpackUint(br, 8)
+	if e != nil {
+		return d, e
+	}
+	return Decimal{Value: int64(v), Scale: int(scale)}, nil
+}
+
+//
+// readMsgpackInt read MessagePack integer from `br`.
+//
+func readMsgpackInt(br *bytes.Reader) (int64, error) {
+	v, e := readMsgpackValue(br)
+	if e != nil {
+		return 0, e
+	}
+	i64, ok := v.(int64)
+	if !ok {
+		return 0, ErrInvalidMsgpack
+	}
+	return i64, nil
+}
+
+//
+// readMsgpackBool read MessagePack boolean from `br`.
+//
+func readMsgpackBool(br *bytes.Reader) (bool, error) {
+	v, e := readMsgpackValue(br)
+	if e != nil {
+		return false, e
+	}
+	b, ok := v.(bool)
+	if !ok {
+		return false, ErrInvalidMsgpack
+	}
+	return b, nil
+}
+
+//
+// readMsgpackString read MessagePack str or bin from `br`.
+//
+func readMsgpackString(br *bytes.Reader) (string, error) {
+	v, e := readMsgpackValue(br)
+	if e != nil {
+		return "", e
+	}
+	s, ok := v.(string)
+	if !ok {
+		return "", ErrInvalidMsgpack
+	}
+	return s, nil
+}
+
+//
+// readMsgpackStrings read MessagePack array of str from `br`. Nil is read as
+// nil list.
+//
+func readMsgpackStrings(br *bytes.Reader) (list []string, e error) {
+	n, e := readMsgpackArray(br)
+	if e != nil || n < 0 {
+		return nil, e
+	}
+
+	list = make([]string, n)
+	for x := range list {
+		list[x], e = readMsgpackString(br)
+		if e != nil {
+			return nil, e
+		}
+	}
+
+	return list, nil
+}
+
+//
+// readMsgpackArray read the header of MessagePack array from `br` and return
+// the number of items. If the value is nil, it will return -1.
+//
+func readMsgpackArray(br *bytes.Reader) (n int, e error) {
+	return readMsgpackLen(br, 0x90, 0xdc, 1)
+}
+
+//
+// readMsgpackMap read the header of MessagePack map from `br` and return the
+// number of pairs. If the value is nil, it will return -1.
+//
+func readMsgpackMap(br *bytes.Reader) (n int, e error) {
+	return readMsgpackLen(br, 0x80, 0xde, 2)
+}
+
+//
+// readMsgpackLen read the header of array or map, where `fix` is the fixed
+// format and `f16` is the 16-bit format, followed by 32-bit format. Each
+// item is at least `min` bytes.
+//
+func readMsgpackLen(br *bytes.Reader, fix, f16 byte, min int) (
+	n int, e error,
+) {
+	c, e := br.ReadByte()
+	if e != nil {
+		return 0, ErrInvalidMsgpack
+	}
+
+	var l uint64
+
+	switch {
+	case c == 0xc0:
+		return -1, nil
+	case c&0xf0 == fix:
+		l = uint64(c & 0x0f)
+	case c == f16:
+		l, e = readMsgpackUint(br, 2)
+	case c == f16+1:
+		l, e = readMsgpackUint(br, 4)
+	default:
+		return 0, ErrInvalidMsgpack
+	}
+	if e != nil {
+		return 0, e
+	}
+	if l*uint64(min) > uint64(br.Len()) {
+		return 0, ErrInvalidMsgpack
+	}
+
+	return int(l), nil
+}
+
+//
+// skipMsgpack read and discard one MessagePack value, including array, map,
+// and extension, from `br`.
+//
+func skipMsgpack(br *bytes.Reader) (e error) {
+	c, e := br.ReadByte()
+	if e != nil {
+		return ErrInvalidMsgpack
+	}
+	_ = br.UnreadByte()
+
+	var n int
+
+	switch {
+	case c&0xf0 == 0x90, c == 0xdc, c == 0xdd:
+		n, e = readMsgpackArray(br)
+	case c&0xf0 == 0x80, c == 0xde, c == 0xdf:
+		n, e = readMsgpackMap(br)
+		n *= 2
+	case c >= 0xd4 && c <= 0xd8:
+		// fixext 1, 2, 4, 8, 16 with one byte type.
+		_, e = readMsgpackBytes(br, 2+1<<(c-0xd4), 0)
+		return e
+	case c >= 0xc7 && c <= 0xc9:
+		// ext 8, 16, 32 with length and one byte type.
+		_, _ = br.ReadByte()
+		l, e := readMsgpackUint(br, 1<<(c-0xc7))
+		if e != nil {
+			return e
+		}
+		_, e = readMsgpackBytes(br, l+1, 0)
+		return e
+	default:
+		_, e = readMsgpackValue(br)
+		return e
+	}
+	if e != nil {
+		return e
+	}
+
+	for ; n > 0; n-- {
+		e = skipMsgpack(br)
+		if e != nil {
+			return e
+		}
+	}
+
+	return nil
+}
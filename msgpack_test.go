@@ -0,0 +1,227 @@
+// Copyright 2017 M. Shulhan <ms@kilabit.info>. All rights reserved.
+// Use of this source code is governed by a BSD-style license that can be found
+// in the LICENSE file.
+
+package tabula_test
+
+import (
+	"github.com/shuLhan/tabula"
+	"math"
+	"testing"
+)
+
+func TestRecordMsgpack(t *testing.T) {
+	cases := []struct {
+		rec *tabula.Record
+		exp []byte
+	}{{
+		rec: tabula.NewRecord(),
+		exp: []byte{0xc0},
+	}, {
+		rec: tabula.NewRecordInt(1),
+		exp: []byte{0x01},
+	}, {
+		rec: tabula.NewRecordInt(-1),
+		exp: []byte{0xff},
+	}, {
+		rec: tabula.NewRecordInt(-100),
+		exp: []byte{0xd0, 0x9c},
+	}, {
+		rec: tabula.NewRecordInt(300),
+		exp: []byte{0xd1, 0x01, 0x2c},
+	}, {
+		rec: tabula.NewRecordInt(math.MinInt64),
+		exp: []byte{0xd3, 0x80, 0, 0, 0, 0, 0, 0, 0},
+	}, {
+		rec: tabula.NewRecordReal(2),
+		exp: []byte{0xcb, 0x40, 0, 0, 0, 0, 0, 0, 0},
+	}, {
+		rec: tabula.NewRecordReal(math.Inf(-1)),
+		exp: []byte{0xcb, 0xff, 0xf0, 0, 0, 0, 0, 0, 0},
+	}, {
+		rec: tabula.NewRecordString("?"),
+		exp: []byte{0xa1, '?'},
+	}, {
+		rec: tabula.NewRecordDecimal(tabula.Decimal{Value: 1250, Scale: 2}),
+		exp: []byte{0xc7, 9, 0x01, 2, 0, 0, 0, 0, 0, 0, 0x04, 0xe2},
+	}}
+
+	for _, c := range cases {
+		got, e := c.rec.MarshalMsgpack()
+		if e != nil {
+			t.Fatal(e)
+		}
+
+		assert(t, c.exp, got, true)
+
+		rec := tabula.NewRecord()
+
+		e = rec.UnmarshalMsgpack(got)
+		if e != nil {
+			t.Fatal(e)
+		}
+
+		assert(t, c.rec, rec, true)
+	}
+}
+
+func TestRecordMsgpackDecode(t *testing.T) {
+	cases := []struct {
+		in  []byte
+		exp *tabula.Record
+		err error
+	}{{
+		in:  []byte{0xcc, 0xff},
+		exp: tabula.NewRecordInt(255),
+	}, {
+		in:  []byte{0xcf, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff},
+		err: tabula.ErrInvalidMsgpack,
+	}, {
+		in:  []byte{0xca, 0x3f, 0xc0, 0, 0},
+		exp: tabula.NewRecordReal(1.5),
+	}, {
+		in:  []byte{0xc4, 2, 'a', 'b'},
+		exp: tabula.NewRecordString("ab"),
+	}, {
+		in:  []byte{0xc3},
+		exp: tabula.NewRecordBool(true),
+	}, {
+		in:  []byte{0xd9, 3, 'a'},
+		err: tabula.ErrInvalidMsgpack,
+	}, {
+		in:  []byte{0x01, 0x02},
+		err: tabula.ErrInvalidMsgpack,
+	}, {
+		in:  []byte{0x90},
+		err: tabula.ErrInvalidMsgpack,
+	}}
+
+	for _, c := range cases {
+		rec := tabula.NewRecord()
+
+		e := rec.UnmarshalMsgpack(c.in)
+
+		assert(t, c.err, e, true)
+		if e == nil {
+			assert(t, c.exp, rec, true)
+		}
+	}
+}
+
+func TestRowMsgpack(t *testing.T) {
+	row := tabula.Row{
+		tabula.NewRecordInt(math.MinInt64),
+		tabula.NewRecordReal(1),
+		tabula.NewRecordString("a"),
+		nil,
+	}
+
+	b, e := row.MarshalMsgpack()
+	if e != nil {
+		t.Fatal(e)
+	}
+
+	got := tabula.Row{}
+
+	e = got.UnmarshalMsgpack(b)
+	if e != nil {
+		t.Fatal(e)
+	}
+
+	row[3] = tabula.NewRecord()
+
+	assert(t, row, got, true)
+	assert(t, true, got[0].IsMissingValue(), true)
+}
+
+func TestDatasetMsgpack(t *testing.T) {
+	modes := []int{
+		tabula.DatasetModeRows,
+		tabula.DatasetModeColumns,
+		tabula.DatasetModeMatrix,
+	}
+
+	for _, mode := range modes {
+		dataset := tabula.NewDataset(mode, datasetTypes, datasetNames)
+		dataset.Columns[2].ValueSpace = []string{"A", "B"}
+
+		e := populateWithRows(dataset)
+		if e != nil {
+			t.Fatal(e)
+		}
+
+		dataset.PushRow(&tabula.Row{
+			tabula.NewRecordNull(tabula.TInteger),
+			tabula.NewRecordNull(tabula.TReal),
+			tabula.NewRecordNull(tabula.TString),
+		})
+
+		b, e := dataset.MarshalMsgpack()
+		if e != nil {
+			t.Fatal(e)
+		}
+
+		got := &tabula.Dataset{}
+
+		e = got.UnmarshalMsgpack(b)
+		if e != nil {
+			t.Fatal(e)
+		}
+
+		assert(t, dataset, got, true)
+
+		// Null is read back with the column type.
+		rows := got.GetDataAsRows()
+		rec := (*rows)[rows.Len()-1].GetRecord(0)
+
+		assert(t, true, rec.IsNull(), true)
+		assert(t, tabula.TInteger, rec.Type(), true)
+	}
+}
+
+func TestDatasetMsgpackUnknownKey(t *testing.T) {
+	in := []byte{0x83,
+		0xa4, 'M', 'o', 'd', 'e', 0x01,
+		// "x": {"y": [fixext 1, ext 8]}
+		0xa1, 'x', 0x81, 0xa1, 'y', 0x92,
+		0xd4, 0x01, 0x00,
+		0xc7, 0x02, 0x01, 0xaa, 0xbb,
+		0xa4, 'R', 'o', 'w', 's', 0x91, 0x92, 0x01, 0xa1, 'a',
+	}
+
+	got := &tabula.Dataset{}
+
+	e := got.UnmarshalMsgpack(in)
+	if e != nil {
+		t.Fatal(e)
+	}
+
+	assert(t, tabula.DatasetModeRows, got.Mode, true)
+	assert(t, "&[1 a]", got.Rows.String(), true)
+}
+
+func TestClasetMsgpack(t *testing.T) {
+	claset := tabula.NewClaset(tabula.DatasetModeRows, testColTypes,
+		testColNames)
+	claset.SetClassIndex(testClassIdx)
+
+	rows, e := initRows()
+	if e != nil {
+		t.Fatal(e)
+	}
+	claset.SetRows(&rows)
+
+	b, e := claset.MarshalMsgpack()
+	if e != nil {
+		t.Fatal(e)
+	}
+
+	got := tabula.Claset{}
+
+	e = got.UnmarshalMsgpack(b)
+	if e != nil {
+		t.Fatal(e)
+	}
+
+	assert(t, claset, &got, true)
+}
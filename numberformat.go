@@ -0,0 +1,84 @@
+// Copyright 2017 M. Shulhan <ms@kilabit.info>. All rights reserved.
+// Use of this source code is governed by a BSD-style license that can be found
+// in the LICENSE file.
+
+package tabula
+
+import (
+	"strings"
+	"unicode"
+)
+
+//
+// NumberFormat define the locale format of numeric value in input, which is
+// used to convert the text into integer or real value.
+//
+// For example, to read "€ 1.234,56" or "12,5%" as real value, set Decimal to
+// ",", Thousands to ".", and Symbols to {"€", "%"}.
+//
+type NumberFormat struct {
+	// Decimal is the decimal separator. If its empty, it will default to
+	// ".".
+	Decimal string
+	// Thousands is the digit grouping separator, for example "," in
+	// English, "." in German, or space in French. If its empty, digit
+	// grouping is not allowed.
+	Thousands string
+	// Symbols contain the currency or percent sign that will be removed
+	// from value, for example "$", "€", "USD", or "%".
+	Symbols []string
+}
+
+//
+// Normalize convert numeric value `v` in this format into the format
+// accepted by strconv.ParseInt and strconv.ParseFloat, by removing the
+// symbols, spaces, and thousands separator, and replacing the decimal
+// separator with ".".
+//
+func (nf *NumberFormat) Normalize(v string) string {
+	if nf == nil {
+		return v
+	}
+
+	for _, sym := range nf.Symbols {
+		if sym != "" {
+			v = strings.Replace(v, sym, "", -1)
+		}
+	}
+	if nf.Thousands != "" {
+		v = strings.Replace(v, nf.Thousands, "", -1)
+	}
+	v = strings.Map(func(r rune) rune {
+		if unicode.IsSpace(r) {
+			return -1
+		}
+		return r
+	}, v)
+	if nf.Decimal != "" && nf.Decimal != "." {
+		v = strings.Replace(v, nf.Decimal, ".", 1)
+	}
+
+	return v
+}
+
+//
+// newRecordFormat create new record from value `v` with type `t`. If type
+// is integer or real, the value is normalized using number format `nf`. If
+// type is time, the value is parsed using time format `tf`.
+//
+func newRecordFormat(v string, t int, nf *NumberFormat, tf *TimeFormat) (
+	*Record, error,
+) {
+	if t == TTime {
+		rec := NewRecord()
+		e := rec.SetTimeValue(v, tf)
+		if e != nil {
+			return nil, e
+		}
+		return rec, nil
+	}
+	if nf != nil && (t == TInteger || t == TReal) {
+		v = nf.Normalize(v)
+	}
+	return NewRecordBy(v, t)
+}
@@ -0,0 +1,71 @@
+// Copyright 2017 M. Shulhan <ms@kilabit.info>. All rights reserved.
+// Use of this source code is governed by a BSD-style license that can be found
+// in the LICENSE file.
+
+package tabula_test
+
+import (
+	"github.com/shuLhan/tabula"
+	"strings"
+	"testing"
+)
+
+func TestNumberFormatNormalize(t *testing.T) {
+	cases := []struct {
+		nf  *tabula.NumberFormat
+		in  string
+		exp string
+	}{{
+		nf:  nil,
+		in:  "1,234.5",
+		exp: "1,234.5",
+	}, {
+		nf:  &tabula.NumberFormat{Thousands: ","},
+		in:  " 1,234.5 ",
+		exp: "1234.5",
+	}, {
+		nf: &tabula.NumberFormat{
+			Decimal:   ",",
+			Thousands: ".",
+			Symbols:   []string{"€"},
+		},
+		in:  "-€ 1.234.567,89",
+		exp: "-1234567.89",
+	}, {
+		nf: &tabula.NumberFormat{
+			Decimal:   ",",
+			Thousands: " ",
+			Symbols:   []string{"%"},
+		},
+		in:  "12 345,5 %",
+		exp: "12345.5",
+	}}
+
+	for _, c := range cases {
+		assert(t, c.exp, c.nf.Normalize(c.in), true)
+	}
+}
+
+func TestFixedWidthReaderNumberFormat(t *testing.T) {
+	fields := []tabula.FixedWidthField{
+		{Name: "qty", Type: tabula.TInteger, Start: 0, Width: 6},
+		{Name: "rate", Type: tabula.TReal, Start: 6, Width: 6},
+	}
+	input := " 1.000 12,5%\n    15  0,5%\n"
+
+	reader := tabula.NewFixedWidthReader(fields)
+	reader.NumberFormat = &tabula.NumberFormat{
+		Decimal:   ",",
+		Thousands: ".",
+		Symbols:   []string{"%"},
+	}
+
+	ds := tabula.NewDataset(tabula.DatasetModeRows, nil, nil)
+
+	e := reader.Read(strings.NewReader(input), ds)
+	if e != nil {
+		t.Fatal(e)
+	}
+
+	assert(t, "&[1000 12.5]&[15 0.5]", ds.Rows.String(), true)
+}
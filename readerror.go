@@ -0,0 +1,44 @@
+// Copyright 2017 M. Shulhan <ms@kilabit.info>. All rights reserved.
+// Use of this source code is governed by a BSD-style license that can be found
+// in the LICENSE file.
+
+package tabula
+
+import (
+	"errors"
+	"fmt"
+)
+
+var (
+	// ErrUnclosedQuote returned when reader reach end of input but the
+	// quoted value is not closed yet.
+	ErrUnclosedQuote = errors.New("tabula: unclosed quote")
+)
+
+//
+// ReadError contain the position of input that can not be read or converted
+// into record.
+//
+type ReadError struct {
+	// Line number in input, start from 1.
+	Line int
+	// Column number in line, start from 1. Zero means the error is not
+	// specific to any column, for example when number of fields does not
+	// match with number of columns.
+	Column int
+	// Value contain the original value that can not be read.
+	Value string
+	// Err contain the cause of error.
+	Err error
+}
+
+//
+// Error return the string representation of error.
+//
+func (re *ReadError) Error() string {
+	if re.Column <= 0 {
+		return fmt.Sprintf("tabula: line %d: %s", re.Line, re.Err)
+	}
+	return fmt.Sprintf("tabula: line %d column %d: %q: %s", re.Line,
+		re.Column, re.Value, re.Err)
+}
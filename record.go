@@ -5,9 +5,16 @@
 package tabula
 
 import (
+	"bytes"
+	"encoding/binary"
+	"encoding/json"
+	"errors"
+	"io"
 	"math"
 	"reflect"
 	"strconv"
+	"strings"
+	"time"
 )
 
 const (
@@ -19,8 +26,55 @@ const (
 	TInteger = 1
 	// TReal float type (64 bit).
 	TReal = 2
+	// TBool boolean type.
+	TBool = 3
+	// TTime date and time type.
+	TTime = 4
+	// TDecimal fixed-point decimal type, see Decimal.
+	TDecimal = 5
 )
 
+// List of record value kind in binary encoding.
+const (
+	recordBinaryNil byte = iota
+	recordBinaryString
+	recordBinaryInteger
+	recordBinaryReal
+	recordBinaryBool
+	recordBinaryTime
+	recordBinaryNull
+	recordBinaryDecimal
+)
+
+// List of boolean value in binary encoding.
+const (
+	recordBinaryFalse byte = iota
+	recordBinaryTrue
+	recordBinaryBoolMissing
+)
+
+var (
+	// ErrInvalidJSONValue returned when JSON value can not be converted
+	// into record.
+	ErrInvalidJSONValue = errors.New("tabula: invalid JSON value for record")
+	// ErrInvalidBool returned when string can not be converted into
+	// boolean.
+	ErrInvalidBool = errors.New("tabula: invalid boolean value")
+	// ErrNullValue returned when null record is converted into numeric
+	// value.
+	ErrNullValue = errors.New("tabula: record is null")
+)
+
+//
+// boolMissing is the value of boolean record that is missing.
+//
+type boolMissing struct{}
+
+//
+// recordNull is the value of null record, which contain the type of record.
+//
+type recordNull int
+
 //
 // Record represent the smallest building block of data-set.
 //
@@ -65,6 +119,57 @@ func NewRecordReal(v float64) (r *Record) {
 	return &Record{v: v}
 }
 
+//
+// NewRecordBool create new record from boolean value.
+//
+func NewRecordBool(v bool) (r *Record) {
+	return &Record{v: v}
+}
+
+//
+// NewRecordTime create new record from time value.
+//
+func NewRecordTime(v time.Time) (r *Record) {
+	return &Record{v: v}
+}
+
+//
+// NewRecordDecimal create new record from decimal value.
+//
+func NewRecordDecimal(v Decimal) (r *Record) {
+	return &Record{v: v}
+}
+
+//
+// NewRecordNull create new null record with type `t`.
+//
+func NewRecordNull(t int) (r *Record) {
+	return &Record{v: recordNull(t)}
+}
+
+//
+// Code return the categorical code of record, which is the index of its
+// value in column value space, or -1 if record is not categorical. See
+// Column.Categorical.
+//
+func (r *Record) Code() int {
+	if c, ok := r.v.(*categoryCode); ok {
+		return c.code
+	}
+	return -1
+}
+
+//
+// value return the record value, where categorical code is converted into
+// its string value.
+//
+func (r *Record) value() interface{} {
+	if c, ok := r.v.(*categoryCode); ok {
+		return c.String()
+	}
+	return r.v
+}
+
 //
 // Clone will create and return a clone of record.
 //
@@ -80,21 +185,53 @@ func (r *Record) IsNil() bool {
 }
 
 //
-// Type of record.
+// IsNull return true if record is null, which is set by SetNull, or if it
+// has not been set with value. Unlike missing value, null does not use any
+// value of its type, so it will not collide with the data.
 //
-func (r *Record) Type() int {
+func (r *Record) IsNull() bool {
 	switch r.v.(type) {
+	case nil, recordNull:
+		return true
+	}
+	return false
+}
+
+//
+// SetNull will set the record to null with type `t`.
+//
+func (r *Record) SetNull(t int) {
+	r.v = recordNull(t)
+}
+
+//
+// Type of record. Null record return the type that is set by SetNull.
+//
+func (r *Record) Type() int {
+	switch v := r.v.(type) {
+	case recordNull:
+		return int(v)
 	case int64:
 		return TInteger
 	case float64:
 		return TReal
+	case bool, boolMissing:
+		return TBool
+	case time.Time:
+		return TTime
+	case Decimal:
+		return TDecimal
 	}
 	return TString
 }
 
 //
 // SetValue set the record value from string using type `t`. If value can not
-// be converted to type, it will return an error.
+// be converted to type, it will return an error. See ParseBool for the value
+// that is accepted for TBool. TTime value is parsed using DefaultTimeLayouts
+// in UTC, use SetTimeValue to parse with other layouts. TDecimal value is
+// parsed exactly with the number of digits after decimal point in `v` as
+// its scale, see ParseDecimal.
 //
 func (r *Record) SetValue(v string, t int) error {
 	switch t {
@@ -116,10 +253,58 @@ func (r *Record) SetValue(v string, t int) error {
 		}
 
 		r.v = f64
+
+	case TBool:
+		b, e := ParseBool(v)
+		if nil != e {
+			return e
+		}
+
+		r.v = b
+
+	case TTime:
+		return r.SetTimeValue(v, nil)
+
+	case TDecimal:
+		d, e := ParseDecimal(v, -1)
+		if nil != e {
+			return e
+		}
+
+		r.v = d
 	}
 	return nil
 }
 
+//
+// SetTimeValue set the record value from string `v` that is parsed using
+// time format `tf`. If `tf` is nil, DefaultTimeLayouts in UTC is used.
+//
+func (r *Record) SetTimeValue(v string, tf *TimeFormat) error {
+	t, e := tf.Parse(v)
+	if e != nil {
+		return e
+	}
+	r.v = t
+	return nil
+}
+
+//
+// ParseBool convert string `v` into boolean. The string is case insensitive
+// and surrounding spaces is ignored. The value "true", "t", "yes", "y",
+// "on", and "1" is converted to true; "false", "f", "no", "n", "off", and
+// "0" is converted to false. Other value will return ErrInvalidBool.
+//
+func ParseBool(v string) (bool, error) {
+	switch strings.ToLower(strings.TrimSpace(v)) {
+	case "true", "t", "yes", "y", "on", "1":
+		return true, nil
+	case "false", "f", "no", "n", "off", "0":
+		return false, nil
+	}
+	return false, ErrInvalidBool
+}
+
 //
 // SetString will set the record value with string value.
 //
@@ -141,6 +326,27 @@ func (r *Record) SetInteger(v int64) {
 	r.v = v
 }
 
+//
+// SetBool will set the record value with boolean.
+//
+func (r *Record) SetBool(v bool) {
+	r.v = v
+}
+
+//
+// SetTime will set the record value with time.
+//
+func (r *Record) SetTime(v time.Time) {
+	r.v = v
+}
+
+//
+// SetDecimal will set the record value with decimal.
+//
+func (r *Record) SetDecimal(v Decimal) {
+	r.v = v
+}
+
 //
 // IsMissingValue check wether the value is a missing attribute.
 //
@@ -151,14 +357,30 @@ func (r *Record) SetInteger(v int64) {
 //
 // If its real the missing value is indicated by -Inf.
 //
+// If its boolean the missing value is set by SetMissingValue, which is
+// neither true nor false.
+//
+// If its time the missing value is indicated by zero time.
+//
+// If its decimal the missing value is indicated by math.MinInt64 as its
+// unscaled value.
+//
+// Null record, see SetNull, is also a missing value.
+//
 func (r *Record) IsMissingValue() bool {
 	switch r.v.(type) {
+	case recordNull:
+		return true
+
 	case string:
 		str := r.v.(string)
 		if str == "?" {
 			return true
 		}
 
+	case *categoryCode:
+		return r.v.(*categoryCode).String() == "?"
+
 	case int64:
 		i64 := r.v.(int64)
 		if i64 == math.MinInt64 {
@@ -168,16 +390,51 @@ func (r *Record) IsMissingValue() bool {
 	case float64:
 		f64 := r.v.(float64)
 		return math.IsInf(f64, -1)
+
+	case boolMissing:
+		return true
+
+	case time.Time:
+		return r.v.(time.Time).IsZero()
+
+	case Decimal:
+		return r.v.(Decimal).IsMissing()
 	}
 
 	return false
 }
 
 //
-// Interface return record value as interface.
+// SetMissingValue will set the record value to missing value based on type
+// `t`. See IsMissingValue for the value of each type.
+//
+func (r *Record) SetMissingValue(t int) {
+	switch t {
+	case TString:
+		r.v = "?"
+	case TInteger:
+		r.v = int64(math.MinInt64)
+	case TReal:
+		r.v = math.Inf(-1)
+	case TBool:
+		r.v = boolMissing{}
+	case TTime:
+		r.v = time.Time{}
+	case TDecimal:
+		r.v = Decimal{Value: math.MinInt64}
+	}
+}
+
+//
+// Interface return record value as interface. Null and missing boolean
+// value is returned as nil, and categorical value is returned as string.
 //
 func (r *Record) Interface() interface{} {
-	return r.v
+	switch r.v.(type) {
+	case boolMissing, recordNull:
+		return nil
+	}
+	return r.value()
 }
 
 //
@@ -188,32 +445,54 @@ func (r *Record) Bytes() []byte {
 }
 
 //
-// String convert record value to string.
+// String convert record value to string. Null is converted to empty
+// string.
 //
 func (r Record) String() (s string) {
 	switch r.v.(type) {
 	case string:
 		s = r.v.(string)
 
+	case *categoryCode:
+		s = r.v.(*categoryCode).String()
+
 	case int64:
 		s = strconv.FormatInt(r.v.(int64), 10)
 
 	case float64:
 		s = strconv.FormatFloat(r.v.(float64), 'f', -1, 64)
+
+	case bool:
+		s = strconv.FormatBool(r.v.(bool))
+
+	case boolMissing:
+		s = "?"
+
+	case Decimal:
+		s = r.v.(Decimal).String()
+
+	case time.Time:
+		t := r.v.(time.Time)
+		if t.IsZero() {
+			s = "?"
+		} else {
+			s = t.Format(DefaultTimeLayouts[0])
+		}
 	}
 	return
 }
 
 //
-// Float convert given record to float value. If its failed it will return
-// the -Infinity value.
+// Float convert given record to float value. If its failed or the record is
+// null, it will return the -Infinity value. Use ToFloat to differentiate
+// between them.
 //
 func (r *Record) Float() (f64 float64) {
 	var e error
 
 	switch r.v.(type) {
-	case string:
-		f64, e = strconv.ParseFloat(r.v.(string), 64)
+	case string, *categoryCode:
+		f64, e = strconv.ParseFloat(r.String(), 64)
 
 		if nil != e {
 			f64 = math.Inf(-1)
@@ -224,21 +503,41 @@ func (r *Record) Float() (f64 float64) {
 
 	case float64:
 		f64 = r.v.(float64)
+
+	case bool:
+		if r.v.(bool) {
+			f64 = 1
+		}
+
+	case boolMissing, recordNull:
+		f64 = math.Inf(-1)
+
+	case time.Time:
+		t := r.v.(time.Time)
+		if t.IsZero() {
+			f64 = math.Inf(-1)
+		} else {
+			f64 = float64(t.Unix()) + float64(t.Nanosecond())/1e9
+		}
+
+	case Decimal:
+		f64 = r.v.(Decimal).Float()
 	}
 
 	return
 }
 
 //
-// Integer convert given record to integer value. If its failed, it will return
-// the minimum integer in 64bit.
+// Integer convert given record to integer value. If its failed or the record
+// is null, it will return the minimum integer in 64bit. Use ToInteger to
+// differentiate between them.
 //
 func (r *Record) Integer() (i64 int64) {
 	var e error
 
 	switch r.v.(type) {
-	case string:
-		i64, e = strconv.ParseInt(r.v.(string), 10, 64)
+	case string, *categoryCode:
+		i64, e = strconv.ParseInt(r.String(), 10, 64)
 
 		if nil != e {
 			i64 = math.MinInt64
@@ -249,16 +548,192 @@ func (r *Record) Integer() (i64 int64) {
 
 	case float64:
 		i64 = int64(r.v.(float64))
+
+	case bool:
+		if r.v.(bool) {
+			i64 = 1
+		}
+
+	case boolMissing, recordNull:
+		i64 = math.MinInt64
+
+	case time.Time:
+		t := r.v.(time.Time)
+		if t.IsZero() {
+			i64 = math.MinInt64
+		} else {
+			i64 = t.Unix()
+		}
+
+	case Decimal:
+		i64 = r.v.(Decimal).Integer()
 	}
 
 	return
 }
 
+//
+// ToFloat convert record to float value, like Float, but return ErrNullValue
+// if record is null and the parsing error if string can not be converted.
+//
+func (r *Record) ToFloat() (float64, error) {
+	switch r.v.(type) {
+	case nil, recordNull:
+		return 0, ErrNullValue
+	case string, *categoryCode:
+		return strconv.ParseFloat(r.String(), 64)
+	}
+	return r.Float(), nil
+}
+
+//
+// ToInteger convert record to integer value, like Integer, but return
+// ErrNullValue if record is null and the parsing error if string can not be
+// converted.
+//
+func (r *Record) ToInteger() (int64, error) {
+	switch r.v.(type) {
+	case nil, recordNull:
+		return 0, ErrNullValue
+	case string, *categoryCode:
+		return strconv.ParseInt(r.String(), 10, 64)
+	}
+	return r.Integer(), nil
+}
+
+//
+// Bool convert given record to boolean value. String is converted using
+// ParseBool, and numeric value is true if its not zero. If its failed or
+// the record is missing value, it will return false.
+//
+func (r *Record) Bool() (b bool) {
+	switch v := r.value().(type) {
+	case string:
+		b, _ = ParseBool(v)
+	case int64:
+		b = v != 0 && v != math.MinInt64
+	case float64:
+		b = v != 0 && !math.IsInf(v, -1) && !math.IsNaN(v)
+	case bool:
+		b = v
+	case Decimal:
+		b = v.Value != 0 && !v.IsMissing()
+	}
+	return
+}
+
+//
+// Time return the record value as time. String is parsed using
+// DefaultTimeLayouts, and integer and real is converted from Unix time in
+// seconds. If its failed, it will return zero time.
+//
+func (r *Record) Time() (t time.Time) {
+	switch v := r.value().(type) {
+	case string:
+		t, _ = (*TimeFormat)(nil).Parse(v)
+	case int64:
+		if v != math.MinInt64 {
+			t = time.Unix(v, 0).UTC()
+		}
+	case float64:
+		if !math.IsInf(v, 0) && !math.IsNaN(v) {
+			sec, frac := math.Modf(v)
+			t = time.Unix(int64(sec), int64(frac*1e9)).UTC()
+		}
+	case time.Time:
+		t = v
+	}
+	return
+}
+
+//
+// Decimal return the record value as decimal. String is parsed using
+// ParseDecimal with its own scale, integer is converted with zero scale,
+// real is converted using the shortest representation of its value, and
+// boolean is converted to 1 or 0. If its failed, or the record is missing
+// value or null, it will return missing value.
+//
+func (r *Record) Decimal() (d Decimal) {
+	d.Value = math.MinInt64
+
+	switch v := r.value().(type) {
+	case string:
+		if pd, e := ParseDecimal(v, -1); e == nil {
+			d = pd
+		}
+	case int64:
+		d.Value = v
+	case float64:
+		if !math.IsInf(v, 0) && !math.IsNaN(v) {
+			s := strconv.FormatFloat(v, 'f', -1, 64)
+			if pd, e := ParseDecimal(s, -1); e == nil {
+				d = pd
+			}
+		}
+	case bool:
+		d.Value = 0
+		if v {
+			d.Value = 1
+		}
+	case Decimal:
+		d = v
+	}
+	return
+}
+
+//
+// Compare return -1 if record is less than other, 1 if its greater than
+// other, and 0 if its equal. Time is compared by its instant, string is
+// compared lexicographically, decimal is compared exactly, and the rest is
+// compared by their numeric value.
+//
+func (r *Record) Compare(o *Record) int {
+	switch v := r.value().(type) {
+	case time.Time:
+		if ov, ok := o.v.(time.Time); ok {
+			switch {
+			case v.Before(ov):
+				return -1
+			case v.After(ov):
+				return 1
+			}
+			return 0
+		}
+	case string:
+		if ov, ok := o.value().(string); ok {
+			return strings.Compare(v, ov)
+		}
+	case int64:
+		if ov, ok := o.v.(int64); ok {
+			switch {
+			case v < ov:
+				return -1
+			case v > ov:
+				return 1
+			}
+			return 0
+		}
+	case Decimal:
+		if ov, ok := o.v.(Decimal); ok {
+			return v.Cmp(ov)
+		}
+	}
+
+	f1, f2 := r.Float(), o.Float()
+	switch {
+	case f1 < f2:
+		return -1
+	case f1 > f2:
+		return 1
+	}
+	return 0
+}
+
 //
 // IsEqual return true if record is equal with other, otherwise return false.
 //
 func (r *Record) IsEqual(o *Record) bool {
-	return reflect.DeepEqual(r.v, o.Interface())
+	return reflect.DeepEqual(r.value(), o.value())
 }
 
 //
@@ -274,19 +749,448 @@ func (r *Record) IsEqualToString(v string) bool {
 // type and value.
 //
 func (r *Record) IsEqualToInterface(v interface{}) bool {
-	return reflect.DeepEqual(r.v, v)
+	return reflect.DeepEqual(r.value(), v)
 }
 
 //
 // Reset will reset record value to empty string or zero, depend on type.
+// Null record is reset to zero value of its type.
 //
 func (r *Record) Reset() {
+	if v, ok := r.v.(recordNull); ok {
+		switch int(v) {
+		case TInteger:
+			r.v = int64(0)
+		case TReal:
+			r.v = float64(0)
+		case TBool:
+			r.v = false
+		case TTime:
+			r.v = time.Time{}
+		case TDecimal:
+			r.v = Decimal{}
+		default:
+			r.v = ""
+		}
+		return
+	}
+
 	switch r.v.(type) {
-	case string:
+	case string, *categoryCode:
 		r.v = ""
 	case int64:
 		r.v = int64(0)
 	case float64:
 		r.v = float64(0)
+	case bool, boolMissing:
+		r.v = false
+	case time.Time:
+		r.v = time.Time{}
+	case Decimal:
+		r.v = Decimal{Scale: r.v.(Decimal).Scale}
+	}
+}
+
+//
+// MarshalJSON convert record value into JSON. String is converted to JSON
+// string, integer to JSON number without fraction, real to JSON number
+// with fraction or exponent, and boolean to JSON true or false, so the type
+// can be restored back by UnmarshalJSON. Time is converted to JSON string
+// using the first of DefaultTimeLayouts. Decimal is converted to JSON
+// number with all digits after decimal point. Nil, null, infinity, NaN,
+// and missing boolean, time, and decimal value is converted to null.
+//
+func (r *Record) MarshalJSON() ([]byte, error) {
+	switch v := r.value().(type) {
+	case string:
+		return json.Marshal(v)
+	case int64:
+		return strconv.AppendInt(nil, v, 10), nil
+	case float64:
+		if math.IsInf(v, 0) || math.IsNaN(v) {
+			return []byte("null"), nil
+		}
+		return appendJSONFloat(nil, v), nil
+	case bool:
+		return strconv.AppendBool(nil, v), nil
+	case time.Time:
+		if v.IsZero() {
+			return []byte("null"), nil
+		}
+		return json.Marshal(v.Format(DefaultTimeLayouts[0]))
+	case Decimal:
+		if v.IsMissing() {
+			return []byte("null"), nil
+		}
+		return []byte(v.String()), nil
+	}
+	return []byte("null"), nil
+}
+
+//
+// UnmarshalJSON set the record value from JSON. JSON string is converted to
+// string, JSON number without fraction and exponent to integer, other JSON
+// number to real, JSON true and false to boolean, and null to nil.
+//
+func (r *Record) UnmarshalJSON(b []byte) (e error) {
+	rec, e := NewRecordJSON(b, TUndefined)
+	if e != nil {
+		return e
+	}
+	r.v = rec.v
+	return nil
+}
+
+//
+// NewRecordJSON create new record from JSON value `b` using type `t`.
+// If type is TUndefined, the type is detected from JSON value, see
+// UnmarshalJSON.
+// If type is defined, null is converted to null record with that type, see
+// NewRecordNull, and number is converted to the type.
+//
+func NewRecordJSON(b []byte, t int) (r *Record, e error) {
+	b = bytes.TrimSpace(b)
+	r = NewRecord()
+
+	if len(b) == 0 {
+		return nil, ErrInvalidJSONValue
+	}
+
+	if bytes.Equal(b, []byte("null")) {
+		if t != TUndefined {
+			r = NewRecordNull(t)
+		}
+		return r, nil
+	}
+
+	if b[0] == '"' {
+		var s string
+
+		e = json.Unmarshal(b, &s)
+		if e != nil {
+			return nil, e
+		}
+		if t == TUndefined {
+			t = TString
+		}
+
+		e = r.SetValue(s, t)
+		if e != nil {
+			return nil, e
+		}
+		return r, nil
+	}
+
+	if bytes.Equal(b, []byte("true")) || bytes.Equal(b, []byte("false")) {
+		if t == TUndefined {
+			t = TBool
+		}
+		if t != TBool && t != TString {
+			return nil, ErrInvalidJSONValue
+		}
+
+		e = r.SetValue(string(b), t)
+		if e != nil {
+			return nil, e
+		}
+		return r, nil
+	}
+
+	if b[0] != '-' && (b[0] < '0' || b[0] > '9') {
+		return nil, ErrInvalidJSONValue
+	}
+
+	if t == TUndefined {
+		t = TInteger
+		if bytes.ContainsAny(b, ".eE") {
+			t = TReal
+		}
+	}
+
+	e = r.SetValue(string(b), t)
+	if e != nil {
+		return nil, e
+	}
+
+	return r, nil
+}
+
+//
+// appendJSONFloat append float value `f` into `b` using the same format as
+// encoding/json, but make sure that the value contain fraction or exponent.
+//
+func appendJSONFloat(b []byte, f float64) []byte {
+	format := byte('f')
+	abs := math.Abs(f)
+	if abs != 0 && (abs < 1e-6 || abs >= 1e21) {
+		format = 'e'
+	}
+
+	start := len(b)
+	b = strconv.AppendFloat(b, f, format, -1, 64)
+
+	if !bytes.ContainsAny(b[start:], ".e") {
+		b = append(b, '.', '0')
+	}
+
+	return b
+}
+
+//
+// MarshalBinary convert record value into binary, which contain the kind of
+// value followed by the value itself.
+//
+func (r *Record) MarshalBinary() ([]byte, error) {
+	return r.appendBinary(nil), nil
+}
+
+//
+// UnmarshalBinary set the record value from binary that is created by
+// MarshalBinary.
+//
+func (r *Record) UnmarshalBinary(b []byte) (e error) {
+	br := bytes.NewReader(b)
+
+	rec, e := readRecordBinary(br)
+	if e != nil {
+		return e
+	}
+	if br.Len() > 0 {
+		return ErrInvalidBinary
+	}
+
+	r.v = rec.v
+
+	return nil
+}
+
+//
+// GobEncode implement the gob.GobEncoder interface, see MarshalBinary.
+//
+func (r *Record) GobEncode() ([]byte, error) {
+	return r.MarshalBinary()
+}
+
+//
+// GobDecode implement the gob.GobDecoder interface, see UnmarshalBinary.
+//
+func (r *Record) GobDecode(b []byte) error {
+	return r.UnmarshalBinary(b)
+}
+
+//
+// appendBinary convert record value into binary and append it to `b`.
+//
+func (r *Record) appendBinary(b []byte) []byte {
+	switch v := r.value().(type) {
+	case string:
+		b = append(b, recordBinaryString)
+		return appendBinaryString(b, v)
+	case int64:
+		b = append(b, recordBinaryInteger)
+		return appendVarint(b, v)
+	case float64:
+		var f64 [8]byte
+		binary.LittleEndian.PutUint64(f64[:], math.Float64bits(v))
+		b = append(b, recordBinaryReal)
+		return append(b, f64[:]...)
+	case bool:
+		if v {
+			return append(b, recordBinaryBool, recordBinaryTrue)
+		}
+		return append(b, recordBinaryBool, recordBinaryFalse)
+	case boolMissing:
+		return append(b, recordBinaryBool, recordBinaryBoolMissing)
+	case time.Time:
+		_, offset := v.Zone()
+		b = append(b, recordBinaryTime)
+		b = appendVarint(b, v.Unix())
+		b = appendUvarint(b, uint64(v.Nanosecond()))
+		return appendVarint(b, int64(offset))
+	case recordNull:
+		b = append(b, recordBinaryNull)
+		return appendVarint(b, int64(v))
+	case Decimal:
+		b = append(b, recordBinaryDecimal)
+		b = appendVarint(b, v.Value)
+		return appendUvarint(b, uint64(v.Scale))
+	}
+	return append(b, recordBinaryNil)
+}
+
+//
+// readRecordBinary read binary record from `br` and return it.
+//
+func readRecordBinary(br *bytes.Reader) (r *Record, e error) {
+	kind, e := br.ReadByte()
+	if e != nil {
+		return nil, ErrInvalidBinary
+	}
+
+	r = NewRecord()
+
+	switch kind {
+	case recordBinaryNil:
+	case recordBinaryString:
+		s, e := readBinaryString(br, ErrInvalidBinary)
+		if e != nil {
+			return nil, e
+		}
+		r.v = s
+	case recordBinaryInteger:
+		i64, e := readVarint(br, ErrInvalidBinary)
+		if e != nil {
+			return nil, e
+		}
+		r.v = i64
+	case recordBinaryReal:
+		var f64 [8]byte
+		_, e = io.ReadFull(br, f64[:])
+		if e != nil {
+			return nil, ErrInvalidBinary
+		}
+		r.v = math.Float64frombits(binary.LittleEndian.Uint64(f64[:]))
+	case recordBinaryBool:
+		c, e := br.ReadByte()
+		if e != nil {
+			return nil, ErrInvalidBinary
+		}
+		switch c {
+		case recordBinaryFalse:
+			r.v = false
+		case recordBinaryTrue:
+			r.v = true
+		case recordBinaryBoolMissing:
+			r.v = boolMissing{}
+		default:
+			return nil, ErrInvalidBinary
+		}
+	case recordBinaryTime:
+		t, e := readBinaryTime(br)
+		if e != nil {
+			return nil, e
+		}
+		r.v = t
+	case recordBinaryNull:
+		t, e := readVarint(br, ErrInvalidBinary)
+		if e != nil {
+			return nil, e
+		}
+		r.v = recordNull(t)
+	case recordBinaryDecimal:
+		v, e := readVarint(br, ErrInvalidBinary)
+		if e != nil {
+			return nil, e
+		}
+		scale, e := readUvarint(br, ErrInvalidBinary)
+		if e != nil {
+			return nil, e
+		}
+		if scale > DecimalMaxScale {
+			return nil, ErrInvalidBinary
+		}
+		r.v = Decimal{Value: v, Scale: int(scale)}
+	default:
+		return nil, ErrInvalidBinary
 	}
+
+	return r, nil
+}
+
+//
+// readBinaryTime read Unix time in seconds, nanoseconds, and time zone offset
+// in seconds from `br`. Time with zero offset is set to UTC, otherwise its
+// set to fixed time zone with the offset.
+//
+func readBinaryTime(br *bytes.Reader) (t time.Time, e error) {
+	sec, e := readVarint(br, ErrInvalidBinary)
+	if e != nil {
+		return
+	}
+	nsec, e := readUvarint(br, ErrInvalidBinary)
+	if e != nil {
+		return
+	}
+	offset, e := readVarint(br, ErrInvalidBinary)
+	if e != nil {
+		return
+	}
+	if nsec >= 1e9 {
+		return t, ErrInvalidBinary
+	}
+
+	t = time.Unix(sec, int64(nsec)).UTC()
+	if offset != 0 {
+		t = t.In(time.FixedZone("", int(offset)))
+	}
+
+	return t, nil
+}
+
+//
+// MarshalMsgpack convert record value into MessagePack. String is converted
+// to str, integer to int, real to float 64, boolean to bool, time to
+// timestamp extension in UTC, and nil and missing boolean and time to nil, so
+// the type can be restored back by UnmarshalMsgpack.
+//
+func (r *Record) MarshalMsgpack() ([]byte, error) {
+	return r.appendMsgpack(nil), nil
+}
+
+//
+// UnmarshalMsgpack set the record value from MessagePack. Str and bin is
+// converted to string, int to integer, float to real, bool to boolean,
+// timestamp extension to time in UTC, and nil to nil.
+//
+func (r *Record) UnmarshalMsgpack(b []byte) (e error) {
+	br := bytes.NewReader(b)
+
+	rec, e := readRecordMsgpack(br)
+	if e != nil {
+		return e
+	}
+	if br.Len() > 0 {
+		return ErrInvalidMsgpack
+	}
+
+	r.v = rec.v
+
+	return nil
+}
+
+//
+// appendMsgpack convert record value into MessagePack and append it to `b`.
+//
+func (r *Record) appendMsgpack(b []byte) []byte {
+	switch v := r.value().(type) {
+	case string:
+		return appendMsgpackString(b, v)
+	case int64:
+		return appendMsgpackInt(b, v)
+	case float64:
+		return appendMsgpackFloat(b, v)
+	case bool:
+		return appendMsgpackBool(b, v)
+	case time.Time:
+		return appendMsgpackTime(b, v)
+	case Decimal:
+		return appendMsgpackDecimal(b, v)
+	}
+	return appendMsgpackNil(b)
+}
+
+//
+// readRecordMsgpack read MessagePack value from `br` and return it as
+// record.
+//
+func readRecordMsgpack(br *bytes.Reader) (r *Record, e error) {
+	v, e := readMsgpackValue(br)
+	if e != nil {
+		return nil, e
+	}
+
+	r = NewRecord()
+	r.v = v
+
+	return r, nil
 }
@@ -5,9 +5,14 @@
 package tabula_test
 
 import (
+	"bytes"
+	"encoding/gob"
+	"encoding/json"
 	"fmt"
 	"github.com/shuLhan/tabula"
+	"math"
 	"testing"
+	"time"
 )
 
 //
@@ -32,3 +37,263 @@ func TestRecord(t *testing.T) {
 	got := fmt.Sprint(row)
 	assert(t, exp, got, true)
 }
+
+func TestRecordJSON(t *testing.T) {
+	recs := []*tabula.Record{
+		tabula.NewRecordString("a \"b\""),
+		tabula.NewRecordInt(1),
+		tabula.NewRecordReal(1),
+		tabula.NewRecordReal(0.5),
+		tabula.NewRecordReal(1e21),
+		tabula.NewRecordReal(math.Inf(-1)),
+		tabula.NewRecord(),
+	}
+	exps := []string{
+		`"a \"b\""`,
+		`1`,
+		`1.0`,
+		`0.5`,
+		`1e+21`,
+		`null`,
+		`null`,
+	}
+	expTypes := []int{
+		tabula.TString,
+		tabula.TInteger,
+		tabula.TReal,
+		tabula.TReal,
+		tabula.TReal,
+		tabula.TString,
+		tabula.TString,
+	}
+
+	for x, rec := range recs {
+		got, e := json.Marshal(rec)
+		if e != nil {
+			t.Fatal(e)
+		}
+
+		assert(t, exps[x], string(got), true)
+
+		newrec := tabula.NewRecord()
+
+		e = json.Unmarshal(got, newrec)
+		if e != nil {
+			t.Fatal(e)
+		}
+
+		assert(t, expTypes[x], newrec.Type(), true)
+		if x < 5 {
+			assert(t, rec, newrec, true)
+		}
+	}
+}
+
+func TestRecordBool(t *testing.T) {
+	cases := []struct {
+		in  string
+		exp bool
+		err error
+	}{
+		{in: "true", exp: true},
+		{in: " Yes ", exp: true},
+		{in: "Y", exp: true},
+		{in: "on", exp: true},
+		{in: "1", exp: true},
+		{in: "FALSE", exp: false},
+		{in: "f", exp: false},
+		{in: "no", exp: false},
+		{in: "Off", exp: false},
+		{in: "0", exp: false},
+		{in: "2", err: tabula.ErrInvalidBool},
+		{in: "", err: tabula.ErrInvalidBool},
+	}
+
+	for _, c := range cases {
+		rec, e := tabula.NewRecordBy(c.in, tabula.TBool)
+
+		assert(t, c.err, e, true)
+		if e != nil {
+			continue
+		}
+
+		assert(t, tabula.TBool, rec.Type(), true)
+		assert(t, c.exp, rec.Bool(), true)
+	}
+
+	rec := tabula.NewRecordBool(true)
+
+	assert(t, "true", rec.String(), true)
+	assert(t, int64(1), rec.Integer(), true)
+	assert(t, float64(1), rec.Float(), true)
+	assert(t, false, rec.IsMissingValue(), true)
+
+	rec.Reset()
+
+	assert(t, "false", rec.String(), true)
+	assert(t, int64(0), rec.Integer(), true)
+
+	rec.SetMissingValue(tabula.TBool)
+
+	assert(t, tabula.TBool, rec.Type(), true)
+	assert(t, true, rec.IsMissingValue(), true)
+	assert(t, false, rec.IsNil(), true)
+	assert(t, "?", rec.String(), true)
+	assert(t, int64(math.MinInt64), rec.Integer(), true)
+	assert(t, math.Inf(-1), rec.Float(), true)
+	assert(t, false, rec.Bool(), true)
+	assert(t, nil, rec.Interface(), true)
+
+	got, e := json.Marshal([]*tabula.Record{
+		tabula.NewRecordBool(true),
+		tabula.NewRecordBool(false),
+		rec,
+	})
+	if e != nil {
+		t.Fatal(e)
+	}
+
+	assert(t, "[true,false,null]", string(got), true)
+
+	rec, e = tabula.NewRecordJSON([]byte("false"), tabula.TUndefined)
+	if e != nil {
+		t.Fatal(e)
+	}
+
+	assert(t, tabula.NewRecordBool(false), rec, true)
+
+	_, e = tabula.NewRecordJSON([]byte("true"), tabula.TInteger)
+
+	assert(t, tabula.ErrInvalidJSONValue, e, true)
+}
+
+func TestRecordTime(t *testing.T) {
+	rec, e := tabula.NewRecordBy("2017-12-31 23:59:58.5", tabula.TTime)
+	if e != nil {
+		t.Fatal(e)
+	}
+
+	exp := time.Date(2017, 12, 31, 23, 59, 58, 5e8, time.UTC)
+
+	assert(t, tabula.TTime, rec.Type(), true)
+	assert(t, exp, rec.Time(), true)
+	assert(t, "2017-12-31T23:59:58.5Z", rec.String(), true)
+	assert(t, exp.Unix(), rec.Integer(), true)
+	assert(t, float64(exp.Unix())+0.5, rec.Float(), true)
+
+	_, e = tabula.NewRecordBy("31/12/2017", tabula.TTime)
+
+	assert(t, tabula.ErrInvalidTime, e, true)
+
+	tf := &tabula.TimeFormat{
+		Layouts:  []string{"02/01/2006 15:04"},
+		Location: time.FixedZone("WIB", 7*3600),
+	}
+
+	later := tabula.NewRecord()
+
+	e = later.SetTimeValue("01/01/2018 07:00", tf)
+	if e != nil {
+		t.Fatal(e)
+	}
+
+	assert(t, -1, rec.Compare(later), true)
+	assert(t, 1, later.Compare(rec), true)
+	assert(t, "2018-01-01T07:00:00+07:00", later.String(), true)
+	assert(t, "01/01/2018 07:00", tf.Format(later.Time()), true)
+
+	rec.SetMissingValue(tabula.TTime)
+
+	assert(t, tabula.TTime, rec.Type(), true)
+	assert(t, true, rec.IsMissingValue(), true)
+	assert(t, "?", rec.String(), true)
+	assert(t, int64(math.MinInt64), rec.Integer(), true)
+	assert(t, math.Inf(-1), rec.Float(), true)
+
+	got, e := json.Marshal([]*tabula.Record{
+		tabula.NewRecordTime(exp),
+		rec,
+	})
+	if e != nil {
+		t.Fatal(e)
+	}
+
+	assert(t, `["2017-12-31T23:59:58.5Z",null]`, string(got), true)
+}
+
+func TestRecordNull(t *testing.T) {
+	rec := tabula.NewRecordNull(tabula.TInteger)
+
+	assert(t, true, rec.IsNull(), true)
+	assert(t, false, rec.IsNil(), true)
+	assert(t, true, rec.IsMissingValue(), true)
+	assert(t, tabula.TInteger, rec.Type(), true)
+	assert(t, "", rec.String(), true)
+	assert(t, nil, rec.Interface(), true)
+
+	_, e := rec.ToInteger()
+
+	assert(t, tabula.ErrNullValue, e, true)
+
+	_, e = tabula.NewRecordString("x").ToFloat()
+
+	assert(t, false, e == nil || e == tabula.ErrNullValue, true)
+
+	missing := tabula.NewRecordInt(math.MinInt64)
+	i64, e := missing.ToInteger()
+
+	assert(t, nil, e, true)
+	assert(t, int64(math.MinInt64), i64, true)
+	assert(t, false, missing.IsNull(), true)
+	assert(t, false, rec.IsEqual(missing), true)
+
+	got, e := json.Marshal(rec)
+	if e != nil {
+		t.Fatal(e)
+	}
+
+	assert(t, "null", string(got), true)
+
+	rec.Reset()
+
+	assert(t, false, rec.IsNull(), true)
+	assert(t, int64(0), rec.Integer(), true)
+}
+
+func TestRecordGob(t *testing.T) {
+	recs := []*tabula.Record{
+		tabula.NewRecordString("a"),
+		tabula.NewRecordInt(math.MinInt64),
+		tabula.NewRecordReal(math.Inf(-1)),
+		tabula.NewRecordReal(0.1),
+		tabula.NewRecordBool(true),
+		tabula.NewRecordTime(time.Date(2017, 12, 31, 23, 59, 58, 5e8,
+			time.UTC)),
+		tabula.NewRecordDecimal(tabula.Decimal{Value: -1250, Scale: 2}),
+		tabula.NewRecord(),
+	}
+
+	missing := tabula.NewRecord()
+	missing.SetMissingValue(tabula.TBool)
+	recs = append(recs, missing, tabula.NewRecordNull(tabula.TReal))
+
+	var buf bytes.Buffer
+
+	e := gob.NewEncoder(&buf).Encode(recs)
+	if e != nil {
+		t.Fatal(e)
+	}
+
+	var got []*tabula.Record
+
+	e = gob.NewDecoder(&buf).Decode(&got)
+	if e != nil {
+		t.Fatal(e)
+	}
+
+	assert(t, recs, got, true)
+
+	e = got[0].UnmarshalBinary([]byte{9})
+
+	assert(t, tabula.ErrInvalidBinary, e, true)
+}
@@ -4,6 +4,11 @@
 
 package tabula
 
+import (
+	"bytes"
+	"encoding/json"
+)
+
 //
 // Row represent slice of record.
 //
@@ -121,3 +126,141 @@ func (row *Row) IsEqual(other *Row) bool {
 	}
 	return true
 }
+
+//
+// MarshalJSON convert row into JSON array of record values.
+//
+func (row *Row) MarshalJSON() ([]byte, error) {
+	return row.appendJSON(nil)
+}
+
+//
+// appendJSON convert row into JSON array and append it into `b`.
+//
+func (row *Row) appendJSON(b []byte) ([]byte, error) {
+	b = append(b, '[')
+	for x, rec := range *row {
+		if x > 0 {
+			b = append(b, ',')
+		}
+		if rec == nil {
+			b = append(b, "null"...)
+			continue
+		}
+		v, e := rec.MarshalJSON()
+		if e != nil {
+			return nil, e
+		}
+		b = append(b, v...)
+	}
+	return append(b, ']'), nil
+}
+
+//
+// UnmarshalJSON set the row from JSON array. Each value type is detected
+// from JSON, see Record.UnmarshalJSON.
+//
+func (row *Row) UnmarshalJSON(b []byte) (e error) {
+	return row.unmarshalJSON(b, nil)
+}
+
+//
+// unmarshalJSON set the row from JSON array, converting each value using
+// type in `types`. If types is nil, each value type is detected from JSON.
+//
+func (row *Row) unmarshalJSON(b []byte, types []int) (e error) {
+	var raws []json.RawMessage
+
+	e = json.Unmarshal(b, &raws)
+	if e != nil {
+		return e
+	}
+
+	if types != nil && len(types) != len(raws) {
+		return ErrMisColLength
+	}
+
+	newrow := make(Row, len(raws))
+
+	for x, raw := range raws {
+		t := TUndefined
+		if types != nil {
+			t = types[x]
+		}
+
+		newrow[x], e = NewRecordJSON(raw, t)
+		if e != nil {
+			return e
+		}
+	}
+
+	*row = newrow
+
+	return nil
+}
+
+//
+// MarshalMsgpack convert row into MessagePack array of record values, see
+// Record.MarshalMsgpack.
+//
+func (row *Row) MarshalMsgpack() ([]byte, error) {
+	return row.appendMsgpack(nil), nil
+}
+
+//
+// appendMsgpack convert row into MessagePack array and append it into `b`.
+//
+func (row *Row) appendMsgpack(b []byte) []byte {
+	b = appendMsgpackArray(b, len(*row))
+	for _, rec := range *row {
+		if rec == nil {
+			b = appendMsgpackNil(b)
+			continue
+		}
+		b = rec.appendMsgpack(b)
+	}
+	return b
+}
+
+//
+// UnmarshalMsgpack set the row from MessagePack array, see
+// Record.UnmarshalMsgpack.
+//
+func (row *Row) UnmarshalMsgpack(b []byte) (e error) {
+	br := bytes.NewReader(b)
+
+	newrow, e := readRowMsgpack(br)
+	if e != nil {
+		return e
+	}
+	if br.Len() > 0 {
+		return ErrInvalidMsgpack
+	}
+
+	*row = newrow
+
+	return nil
+}
+
+//
+// readRowMsgpack read MessagePack array from `br` and return it as row.
+//
+func readRowMsgpack(br *bytes.Reader) (row Row, e error) {
+	n, e := readMsgpackArray(br)
+	if e != nil {
+		return nil, e
+	}
+	if n < 0 {
+		return nil, ErrInvalidMsgpack
+	}
+
+	row = make(Row, n)
+	for x := range row {
+		row[x], e = readRecordMsgpack(br)
+		if e != nil {
+			return nil, e
+		}
+	}
+
+	return row, nil
+}
@@ -0,0 +1,347 @@
+// Copyright 2017 M. Shulhan <ms@kilabit.info>. All rights reserved.
+// Use of this source code is governed by a BSD-style license that can be found
+// in the LICENSE file.
+
+package tabula
+
+import (
+	"database/sql"
+	"fmt"
+	"io"
+	"reflect"
+	"strconv"
+	"strings"
+	"time"
+)
+
+//
+// SQLReader read the result of SQL query into dataset.
+//
+// If dataset does not have any columns, the columns will be created from
+// query result columns. Column with database type integer (INT, BIGINT,
+// SERIAL, ...) is mapped to TInteger, floating point (REAL, DOUBLE, ...) to
+// TReal, NUMERIC and DECIMAL to TDecimal with the scale reported by driver,
+// or to TReal if driver does not report the scale, boolean (BOOL, BOOLEAN)
+// to TBool, date and time (DATE, DATETIME, TIMESTAMP, ...) to TTime, and the
+// rest to TString. If the driver does not report the database type, the scan
+// type is used. Date and time that is returned as text by driver is parsed
+// using column time format.
+//
+// SQL NULL is converted to null record based on the column type, see
+// NewRecordNull.
+//
+type SQLReader struct {
+	// BatchSize maximum number of rows to be read on each call to Read.
+	// If its zero or negative, all rows will be read.
+	BatchSize int
+	// ClassName is the name of class column, used when reading into
+	// claset with no columns. If its empty, the last column is used as
+	// class.
+	ClassName string
+}
+
+//
+// NewSQLReader create and return new SQL reader.
+//
+func NewSQLReader() *SQLReader {
+	return &SQLReader{}
+}
+
+//
+// Query execute the `query` with arguments `args` in database `db`, and read
+// all of its rows into dataset `ds`, in batch of BatchSize rows.
+//
+func (reader *SQLReader) Query(db *sql.DB, ds DatasetInterface, query string,
+	args ...interface{},
+) (e error) {
+	rows, e := db.Query(query, args...)
+	if e != nil {
+		return e
+	}
+
+	for e == nil {
+		_, e = reader.Read(rows, ds)
+	}
+	if e == io.EOF {
+		e = nil
+	}
+
+	errClose := rows.Close()
+	if e == nil {
+		e = errClose
+	}
+
+	return
+}
+
+//
+// Read at most BatchSize rows from `rows` and push them into dataset `ds`.
+// It will return number of rows read, or io.EOF if there is no more rows.
+//
+// To stream the query result, call Read on the same `rows` until it return
+// io.EOF, resetting or replacing the dataset after each batch.
+//
+func (reader *SQLReader) Read(rows *sql.Rows, ds DatasetInterface) (
+	n int, e error,
+) {
+	if ds.GetNColumn() <= 0 {
+		e = reader.initColumns(rows, ds)
+		if e != nil {
+			return 0, e
+		}
+	}
+
+	cols := ds.GetColumns()
+	types := ds.GetColumnsType()
+	tfs := columnsTimeFormat(ds)
+	values := make([]interface{}, len(types))
+	dest := make([]interface{}, len(types))
+	for x := range values {
+		dest[x] = &values[x]
+	}
+
+	for ; reader.BatchSize <= 0 || n < reader.BatchSize; n++ {
+		if !rows.Next() {
+			break
+		}
+
+		e = rows.Scan(dest...)
+		if e != nil {
+			return n, &ReadError{Line: n + 1, Err: e}
+		}
+
+		row := make(Row, len(types))
+
+		for x, v := range values {
+			row[x], e = newSQLRecord(v, types[x],
+				timeFormatAt(tfs, x))
+			if e == nil {
+				e = (*cols)[x].Encode(row[x])
+			}
+			if e != nil {
+				if b, ok := v.([]byte); ok {
+					v = string(b)
+				}
+				return n, &ReadError{
+					Line:   n + 1,
+					Column: x + 1,
+					Value:  fmt.Sprint(v),
+					Err:    e,
+				}
+			}
+		}
+
+		ds.PushRow(&row)
+	}
+
+	e = rows.Err()
+	if e != nil {
+		return n, e
+	}
+	if n == 0 {
+		return 0, io.EOF
+	}
+
+	return n, nil
+}
+
+//
+// initColumns create the dataset columns from query result columns. If
+// dataset is a claset, the class index is set using ClassName.
+//
+func (reader *SQLReader) initColumns(rows *sql.Rows, ds DatasetInterface) (
+	e error,
+) {
+	colTypes, e := rows.ColumnTypes()
+	if e != nil {
+		return e
+	}
+
+	types := make([]int, len(colTypes))
+	names := make([]string, len(colTypes))
+	scales := make([]int, len(colTypes))
+	classIdx := len(colTypes) - 1
+
+	for x, ct := range colTypes {
+		types[x] = sqlColumnType(ct)
+		names[x] = ct.Name()
+		if reader.ClassName != "" && names[x] == reader.ClassName {
+			classIdx = x
+		}
+		if types[x] != TDecimal {
+			continue
+		}
+		_, scale, ok := ct.DecimalSize()
+		if !ok || scale < 0 || scale > DecimalMaxScale {
+			types[x] = TReal
+			continue
+		}
+		scales[x] = int(scale)
+	}
+
+	ds.Init(ds.GetMode(), types, names)
+
+	cols := ds.GetColumns()
+	for x := range *cols {
+		(*cols)[x].Scale = scales[x]
+	}
+
+	if claset, ok := ds.(ClasetInterface); ok {
+		claset.SetClassIndex(classIdx)
+	}
+
+	return nil
+}
+
+//
+// sqlColumnType return the tabula type of SQL column, based on database type
+// name or, if its not available, the scan type.
+//
+func sqlColumnType(ct *sql.ColumnType) int {
+	name := strings.ToUpper(ct.DatabaseTypeName())
+	if x := strings.IndexByte(name, '('); x > 0 {
+		name = strings.TrimSpace(name[:x])
+	}
+	name = strings.TrimPrefix(name, "UNSIGNED ")
+
+	switch name {
+	case "INT", "INTEGER", "TINYINT", "SMALLINT", "MEDIUMINT", "BIGINT",
+		"INT2", "INT4", "INT8", "SERIAL", "SMALLSERIAL", "BIGSERIAL":
+		return TInteger
+	case "REAL", "FLOAT", "DOUBLE", "DOUBLE PRECISION", "FLOAT4",
+		"FLOAT8":
+		return TReal
+	case "NUMERIC", "DECIMAL":
+		return TDecimal
+	case "BOOL", "BOOLEAN":
+		return TBool
+	case "DATE", "DATETIME", "DATETIME2", "SMALLDATETIME", "TIMESTAMP",
+		"TIMESTAMPTZ", "TIMESTAMP WITH TIME ZONE",
+		"TIMESTAMP WITHOUT TIME ZONE", "DATETIMEOFFSET":
+		return TTime
+	case "":
+	default:
+		return TString
+	}
+
+	st := ct.ScanType()
+	if st == nil {
+		return TString
+	}
+
+	switch st {
+	case reflect.TypeOf(sql.NullInt64{}):
+		return TInteger
+	case reflect.TypeOf(sql.NullFloat64{}):
+		return TReal
+	case reflect.TypeOf(sql.NullBool{}):
+		return TBool
+	case reflect.TypeOf(time.Time{}):
+		return TTime
+	}
+
+	switch st.Kind() {
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32,
+		reflect.Int64, reflect.Uint, reflect.Uint8, reflect.Uint16,
+		reflect.Uint32, reflect.Uint64:
+		return TInteger
+	case reflect.Float32, reflect.Float64:
+		return TReal
+	case reflect.Bool:
+		return TBool
+	}
+
+	return TString
+}
+
+//
+// newSQLRecord create new record from driver value `v` with type `t`. Nil
+// value is converted to null record. Text value for TTime is parsed using
+// time format `tf`, and number is converted from Unix time in seconds.
+//
+func newSQLRecord(v interface{}, t int, tf *TimeFormat) (rec *Record,
+	e error,
+) {
+	rec = NewRecord()
+
+	switch v := v.(type) {
+	case nil:
+		rec.SetNull(t)
+	case int64:
+		switch t {
+		case TInteger:
+			rec.SetInteger(v)
+		case TReal:
+			rec.SetFloat(float64(v))
+		case TDecimal:
+			rec.SetDecimal(Decimal{Value: v})
+		case TBool:
+			rec.SetBool(v != 0)
+		case TTime:
+			rec.SetTime(time.Unix(v, 0).UTC())
+		default:
+			rec.SetString(strconv.FormatInt(v, 10))
+		}
+	case float64:
+		switch t {
+		case TInteger:
+			rec.SetInteger(int64(v))
+		case TReal:
+			rec.SetFloat(v)
+		case TDecimal:
+			e = rec.SetValue(strconv.FormatFloat(v, 'f', -1, 64), t)
+		case TBool:
+			rec.SetBool(v != 0)
+		case TTime:
+			rec.SetTime(NewRecordReal(v).Time())
+		default:
+			rec.SetString(strconv.FormatFloat(v, 'f', -1, 64))
+		}
+	case bool:
+		switch t {
+		case TInteger, TReal, TDecimal:
+			var i int64
+			if v {
+				i = 1
+			}
+			e = rec.SetValue(strconv.FormatInt(i, 10), t)
+		case TBool:
+			rec.SetBool(v)
+		default:
+			rec.SetString(strconv.FormatBool(v))
+		}
+	case time.Time:
+		switch t {
+		case TInteger:
+			rec.SetInteger(v.Unix())
+		case TReal:
+			rec.SetFloat(float64(v.UnixNano()) / 1e9)
+		case TTime:
+			rec.SetTime(v)
+		default:
+			rec.SetString(v.Format(time.RFC3339Nano))
+		}
+	case []byte:
+		e = newSQLRecordString(rec, string(v), t, tf)
+	case string:
+		e = newSQLRecordString(rec, v, t, tf)
+	default:
+		e = rec.SetValue(fmt.Sprint(v), t)
+	}
+	if e != nil {
+		return nil, e
+	}
+
+	return rec, nil
+}
+
+//
+// newSQLRecordString set the record from text value `v` with type `t`, where
+// time is parsed using time format `tf`.
+//
+func newSQLRecordString(rec *Record, v string, t int, tf *TimeFormat) error {
+	if t == TTime {
+		return rec.SetTimeValue(v, tf)
+	}
+	return rec.SetValue(v, t)
+}
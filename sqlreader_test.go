@@ -0,0 +1,264 @@
+// Copyright 2017 M. Shulhan <ms@kilabit.info>. All rights reserved.
+// Use of this source code is governed by a BSD-style license that can be found
+// in the LICENSE file.
+
+package tabula_test
+
+import (
+	"database/sql"
+	"database/sql/driver"
+	"errors"
+	"github.com/shuLhan/tabula"
+	"io"
+	"testing"
+)
+
+//
+// fakeDriver is a database driver that return the same rows for any query.
+//
+type fakeDriver struct {
+	names  []string
+	types  []string
+	values [][]driver.Value
+	// scales contain the decimal scale of each column, or -1 if its not
+	// reported.
+	scales []int64
+}
+
+type fakeConn struct {
+	drv *fakeDriver
+}
+
+type fakeStmt struct {
+	drv *fakeDriver
+}
+
+type fakeRows struct {
+	drv *fakeDriver
+	n   int
+}
+
+var fakeDrv = &fakeDriver{
+	names: []string{"id", "score", "name", "class"},
+	types: []string{"BIGINT", "DOUBLE", "VARCHAR", "INTEGER"},
+	values: [][]driver.Value{
+		{int64(1), 0.5, []byte("a"), int64(1)},
+		{int64(2), nil, "b", "0"},
+		{nil, float64(2), nil, int64(1)},
+		{int64(4), int64(3), []byte("d"), nil},
+		{int64(5), 1.25, "e", int64(0)},
+	},
+}
+
+var fakeDecimalDrv = &fakeDriver{
+	names:  []string{"id", "amount", "price"},
+	types:  []string{"BIGINT", "NUMERIC(10,2)", "DECIMAL"},
+	scales: []int64{-1, 2, -1},
+	values: [][]driver.Value{
+		{int64(1), []byte("12.5"), 1.5},
+		{int64(2), 0.25, nil},
+		{int64(3), nil, []byte("2")},
+		{int64(4), int64(7), int64(3)},
+	},
+}
+
+func init() {
+	sql.Register("tabulafake", fakeDrv)
+	sql.Register("tabulafakedecimal", fakeDecimalDrv)
+}
+
+func (drv *fakeDriver) Open(name string) (driver.Conn, error) {
+	return &fakeConn{drv: drv}, nil
+}
+
+func (conn *fakeConn) Prepare(query string) (driver.Stmt, error) {
+	return &fakeStmt{drv: conn.drv}, nil
+}
+
+func (conn *fakeConn) Close() error {
+	return nil
+}
+
+func (conn *fakeConn) Begin() (driver.Tx, error) {
+	return nil, errors.New("not supported")
+}
+
+func (stmt *fakeStmt) Close() error {
+	return nil
+}
+
+func (stmt *fakeStmt) NumInput() int {
+	return -1
+}
+
+func (stmt *fakeStmt) Exec(args []driver.Value) (driver.Result, error) {
+	return nil, errors.New("not supported")
+}
+
+func (stmt *fakeStmt) Query(args []driver.Value) (driver.Rows, error) {
+	return &fakeRows{drv: stmt.drv}, nil
+}
+
+func (rows *fakeRows) Columns() []string {
+	return rows.drv.names
+}
+
+func (rows *fakeRows) ColumnTypeDatabaseTypeName(idx int) string {
+	return rows.drv.types[idx]
+}
+
+func (rows *fakeRows) ColumnTypePrecisionScale(idx int) (
+	precision, scale int64, ok bool,
+) {
+	if rows.drv.scales == nil || rows.drv.scales[idx] < 0 {
+		return 0, 0, false
+	}
+	return 10, rows.drv.scales[idx], true
+}
+
+func (rows *fakeRows) Close() error {
+	return nil
+}
+
+func (rows *fakeRows) Next(dest []driver.Value) error {
+	if rows.n >= len(rows.drv.values) {
+		return io.EOF
+	}
+	copy(dest, rows.drv.values[rows.n])
+	rows.n++
+	return nil
+}
+
+func TestSQLReaderQuery(t *testing.T) {
+	db, e := sql.Open("tabulafake", "")
+	if e != nil {
+		t.Fatal(e)
+	}
+	defer db.Close()
+
+	reader := tabula.NewSQLReader()
+	reader.BatchSize = 2
+	reader.ClassName = "class"
+
+	claset := tabula.NewClaset(tabula.DatasetModeRows, nil, nil)
+
+	e = reader.Query(db, claset, "SELECT * FROM fake")
+	if e != nil {
+		t.Fatal(e)
+	}
+
+	expTypes := []int{
+		tabula.TInteger, tabula.TReal, tabula.TString, tabula.TInteger,
+	}
+
+	assert(t, fakeDrv.names, claset.GetColumnsName(), true)
+	assert(t, expTypes, claset.GetColumnsType(), true)
+	assert(t, 3, claset.GetClassIndex(), true)
+	assert(t, 5, claset.Len(), true)
+
+	exp := tabula.Rows{
+		{
+			tabula.NewRecordInt(1), tabula.NewRecordReal(0.5),
+			tabula.NewRecordString("a"), tabula.NewRecordInt(1),
+		}, {
+			tabula.NewRecordInt(2), tabula.NewRecordNull(tabula.TReal),
+			tabula.NewRecordString("b"), tabula.NewRecordInt(0),
+		}, {
+			tabula.NewRecordNull(tabula.TInteger),
+			tabula.NewRecordReal(2),
+			tabula.NewRecordNull(tabula.TString), tabula.NewRecordInt(1),
+		}, {
+			tabula.NewRecordInt(4), tabula.NewRecordReal(3),
+			tabula.NewRecordString("d"),
+			tabula.NewRecordNull(tabula.TInteger),
+		}, {
+			tabula.NewRecordInt(5), tabula.NewRecordReal(1.25),
+			tabula.NewRecordString("e"), tabula.NewRecordInt(0),
+		},
+	}
+
+	assert(t, exp, *claset.GetRows(), true)
+	assert(t, true, claset.GetRow(1).GetRecord(1).IsNull(), true)
+	assert(t, tabula.TString, claset.GetRow(2).GetRecord(2).Type(), true)
+}
+
+func TestSQLReaderBatch(t *testing.T) {
+	db, e := sql.Open("tabulafake", "")
+	if e != nil {
+		t.Fatal(e)
+	}
+	defer db.Close()
+
+	rows, e := db.Query("SELECT * FROM fake")
+	if e != nil {
+		t.Fatal(e)
+	}
+	defer rows.Close()
+
+	reader := &tabula.SQLReader{BatchSize: 2}
+	ds := tabula.NewDataset(tabula.DatasetModeColumns, nil, nil)
+	got := []int{}
+
+	for {
+		e = ds.Reset()
+		if e != nil {
+			t.Fatal(e)
+		}
+
+		n, e := reader.Read(rows, ds)
+		if e == io.EOF {
+			break
+		}
+		if e != nil {
+			t.Fatal(e)
+		}
+
+		assert(t, n, ds.Len(), true)
+		got = append(got, n)
+	}
+
+	assert(t, []int{2, 2, 1}, got, true)
+}
+
+func TestSQLReaderInvalid(t *testing.T) {
+	db, e := sql.Open("tabulafake", "")
+	if e != nil {
+		t.Fatal(e)
+	}
+	defer db.Close()
+
+	types := []int{
+		tabula.TInteger, tabula.TReal, tabula.TInteger, tabula.TInteger,
+	}
+	ds := tabula.NewDataset(tabula.DatasetModeRows, types, fakeDrv.names)
+
+	e = tabula.NewSQLReader().Query(db, ds, "SELECT * FROM fake")
+
+	rerr, ok := e.(*tabula.ReadError)
+	assert(t, true, ok, true)
+	assert(t, 1, rerr.Line, true)
+	assert(t, 3, rerr.Column, true)
+	assert(t, "a", rerr.Value, true)
+}
+
+func TestSQLReaderDecimal(t *testing.T) {
+	db, e := sql.Open("tabulafakedecimal", "")
+	if e != nil {
+		t.Fatal(e)
+	}
+	defer db.Close()
+
+	ds := tabula.NewDataset(tabula.DatasetModeRows, nil, nil)
+
+	e = tabula.NewSQLReader().Query(db, ds, "SELECT * FROM fake")
+	if e != nil {
+		t.Fatal(e)
+	}
+
+	assert(t, []int{tabula.TInteger, tabula.TDecimal, tabula.TReal},
+		ds.GetColumnsType(), true)
+	assert(t, 2, ds.GetColumn(1).Scale, true)
+	assert(t, "&[1 12.50 1.5]&[2 0.25 ]&[3  2]&[4 7.00 3]",
+		ds.GetDataAsRows().String(), true)
+	assert(t, true, ds.GetRow(2).GetRecord(1).IsNull(), true)
+}
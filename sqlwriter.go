@@ -0,0 +1,350 @@
+// Copyright 2017 M. Shulhan <ms@kilabit.info>. All rights reserved.
+// Use of this source code is governed by a BSD-style license that can be found
+// in the LICENSE file.
+
+package tabula
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"math"
+	"strconv"
+	"strings"
+)
+
+//
+// SQLDialect define the quote for identifier, the string escaping, and the
+// name of column type in SQL script.
+//
+type SQLDialect struct {
+	// QuoteOpen and QuoteClose enclose the table and column name.
+	QuoteOpen  string
+	QuoteClose string
+	// EscapeBackslash if its true, backslash in string is escaped with
+	// another backslash.
+	EscapeBackslash bool
+	// TypeInteger, TypeReal, TypeString, TypeBool, TypeTime, and
+	// TypeDecimal is the name of column type for TInteger, TReal,
+	// TString, TBool, TTime, and TDecimal. If TypeBool is empty,
+	// TypeInteger is used. If TypeTime is empty, TypeString is used. If
+	// TypeDecimal is empty, TypeReal is used, otherwise the precision and
+	// scale is appended to it, as in "DECIMAL(19, 2)".
+	TypeInteger string
+	TypeReal    string
+	TypeString  string
+	TypeBool    string
+	TypeTime    string
+	TypeDecimal string
+	// BoolAsInteger if its true, boolean value is written as 1 or 0,
+	// otherwise its written as TRUE or FALSE.
+	BoolAsInteger bool
+}
+
+const (
+	// sqlTimeLayout is the layout of time literal, which is accepted by
+	// all dialects.
+	sqlTimeLayout = "2006-01-02 15:04:05.999999"
+	// sqlDecimalPrecision is the precision of decimal column, which is
+	// the number of digits of unscaled decimal value.
+	sqlDecimalPrecision = 19
+)
+
+var (
+	// SQLDialectANSI is dialect for standard SQL.
+	SQLDialectANSI = &SQLDialect{
+		QuoteOpen:   `"`,
+		QuoteClose:  `"`,
+		TypeInteger: "BIGINT",
+		TypeReal:    "DOUBLE PRECISION",
+		TypeString:  "VARCHAR",
+		TypeBool:    "BOOLEAN",
+		TypeTime:    "TIMESTAMP",
+		TypeDecimal: "DECIMAL",
+	}
+	// SQLDialectMySQL is dialect for MySQL and MariaDB.
+	SQLDialectMySQL = &SQLDialect{
+		QuoteOpen:       "`",
+		QuoteClose:      "`",
+		EscapeBackslash: true,
+		TypeInteger:     "BIGINT",
+		TypeReal:        "DOUBLE",
+		TypeString:      "TEXT",
+		TypeBool:        "BOOLEAN",
+		TypeTime:        "DATETIME(6)",
+		TypeDecimal:     "DECIMAL",
+	}
+	// SQLDialectPostgreSQL is dialect for PostgreSQL.
+	SQLDialectPostgreSQL = &SQLDialect{
+		QuoteOpen:   `"`,
+		QuoteClose:  `"`,
+		TypeInteger: "BIGINT",
+		TypeReal:    "DOUBLE PRECISION",
+		TypeString:  "TEXT",
+		TypeBool:    "BOOLEAN",
+		TypeTime:    "TIMESTAMP",
+		TypeDecimal: "NUMERIC",
+	}
+	// SQLDialectSQLite is dialect for SQLite.
+	SQLDialectSQLite = &SQLDialect{
+		QuoteOpen:     `"`,
+		QuoteClose:    `"`,
+		TypeInteger:   "INTEGER",
+		TypeReal:      "REAL",
+		TypeString:    "TEXT",
+		TypeBool:      "INTEGER",
+		TypeTime:      "TEXT",
+		TypeDecimal:   "NUMERIC",
+		BoolAsInteger: true,
+	}
+	// SQLDialectSQLServer is dialect for Microsoft SQL Server.
+	SQLDialectSQLServer = &SQLDialect{
+		QuoteOpen:     "[",
+		QuoteClose:    "]",
+		TypeInteger:   "BIGINT",
+		TypeReal:      "FLOAT",
+		TypeString:    "NVARCHAR(MAX)",
+		TypeBool:      "BIT",
+		TypeTime:      "DATETIME2",
+		TypeDecimal:   "DECIMAL",
+		BoolAsInteger: true,
+	}
+)
+
+//
+// Quote enclose the identifier `name` with dialect quote. Closing quote
+// inside the name is doubled.
+//
+func (dialect *SQLDialect) Quote(name string) string {
+	name = strings.Replace(name, dialect.QuoteClose,
+		dialect.QuoteClose+dialect.QuoteClose, -1)
+	return dialect.QuoteOpen + name + dialect.QuoteClose
+}
+
+//
+// QuoteString enclose `s` with single quote as string literal. Single quote
+// inside `s` is doubled, and backslash is escaped if EscapeBackslash is true.
+//
+func (dialect *SQLDialect) QuoteString(s string) string {
+	if dialect.EscapeBackslash {
+		s = strings.Replace(s, `\`, `\\`, -1)
+	}
+	return "'" + strings.Replace(s, "'", "''", -1) + "'"
+}
+
+//
+// ColumnType return the name of column type for tabula type `tipe`. TDecimal
+// is returned with zero scale, see DecimalType.
+//
+func (dialect *SQLDialect) ColumnType(tipe int) string {
+	switch tipe {
+	case TDecimal:
+		return dialect.DecimalType(0)
+	case TInteger:
+		return dialect.TypeInteger
+	case TReal:
+		return dialect.TypeReal
+	case TBool:
+		if dialect.TypeBool == "" {
+			return dialect.TypeInteger
+		}
+		return dialect.TypeBool
+	case TTime:
+		if dialect.TypeTime != "" {
+			return dialect.TypeTime
+		}
+	}
+	return dialect.TypeString
+}
+
+//
+// DecimalType return the name of column type for TDecimal with `scale`
+// digits after decimal point.
+//
+func (dialect *SQLDialect) DecimalType(scale int) string {
+	if dialect.TypeDecimal == "" {
+		return dialect.TypeReal
+	}
+	return fmt.Sprintf("%s(%d, %d)", dialect.TypeDecimal,
+		sqlDecimalPrecision, scale)
+}
+
+//
+// SQLWriter write dataset as SQL script, which contain CREATE TABLE
+// statement and INSERT statements, one for each batch of rows.
+//
+// Missing value, and real value which is not finite, is written as NULL.
+// Time is written as string literal in UTC with microseconds precision.
+// Decimal is written as number with all digits after decimal point, in
+// column with the same scale.
+//
+type SQLWriter struct {
+	// Dialect used to quote the identifier and string, and to name the
+	// column type. Default to SQLDialectANSI.
+	Dialect *SQLDialect
+	// Table is the name of table. Default to "tabula".
+	Table string
+	// NoCreateTable if its true, the CREATE TABLE statement is not
+	// written.
+	NoCreateTable bool
+	// BatchSize maximum number of rows in each INSERT statement. If its
+	// zero or negative, all rows is written in single INSERT statement.
+	BatchSize int
+}
+
+//
+// NewSQLWriter create and return new SQL writer with ANSI dialect and 100
+// rows per INSERT statement.
+//
+func NewSQLWriter() *SQLWriter {
+	return &SQLWriter{
+		Dialect:   SQLDialectANSI,
+		Table:     "tabula",
+		BatchSize: 100,
+	}
+}
+
+//
+// WriteFile create or truncate `file` and write dataset `ds` into it.
+//
+func (writer *SQLWriter) WriteFile(file string, ds DatasetInterface) (
+	e error,
+) {
+	f, e := CreateFile(file)
+	if e != nil {
+		return e
+	}
+
+	e = writer.Write(f, ds)
+
+	errClose := f.Close()
+	if e == nil {
+		e = errClose
+	}
+
+	return
+}
+
+//
+// Write the SQL script of dataset `ds` into `w`. Dataset is read based on
+// its mode without transposing.
+//
+func (writer *SQLWriter) Write(w io.Writer, ds DatasetInterface) (e error) {
+	bw := bufio.NewWriter(w)
+
+	dialect := writer.Dialect
+	if dialect == nil {
+		dialect = SQLDialectANSI
+	}
+	table := writer.Table
+	if table == "" {
+		table = "tabula"
+	}
+	table = dialect.Quote(table)
+
+	cols := ds.GetColumns()
+	names := ds.GetColumnsName()
+	types := ds.GetColumnsType()
+	for x := range names {
+		names[x] = dialect.Quote(names[x])
+	}
+
+	if !writer.NoCreateTable {
+		_, e = bw.WriteString("CREATE TABLE " + table + " (\n")
+		if e != nil {
+			return
+		}
+		for x := range names {
+			tipe := dialect.ColumnType(types[x])
+			if types[x] == TDecimal {
+				scale := (*cols)[x].decimalScale()
+				tipe = dialect.DecimalType(scale)
+			}
+			line := "\t" + names[x] + " " + tipe
+			if x < len(names)-1 {
+				line += ","
+			}
+			_, e = bw.WriteString(line + "\n")
+			if e != nil {
+				return
+			}
+		}
+		_, e = bw.WriteString(");\n")
+		if e != nil {
+			return
+		}
+	}
+
+	insert := "INSERT INTO " + table + " (" + strings.Join(names, ", ") +
+		") VALUES\n"
+
+	var line []byte
+	nrow := ds.Len()
+	n := 0
+
+	for r := 0; r < nrow; r++ {
+		line = line[:0]
+		if n == 0 {
+			line = append(line, insert...)
+		}
+
+		line = append(line, "\t("...)
+		for x := range names {
+			if x > 0 {
+				line = append(line, ", "...)
+			}
+			line = appendSQLValue(line, dialect, getRecordAt(ds, r, x))
+		}
+		line = append(line, ')')
+
+		n++
+		if n == writer.BatchSize || r == nrow-1 {
+			line = append(line, ";\n"...)
+			n = 0
+		} else {
+			line = append(line, ",\n"...)
+		}
+
+		_, e = bw.Write(line)
+		if e != nil {
+			return
+		}
+	}
+
+	return bw.Flush()
+}
+
+//
+// appendSQLValue convert record into SQL literal and append it to `line`.
+//
+func appendSQLValue(line []byte, dialect *SQLDialect, rec *Record) []byte {
+	if rec == nil || rec.IsNil() || rec.IsMissingValue() {
+		return append(line, "NULL"...)
+	}
+
+	switch rec.Type() {
+	case TInteger:
+		return strconv.AppendInt(line, rec.Integer(), 10)
+	case TReal:
+		f := rec.Float()
+		if math.IsInf(f, 0) || math.IsNaN(f) {
+			return append(line, "NULL"...)
+		}
+		return strconv.AppendFloat(line, f, 'g', -1, 64)
+	case TBool:
+		if dialect.BoolAsInteger {
+			return strconv.AppendInt(line, rec.Integer(), 10)
+		}
+		if rec.Bool() {
+			return append(line, "TRUE"...)
+		}
+		return append(line, "FALSE"...)
+	case TTime:
+		t := rec.Time().UTC().Format(sqlTimeLayout)
+		return append(line, dialect.QuoteString(t)...)
+	case TDecimal:
+		return append(line, rec.String()...)
+	}
+
+	return append(line, dialect.QuoteString(rec.String())...)
+}
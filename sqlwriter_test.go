@@ -0,0 +1,127 @@
+// Copyright 2017 M. Shulhan <ms@kilabit.info>. All rights reserved.
+// Use of this source code is governed by a BSD-style license that can be found
+// in the LICENSE file.
+
+package tabula_test
+
+import (
+	"bytes"
+	"github.com/shuLhan/tabula"
+	"math"
+	"testing"
+)
+
+func createSQLDataset() *tabula.Dataset {
+	ds := tabula.NewDataset(tabula.DatasetModeRows, datasetTypes,
+		[]string{"id", "score", `na"me`})
+
+	rows := []tabula.Row{{
+		tabula.NewRecordInt(1),
+		tabula.NewRecordReal(0.5),
+		tabula.NewRecordString("it's"),
+	}, {
+		tabula.NewRecordInt(math.MinInt64),
+		tabula.NewRecordReal(math.NaN()),
+		tabula.NewRecordString(`a\b`),
+	}, {
+		tabula.NewRecordInt(3),
+		tabula.NewRecordReal(math.Inf(-1)),
+		tabula.NewRecordString("?"),
+	}}
+
+	for x := range rows {
+		ds.PushRow(&rows[x])
+	}
+
+	return ds
+}
+
+func TestSQLWriter(t *testing.T) {
+	ds := createSQLDataset()
+
+	exp := `CREATE TABLE "tabula" (
+	"id" BIGINT,
+	"score" DOUBLE PRECISION,
+	"na""me" VARCHAR
+);
+INSERT INTO "tabula" ("id", "score", "na""me") VALUES
+	(1, 0.5, 'it''s'),
+	(NULL, NULL, 'a\b'),
+	(3, NULL, NULL);
+`
+
+	var out bytes.Buffer
+
+	e := tabula.NewSQLWriter().Write(&out, ds)
+	if e != nil {
+		t.Fatal(e)
+	}
+
+	assert(t, exp, out.String(), true)
+}
+
+func TestSQLWriterDialect(t *testing.T) {
+	ds := createSQLDataset()
+
+	exp := "INSERT INTO `data` (`id`, `score`, `na\"me`) VALUES\n" +
+		"\t(1, 0.5, 'it''s'),\n" +
+		"\t(NULL, NULL, 'a\\\\b');\n" +
+		"INSERT INTO `data` (`id`, `score`, `na\"me`) VALUES\n" +
+		"\t(3, NULL, NULL);\n"
+
+	writer := &tabula.SQLWriter{
+		Dialect:       tabula.SQLDialectMySQL,
+		Table:         "data",
+		NoCreateTable: true,
+		BatchSize:     2,
+	}
+
+	var out bytes.Buffer
+
+	e := writer.Write(&out, ds)
+	if e != nil {
+		t.Fatal(e)
+	}
+
+	assert(t, exp, out.String(), true)
+
+	assert(t, "[a]]b]", tabula.SQLDialectSQLServer.Quote("a]b"), true)
+	assert(t, "INTEGER", tabula.SQLDialectSQLite.ColumnType(tabula.TInteger),
+		true)
+}
+
+func TestSQLWriterDecimal(t *testing.T) {
+	ds := tabula.NewDataset(tabula.DatasetModeRows,
+		[]int{tabula.TDecimal}, []string{"amount"})
+	ds.Columns[0].Scale = 2
+
+	missing := tabula.NewRecord()
+	missing.SetMissingValue(tabula.TDecimal)
+
+	ds.PushRow(&tabula.Row{
+		tabula.NewRecordDecimal(tabula.Decimal{Value: -1250, Scale: 2}),
+	})
+	ds.PushRow(&tabula.Row{missing})
+
+	exp := `CREATE TABLE "tabula" (
+	"amount" NUMERIC(19, 2)
+);
+INSERT INTO "tabula" ("amount") VALUES
+	(-12.50),
+	(NULL);
+`
+
+	writer := tabula.NewSQLWriter()
+	writer.Dialect = tabula.SQLDialectPostgreSQL
+
+	var out bytes.Buffer
+
+	e := writer.Write(&out, ds)
+	if e != nil {
+		t.Fatal(e)
+	}
+
+	assert(t, exp, out.String(), true)
+	assert(t, "DECIMAL(19, 0)",
+		tabula.SQLDialectANSI.ColumnType(tabula.TDecimal), true)
+}
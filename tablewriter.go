@@ -0,0 +1,339 @@
+// Copyright 2017 M. Shulhan <ms@kilabit.info>. All rights reserved.
+// Use of this source code is governed by a BSD-style license that can be found
+// in the LICENSE file.
+
+package tabula
+
+import (
+	"bufio"
+	"bytes"
+	"io"
+	"strconv"
+	"strings"
+	"unicode/utf8"
+)
+
+const (
+	tableEllipsis  = "..."
+	tableSeparator = " | "
+)
+
+var tableEscaper = strings.NewReplacer("\n", `\n`, "\r", `\r`, "\t", `\t`)
+
+//
+// TableWriter render dataset as aligned text table, for printing in
+// terminal. The first line contain column names, followed by column types,
+// and the records. Integer, real, and decimal columns is aligned to the
+// right.
+//
+// For example,
+//
+//	 id | score | name
+//	int |  real | string
+//	----+-------+-------
+//	  1 |   0.5 | alpha
+//	... |   ... | ...
+//	100 |    -2 | omega
+//	[100 rows x 3 columns]
+//
+type TableWriter struct {
+	// Head and Tail is the number of rows printed from the beginning
+	// and the end of dataset, if dataset has more rows than Head+Tail.
+	// The rows in the middle is replaced by "...". If both is zero, all
+	// rows is printed.
+	Head int
+	Tail int
+	// MaxCellWidth is the maximum number of characters in cell. Longer
+	// value is truncated and ended with "...". If its zero, the value is
+	// not truncated.
+	MaxCellWidth int
+	// MaxWidth is the maximum number of characters in line. Columns that
+	// does not fit is replaced by "..." column. If its zero, all columns
+	// is printed.
+	MaxWidth int
+	// NoTypes if its true, the column types is not printed.
+	NoTypes bool
+	// MissingValue is the text printed for missing value.
+	MissingValue string
+}
+
+//
+// NewTableWriter create and return new table writer that print the first
+// and last 5 rows, with maximum 24 characters per cell and 120 characters
+// per line.
+//
+func NewTableWriter() *TableWriter {
+	return &TableWriter{
+		Head:         5,
+		Tail:         5,
+		MaxCellWidth: 24,
+		MaxWidth:     120,
+		MissingValue: DefaultMissingValue,
+	}
+}
+
+//
+// String return the text table of dataset `ds`.
+//
+func (writer *TableWriter) String(ds DatasetInterface) string {
+	var buf bytes.Buffer
+	_ = writer.Write(&buf, ds)
+	return buf.String()
+}
+
+//
+// Write the text table of dataset `ds` into `w`. Dataset is read based on
+// its mode without transposing.
+//
+func (writer *TableWriter) Write(w io.Writer, ds DatasetInterface) (e error) {
+	names := ds.GetColumnsName()
+	types := ds.GetColumnsType()
+	ncol := len(names)
+	nrow := ds.Len()
+
+	// Collect the cells, starting with header.
+	cells := [][]string{make([]string, ncol)}
+	for x, name := range names {
+		cells[0][x] = writer.truncate(name)
+	}
+	if !writer.NoTypes {
+		row := make([]string, ncol)
+		for x, t := range types {
+			row[x] = tableTypeName(t)
+		}
+		cells = append(cells, row)
+	}
+	nheader := len(cells)
+
+	for _, r := range writer.rowsIndex(nrow) {
+		row := make([]string, ncol)
+		for x := range row {
+			if r < 0 {
+				row[x] = tableEllipsis
+				continue
+			}
+			row[x] = writer.format(getRecordAt(ds, r, x))
+		}
+		cells = append(cells, row)
+	}
+
+	widths := make([]int, ncol)
+	for _, row := range cells {
+		for x, v := range row {
+			n := utf8.RuneCountInString(v)
+			if n > widths[x] {
+				widths[x] = n
+			}
+		}
+	}
+
+	nshow := writer.visibleColumns(widths)
+
+	bw := bufio.NewWriter(w)
+
+	for y, row := range cells {
+		if y == nheader {
+			e = writer.writeLine(bw, widths[:nshow], nshow < ncol)
+			if e != nil {
+				return
+			}
+		}
+
+		var line []byte
+		for x := 0; x < nshow; x++ {
+			if x > 0 {
+				line = append(line, tableSeparator...)
+			}
+			right := types[x] == TInteger || types[x] == TReal ||
+				types[x] == TDecimal
+			line = tableAppendCell(line, row[x], widths[x], right)
+		}
+		if nshow < ncol {
+			if nshow > 0 {
+				line = append(line, tableSeparator...)
+			}
+			line = append(line, tableEllipsis...)
+		}
+		line = bytes.TrimRight(line, " ")
+		line = append(line, '\n')
+
+		_, e = bw.Write(line)
+		if e != nil {
+			return
+		}
+	}
+
+	if len(cells) == nheader {
+		e = writer.writeLine(bw, widths[:nshow], nshow < ncol)
+		if e != nil {
+			return
+		}
+	}
+
+	_, e = bw.WriteString("[" + strconv.Itoa(nrow) + " rows x " +
+		strconv.Itoa(ncol) + " columns]\n")
+	if e != nil {
+		return
+	}
+
+	return bw.Flush()
+}
+
+//
+// rowsIndex return the index of rows to be printed, where -1 is the
+// position of elided rows.
+//
+func (writer *TableWriter) rowsIndex(nrow int) (idx []int) {
+	head, tail := writer.Head, writer.Tail
+	if head < 0 {
+		head = 0
+	}
+	if tail < 0 {
+		tail = 0
+	}
+
+	if head+tail == 0 || nrow <= head+tail {
+		for r := 0; r < nrow; r++ {
+			idx = append(idx, r)
+		}
+		return idx
+	}
+
+	for r := 0; r < head; r++ {
+		idx = append(idx, r)
+	}
+	idx = append(idx, -1)
+	for r := nrow - tail; r < nrow; r++ {
+		idx = append(idx, r)
+	}
+
+	return idx
+}
+
+//
+// visibleColumns return the number of columns, from the first column, that
+// fit in MaxWidth.
+//
+func (writer *TableWriter) visibleColumns(widths []int) int {
+	if writer.MaxWidth <= 0 {
+		return len(widths)
+	}
+
+	total := 0
+	for x, width := range widths {
+		if x > 0 {
+			total += len(tableSeparator)
+		}
+		total += width
+	}
+	if total <= writer.MaxWidth {
+		return len(widths)
+	}
+
+	// Reserve the space for "..." column.
+	total = len(tableEllipsis)
+	for x, width := range widths {
+		total += width + len(tableSeparator)
+		if total > writer.MaxWidth {
+			return x
+		}
+	}
+
+	return len(widths)
+}
+
+//
+// writeLine write the line that separate the header and records.
+//
+func (writer *TableWriter) writeLine(bw *bufio.Writer, widths []int,
+	elided bool,
+) (e error) {
+	var line []byte
+
+	for x, width := range widths {
+		if x > 0 {
+			line = append(line, "-+-"...)
+		}
+		line = append(line, strings.Repeat("-", width)...)
+	}
+	if elided {
+		if len(widths) > 0 {
+			line = append(line, "-+-"...)
+		}
+		line = append(line, strings.Repeat("-", len(tableEllipsis))...)
+	}
+	line = append(line, '\n')
+
+	_, e = bw.Write(line)
+
+	return
+}
+
+//
+// format convert the record into text, replacing the control characters
+// with escaped text.
+//
+func (writer *TableWriter) format(rec *Record) string {
+	if rec == nil || rec.IsNil() {
+		return ""
+	}
+	if rec.IsMissingValue() {
+		return writer.MissingValue
+	}
+
+	v := rec.String()
+	if rec.Type() == TString {
+		v = tableEscaper.Replace(v)
+	}
+
+	return writer.truncate(v)
+}
+
+//
+// truncate the value `v` if its longer than MaxCellWidth.
+//
+func (writer *TableWriter) truncate(v string) string {
+	max := writer.MaxCellWidth
+	if max <= 0 || utf8.RuneCountInString(v) <= max {
+		return v
+	}
+
+	runes := []rune(v)
+	if max <= len(tableEllipsis) {
+		return string(runes[:max])
+	}
+
+	return string(runes[:max-len(tableEllipsis)]) + tableEllipsis
+}
+
+//
+// tableAppendCell append the value `v` padded to `width` into `line`.
+//
+func tableAppendCell(line []byte, v string, width int, right bool) []byte {
+	pad := strings.Repeat(" ", width-utf8.RuneCountInString(v))
+	if right {
+		return append(append(line, pad...), v...)
+	}
+	return append(append(line, v...), pad...)
+}
+
+//
+// tableTypeName return the name of column type.
+//
+func tableTypeName(t int) string {
+	switch t {
+	case TString:
+		return "string"
+	case TInteger:
+		return "int"
+	case TReal:
+		return "real"
+	case TBool:
+		return "bool"
+	case TTime:
+		return "time"
+	case TDecimal:
+		return "decimal"
+	}
+	return "undefined"
+}
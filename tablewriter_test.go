@@ -0,0 +1,90 @@
+// Copyright 2017 M. Shulhan <ms@kilabit.info>. All rights reserved.
+// Use of this source code is governed by a BSD-style license that can be found
+// in the LICENSE file.
+
+package tabula_test
+
+import (
+	"fmt"
+	"github.com/shuLhan/tabula"
+	"math"
+	"testing"
+)
+
+func createTableDataset(nrow int) *tabula.Dataset {
+	ds := tabula.NewDataset(tabula.DatasetModeRows, datasetTypes,
+		[]string{"id", "score", "name"})
+
+	for x := 0; x < nrow; x++ {
+		row := tabula.Row{
+			tabula.NewRecordInt(int64(x + 1)),
+			tabula.NewRecordReal(float64(x) * 12.5),
+			tabula.NewRecordString(fmt.Sprintf("name %d", x+1)),
+		}
+		ds.PushRow(&row)
+	}
+
+	return ds
+}
+
+func TestTableWriter(t *testing.T) {
+	ds := createTableDataset(3)
+
+	row := tabula.Row{
+		tabula.NewRecordInt(math.MinInt64),
+		tabula.NewRecordReal(-1.5),
+		tabula.NewRecordString("very long name\tthat will be truncated"),
+	}
+	ds.PushRow(&row)
+
+	exp := ` id | score | name
+int |  real | string
+----+-------+-------------------------
+  1 |     0 | name 1
+  2 |  12.5 | name 2
+  3 |    25 | name 3
+  ? |  -1.5 | very long name\tthat ...
+[4 rows x 3 columns]
+`
+
+	got := tabula.NewTableWriter().String(ds)
+
+	assert(t, exp, got, true)
+}
+
+func TestTableWriterElision(t *testing.T) {
+	ds := createTableDataset(100)
+
+	writer := tabula.NewTableWriter()
+	writer.Head = 2
+	writer.Tail = 1
+	writer.NoTypes = true
+	writer.MaxWidth = 18
+
+	exp := ` id |  score | ...
+----+--------+----
+  1 |      0 | ...
+  2 |   12.5 | ...
+... |    ... | ...
+100 | 1237.5 | ...
+[100 rows x 3 columns]
+`
+
+	got := writer.String(ds)
+
+	assert(t, exp, got, true)
+}
+
+func TestTableWriterEmpty(t *testing.T) {
+	ds := createTableDataset(0)
+
+	exp := ` id | score | name
+int |  real | string
+----+-------+-------
+[0 rows x 3 columns]
+`
+
+	got := tabula.NewTableWriter().String(ds)
+
+	assert(t, exp, got, true)
+}
@@ -0,0 +1,319 @@
+// Copyright 2017 M. Shulhan <ms@kilabit.info>. All rights reserved.
+// Use of this source code is governed by a BSD-style license that can be found
+// in the LICENSE file.
+
+package tabula
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"strings"
+	"time"
+)
+
+var (
+	// ErrInvalidTime returned when string can not be converted into time
+	// using any of the layouts.
+	ErrInvalidTime = errors.New("tabula: invalid time value")
+)
+
+//
+// DefaultTimeLayouts contain the layouts that is used to parse TTime value
+// when the column does not define its own layouts. The first layout is used
+// to format the value.
+//
+var DefaultTimeLayouts = []string{
+	time.RFC3339Nano,
+	"2006-01-02 15:04:05.999999999Z07:00",
+	"2006-01-02T15:04:05.999999999",
+	"2006-01-02 15:04:05.999999999",
+	"2006-01-02",
+}
+
+//
+// TimeFormat define the layouts and time zone of TTime value in column.
+//
+// For example, to read "31/12/2017 23:59" in Jakarta time, set Layouts to
+// {"02/01/2006 15:04"} and Location to time.LoadLocation("Asia/Jakarta").
+//
+type TimeFormat struct {
+	// Layouts contain the layouts, as in time.Parse, that is tried in
+	// order to parse the value. The first layout is used to format the
+	// value. If its empty, DefaultTimeLayouts is used.
+	Layouts []string
+	// Location is the time zone for value that does not have time zone
+	// information, and the time zone where the value is formatted. If its
+	// nil, UTC is used for parsing and the value is formatted in its own
+	// time zone.
+	Location *time.Location
+}
+
+//
+// Parse convert string `v` into time using the first layout that match.
+// Surrounding spaces is ignored. If none of layouts match, it will return
+// ErrInvalidTime.
+//
+func (tf *TimeFormat) Parse(v string) (t time.Time, e error) {
+	layouts := DefaultTimeLayouts
+	loc := time.UTC
+
+	if tf != nil {
+		if len(tf.Layouts) > 0 {
+			layouts = tf.Layouts
+		}
+		if tf.Location != nil {
+			loc = tf.Location
+		}
+	}
+
+	v = strings.TrimSpace(v)
+
+	for _, layout := range layouts {
+		t, e = time.ParseInLocation(layout, v, loc)
+		if e == nil {
+			return t, nil
+		}
+	}
+
+	return time.Time{}, ErrInvalidTime
+}
+
+//
+// Format convert time `t` into string using the first layout.
+//
+func (tf *TimeFormat) Format(t time.Time) string {
+	if tf == nil {
+		return t.Format(DefaultTimeLayouts[0])
+	}
+	if tf.Location != nil {
+		t = t.In(tf.Location)
+	}
+	if len(tf.Layouts) == 0 {
+		return t.Format(DefaultTimeLayouts[0])
+	}
+	return t.Format(tf.Layouts[0])
+}
+
+//
+// formatRecordTime convert record into string, where TTime value is
+// formatted using `tf`.
+//
+func formatRecordTime(rec *Record, tf *TimeFormat) string {
+	if tf != nil && rec.Type() == TTime {
+		return tf.Format(rec.Time())
+	}
+	return rec.String()
+}
+
+//
+// columnsTimeFormat return the time format of each column in dataset.
+//
+func columnsTimeFormat(ds DatasetInterface) (tfs []*TimeFormat) {
+	cols := ds.GetColumns()
+	tfs = make([]*TimeFormat, len(*cols))
+	for x := range *cols {
+		tfs[x] = (*cols)[x].TimeFormat
+	}
+	return tfs
+}
+
+//
+// timeFormatAt return the time format at index `x`, or nil if its out of
+// range.
+//
+func timeFormatAt(tfs []*TimeFormat, x int) *TimeFormat {
+	if x < 0 || x >= len(tfs) {
+		return nil
+	}
+	return tfs[x]
+}
+
+//
+// timeFormatJSON is the JSON representation of time format, where location
+// is saved by its name.
+//
+type timeFormatJSON struct {
+	Layouts  []string `json:",omitempty"`
+	Location string   `json:",omitempty"`
+}
+
+//
+// MarshalJSON convert time format into JSON object, with location saved by
+// its name.
+//
+func (tf *TimeFormat) MarshalJSON() ([]byte, error) {
+	out := timeFormatJSON{
+		Layouts:  tf.Layouts,
+		Location: timeLocationName(tf.Location),
+	}
+	return json.Marshal(&out)
+}
+
+//
+// UnmarshalJSON set the time format from JSON object. Location is loaded
+// using time.LoadLocation.
+//
+func (tf *TimeFormat) UnmarshalJSON(b []byte) (e error) {
+	var in timeFormatJSON
+
+	e = json.Unmarshal(b, &in)
+	if e != nil {
+		return e
+	}
+
+	tf.Layouts = in.Layouts
+	tf.Location, e = loadTimeLocation(in.Location)
+
+	return e
+}
+
+//
+// appendMsgpack convert time format into MessagePack map and append it to
+// `b`.
+//
+func (tf *TimeFormat) appendMsgpack(b []byte) []byte {
+	b = appendMsgpackMap(b, 2)
+	b = appendMsgpackString(b, "Layouts")
+	b = appendMsgpackStrings(b, tf.Layouts)
+	b = appendMsgpackString(b, "Location")
+	return appendMsgpackString(b, timeLocationName(tf.Location))
+}
+
+//
+// readTimeFormatMsgpack read time format from MessagePack map in `br`. Nil
+// is read as nil time format, and unknown key is ignored.
+//
+func readTimeFormatMsgpack(br *bytes.Reader) (tf *TimeFormat, e error) {
+	n, e := readMsgpackMap(br)
+	if e != nil || n < 0 {
+		return nil, e
+	}
+
+	tf = &TimeFormat{}
+
+	for ; n > 0; n-- {
+		key, e := readMsgpackString(br)
+		if e != nil {
+			return nil, e
+		}
+
+		switch key {
+		case "Layouts":
+			tf.Layouts, e = readMsgpackStrings(br)
+		case "Location":
+			var loc string
+
+			loc, e = readMsgpackString(br)
+			if e == nil {
+				tf.Location, e = loadTimeLocation(loc)
+			}
+		default:
+			e = skipMsgpack(br)
+		}
+		if e != nil {
+			return nil, e
+		}
+	}
+
+	return tf, nil
+}
+
+//
+// appendTimeFormatBinary convert time format `tf` into binary and append it
+// to `b`. Nil time format is saved as zero byte, otherwise as one byte
+// followed by the layouts and location name.
+//
+func appendTimeFormatBinary(b []byte, tf *TimeFormat) []byte {
+	if tf == nil {
+		return append(b, 0)
+	}
+	b = append(b, 1)
+	b = appendBinaryStrings(b, tf.Layouts)
+	return appendBinaryString(b, timeLocationName(tf.Location))
+}
+
+//
+// readTimeFormatBinary read time format that is encoded by
+// appendTimeFormatBinary from `br`. If its failed, it will return
+// `errInvalid`.
+//
+func readTimeFormatBinary(br *bytes.Reader, errInvalid error) (
+	tf *TimeFormat, e error,
+) {
+	c, e := br.ReadByte()
+	if e != nil || c > 1 {
+		return nil, errInvalid
+	}
+	if c == 0 {
+		return nil, nil
+	}
+
+	tf = &TimeFormat{}
+
+	tf.Layouts, e = readBinaryStrings(br, errInvalid)
+	if e != nil {
+		return nil, e
+	}
+
+	name, e := readBinaryString(br, errInvalid)
+	if e != nil {
+		return nil, e
+	}
+
+	tf.Location, e = loadTimeLocation(name)
+	if e != nil {
+		return nil, errInvalid
+	}
+
+	return tf, nil
+}
+
+//
+// timeLocationName return the name of location that can be loaded back by
+// loadTimeLocation. Location that is not in time zone database, or has
+// different offset than the location with the same name in database, for
+// example the one created by time.FixedZone, is saved as its offset from
+// UTC, as in "+07:00".
+//
+func timeLocationName(loc *time.Location) string {
+	if loc == nil {
+		return ""
+	}
+
+	now := time.Now()
+	name := loc.String()
+
+	if name != "" {
+		named, e := time.LoadLocation(name)
+		if e == nil {
+			_, offset := now.In(loc).Zone()
+			_, namedOffset := now.In(named).Zone()
+			if offset == namedOffset {
+				return name
+			}
+		}
+	}
+
+	return now.In(loc).Format("-07:00")
+}
+
+//
+// loadTimeLocation return the location with `name`. Empty name is returned
+// as nil location, and offset from UTC, as in "+07:00", is returned as fixed
+// time zone.
+//
+func loadTimeLocation(name string) (*time.Location, error) {
+	if name == "" {
+		return nil, nil
+	}
+	if name[0] == '+' || name[0] == '-' {
+		t, e := time.Parse("-07:00", name)
+		if e != nil {
+			return nil, e
+		}
+		_, offset := t.Zone()
+		return time.FixedZone(name, offset), nil
+	}
+	return time.LoadLocation(name)
+}
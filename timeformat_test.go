@@ -0,0 +1,164 @@
+// Copyright 2017 M. Shulhan <ms@kilabit.info>. All rights reserved.
+// Use of this source code is governed by a BSD-style license that can be found
+// in the LICENSE file.
+
+package tabula_test
+
+import (
+	"bytes"
+	"encoding/gob"
+	"encoding/json"
+	"github.com/shuLhan/tabula"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestTimeFormatParse(t *testing.T) {
+	jakarta := time.FixedZone("WIB", 7*3600)
+
+	cases := []struct {
+		tf  *tabula.TimeFormat
+		in  string
+		exp time.Time
+		err error
+	}{{
+		in:  "2017-12-31T23:59:58.5+07:00",
+		exp: time.Date(2017, 12, 31, 23, 59, 58, 5e8, jakarta),
+	}, {
+		in:  " 2017-12-31 23:59 ",
+		err: tabula.ErrInvalidTime,
+	}, {
+		in:  "2017-12-31",
+		exp: time.Date(2017, 12, 31, 0, 0, 0, 0, time.UTC),
+	}, {
+		tf: &tabula.TimeFormat{
+			Layouts:  []string{"02/01/2006 15:04", "02/01/2006"},
+			Location: jakarta,
+		},
+		in:  " 31/12/2017 ",
+		exp: time.Date(2017, 12, 31, 0, 0, 0, 0, jakarta),
+	}}
+
+	for _, c := range cases {
+		got, e := c.tf.Parse(c.in)
+
+		assert(t, c.err, e, true)
+		if e != nil {
+			continue
+		}
+
+		assert(t, true, c.exp.Equal(got), true)
+	}
+}
+
+func TestDSVTimeFormat(t *testing.T) {
+	input := "id,at\n1,31/12/2017 23:59\n2,?\n"
+	tf := &tabula.TimeFormat{
+		Layouts:  []string{"02/01/2006 15:04"},
+		Location: time.FixedZone("WIB", 7*3600),
+	}
+
+	ds := tabula.NewDataset(tabula.DatasetModeRows,
+		[]int{tabula.TInteger, tabula.TTime}, nil)
+	ds.Columns[1].TimeFormat = tf
+
+	reader := tabula.NewDSVReader()
+	reader.Header = true
+
+	e := reader.Read(strings.NewReader(input), ds)
+	if e != nil {
+		t.Fatal(e)
+	}
+
+	assert(t, "&[1 2017-12-31T23:59:00+07:00]&[2 ?]",
+		ds.GetDataAsRows().String(), true)
+
+	var out bytes.Buffer
+
+	e = tabula.NewDSVWriter().Write(&out, ds)
+	if e != nil {
+		t.Fatal(e)
+	}
+
+	assert(t, "1,\"31/12/2017 23:59\"\n2,?\n", out.String(), true)
+}
+
+func TestTimeFormatLocation(t *testing.T) {
+	cases := []struct {
+		loc *time.Location
+		exp string
+	}{{
+		loc: time.UTC,
+		exp: `{"Location":"UTC"}`,
+	}, {
+		loc: time.FixedZone("", 7*3600),
+		exp: `{"Location":"+07:00"}`,
+	}, {
+		loc: time.FixedZone("UTC", -5*3600),
+		exp: `{"Location":"-05:00"}`,
+	}}
+
+	at := time.Date(2017, 12, 31, 23, 59, 0, 0, time.UTC)
+
+	for _, c := range cases {
+		b, e := json.Marshal(&tabula.TimeFormat{Location: c.loc})
+		if e != nil {
+			t.Fatal(e)
+		}
+
+		assert(t, c.exp, string(b), true)
+
+		got := &tabula.TimeFormat{}
+
+		e = json.Unmarshal(b, got)
+		if e != nil {
+			t.Fatal(e)
+		}
+
+		_, exp := at.In(c.loc).Zone()
+		_, offset := at.In(got.Location).Zone()
+
+		assert(t, exp, offset, true)
+	}
+}
+
+func TestTimeFormatGob(t *testing.T) {
+	ds := tabula.NewDataset(tabula.DatasetModeRows,
+		[]int{tabula.TTime, tabula.TTime}, []string{"utc", "local"})
+	ds.Columns[0].TimeFormat = &tabula.TimeFormat{
+		Layouts:  []string{"02/01/2006 15:04"},
+		Location: time.UTC,
+	}
+	ds.Columns[1].TimeFormat = &tabula.TimeFormat{
+		Location: time.FixedZone("", 7*3600),
+	}
+
+	at := time.Date(2017, 12, 31, 23, 59, 0, 0, time.UTC)
+	ds.PushRow(&tabula.Row{
+		tabula.NewRecordTime(at),
+		tabula.NewRecordTime(at),
+	})
+
+	var buf bytes.Buffer
+
+	e := gob.NewEncoder(&buf).Encode(ds)
+	if e != nil {
+		t.Fatal(e)
+	}
+
+	got := &tabula.Dataset{}
+
+	e = gob.NewDecoder(&buf).Decode(got)
+	if e != nil {
+		t.Fatal(e)
+	}
+
+	assert(t, ds.Columns[0].TimeFormat, got.Columns[0].TimeFormat, true)
+
+	tf := got.Columns[1].TimeFormat
+
+	assert(t, 0, len(tf.Layouts), true)
+	assert(t, "2018-01-01T06:59:00+07:00",
+		tf.Format(got.Rows[0].GetRecord(1).Time()), true)
+}
@@ -0,0 +1,233 @@
+// Copyright 2017 M. Shulhan <ms@kilabit.info>. All rights reserved.
+// Use of this source code is governed by a BSD-style license that can be found
+// in the LICENSE file.
+
+package tabula
+
+import (
+	"strconv"
+)
+
+//
+// inferTypes contain the candidate types in order of priority, where the
+// first type is chosen if more than one type has the same confidence.
+//
+var inferTypes = []int{TInteger, TReal, TBool, TTime}
+
+//
+// TypeInference scan the string value of each column in dataset to propose
+// its type: TInteger, TReal, TBool, TTime, or TString if none of them match.
+//
+// For example, to infer the type from the first 1000 rows, and apply it if
+// at least 99% of the values match,
+//
+//	ti := tabula.NewTypeInference()
+//	ti.MaxRows = 1000
+//	ti.Threshold = 0.99
+//	inferred := ti.Apply(ds)
+//
+type TypeInference struct {
+	// MaxRows maximum number of rows to be scanned. If its zero or
+	// negative, all rows will be scanned.
+	MaxRows int
+	// Threshold is the minimum confidence of type to be chosen, between
+	// 0 and 1. If its zero or negative, 1 is used, which mean all values
+	// must match the type.
+	Threshold float64
+}
+
+//
+// InferredType contain the proposed type of column and the report of how
+// many values match each candidate type.
+//
+type InferredType struct {
+	// Name of column.
+	Name string
+	// Type is the proposed type of column.
+	Type int
+	// Confidence is the ratio of values that match Type, between 0 and 1.
+	// If no candidate type pass the threshold, Type is TString and
+	// Confidence is the highest ratio of candidate types, so it can be
+	// compared with the threshold. Column without any value has zero
+	// confidence.
+	Confidence float64
+	// Count is the number of values that is scanned. Nil, null, missing
+	// value, and empty string is not counted.
+	Count int
+	// Matches contain the number of values that can be converted to each
+	// candidate type.
+	Matches map[int]int
+}
+
+//
+// NewTypeInference create and return new type inference that scan all rows
+// and require all values to match the type.
+//
+func NewTypeInference() *TypeInference {
+	return &TypeInference{
+		Threshold: 1,
+	}
+}
+
+//
+// Infer return the proposed type of each column in dataset. Only TString
+// column is scanned, other column is returned with its own type and full
+// confidence. The type with the highest confidence that pass the threshold
+// is chosen. Integer value also match TReal, and "1" or "0" also match
+// TBool. TTime value is parsed using the column time format.
+//
+func (ti *TypeInference) Infer(ds DatasetInterface) (inferred []InferredType) {
+	threshold := ti.Threshold
+	if threshold <= 0 {
+		threshold = 1
+	}
+
+	nrow := ds.Len()
+	if ti.MaxRows > 0 && ti.MaxRows < nrow {
+		nrow = ti.MaxRows
+	}
+
+	cols := ds.GetColumns()
+	inferred = make([]InferredType, len(*cols))
+
+	for x := range *cols {
+		col := &(*cols)[x]
+		inf := &inferred[x]
+
+		inf.Name = col.Name
+		inf.Type = col.Type
+		inf.Matches = make(map[int]int)
+
+		for r := 0; r < nrow; r++ {
+			rec := getRecordAt(ds, r, x)
+			if rec == nil || rec.IsNull() || rec.IsMissingValue() {
+				continue
+			}
+
+			v := rec.String()
+			if len(v) == 0 || col.IsMissingValue(v) {
+				continue
+			}
+
+			inf.Count++
+
+			if col.Type != TString {
+				inf.Matches[col.Type]++
+				continue
+			}
+
+			for _, t := range inferMatches(v, col.TimeFormat) {
+				inf.Matches[t]++
+			}
+		}
+
+		if inf.Count == 0 {
+			continue
+		}
+		if col.Type != TString {
+			inf.Confidence = 1
+			continue
+		}
+
+		best := 0.0
+
+		for _, t := range inferTypes {
+			conf := float64(inf.Matches[t]) / float64(inf.Count)
+			if conf > best {
+				best = conf
+			}
+			if conf < threshold {
+				continue
+			}
+			if inf.Type == TString || conf > inf.Confidence {
+				inf.Type = t
+				inf.Confidence = conf
+			}
+		}
+
+		if inf.Type == TString {
+			inf.Confidence = best
+		}
+	}
+
+	return inferred
+}
+
+//
+// Apply infer the type of each column in dataset, convert the records in
+// place to the proposed type, and return the inferred types. See Infer and
+// ApplyInferredTypes.
+//
+func (ti *TypeInference) Apply(ds DatasetInterface) (inferred []InferredType) {
+	inferred = ti.Infer(ds)
+	ApplyInferredTypes(ds, inferred)
+	return inferred
+}
+
+//
+// ApplyInferredTypes set the type of each TString column in dataset to the
+// inferred type, and convert its records in place. Empty string and column
+// missing value is converted to null, "?" to missing value, and the value
+// that can not be converted is set to null, so it can be found using
+// NullIndexes.
+//
+func ApplyInferredTypes(ds DatasetInterface, inferred []InferredType) {
+	cols := ds.GetColumns()
+	nrow := ds.Len()
+
+	for x := range *cols {
+		col := &(*cols)[x]
+		if x >= len(inferred) || col.Type != TString ||
+			inferred[x].Type == TString {
+			continue
+		}
+
+		t := inferred[x].Type
+
+		for r := 0; r < nrow; r++ {
+			rec := getRecordAt(ds, r, x)
+			if rec == nil || rec.IsNull() {
+				continue
+			}
+
+			v := rec.String()
+
+			switch {
+			case len(v) == 0 || col.IsMissingValue(v):
+				rec.SetNull(t)
+			case rec.IsMissingValue():
+				rec.SetMissingValue(t)
+			case t == TTime:
+				if rec.SetTimeValue(v, col.TimeFormat) != nil {
+					rec.SetNull(t)
+				}
+			default:
+				if rec.SetValue(v, t) != nil {
+					rec.SetNull(t)
+				}
+			}
+		}
+
+		col.Type = t
+	}
+}
+
+//
+// inferMatches return the candidate types that value `v` can be converted
+// to.
+//
+func inferMatches(v string, tf *TimeFormat) (types []int) {
+	if _, e := strconv.ParseInt(v, 10, 64); e == nil {
+		types = append(types, TInteger)
+	}
+	if _, e := strconv.ParseFloat(v, 64); e == nil {
+		types = append(types, TReal)
+	}
+	if _, e := ParseBool(v); e == nil {
+		types = append(types, TBool)
+	}
+	if _, e := tf.Parse(v); e == nil {
+		types = append(types, TTime)
+	}
+	return types
+}
@@ -0,0 +1,129 @@
+// Copyright 2017 M. Shulhan <ms@kilabit.info>. All rights reserved.
+// Use of this source code is governed by a BSD-style license that can be found
+// in the LICENSE file.
+
+package tabula_test
+
+import (
+	"github.com/shuLhan/tabula"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestTypeInference(t *testing.T) {
+	input := "id,score,active,at,name\n" +
+		"1,1.5,yes,2017-12-31,a\n" +
+		"2,2,no,2018-01-01,b\n" +
+		"3,,1,?,c\n" +
+		"4,x,off,2018-01-02,d\n"
+
+	modes := []int{
+		tabula.DatasetModeRows,
+		tabula.DatasetModeColumns,
+		tabula.DatasetModeMatrix,
+	}
+
+	for _, mode := range modes {
+		ds := tabula.NewDataset(mode, []int{
+			tabula.TString, tabula.TString, tabula.TString,
+			tabula.TString, tabula.TString,
+		}, nil)
+
+		reader := tabula.NewDSVReader()
+		reader.Header = true
+
+		e := reader.Read(strings.NewReader(input), ds)
+		if e != nil {
+			t.Fatal(e)
+		}
+
+		ti := tabula.NewTypeInference()
+		inferred := ti.Infer(ds)
+
+		assert(t, []int{
+			tabula.TInteger, tabula.TString, tabula.TBool,
+			tabula.TTime, tabula.TString,
+		}, inferredTypes(inferred), true)
+		assert(t, "score", inferred[1].Name, true)
+		assert(t, 3, inferred[1].Count, true)
+		assert(t, map[int]int{
+			tabula.TInteger: 1,
+			tabula.TReal:    2,
+		}, inferred[1].Matches, true)
+		assert(t, 2.0/3, inferred[1].Confidence, true)
+		assert(t, 0.0, inferred[4].Confidence, true)
+
+		ti.Threshold = 0.6
+		inferred = ti.Apply(ds)
+
+		assert(t, tabula.TReal, inferred[1].Type, true)
+		assert(t, 2.0/3, inferred[1].Confidence, true)
+		assert(t, []int{
+			tabula.TInteger, tabula.TReal, tabula.TBool,
+			tabula.TTime, tabula.TString,
+		}, ds.GetColumnsType(), true)
+
+		row := ds.GetDataAsRows()
+
+		assert(t, int64(4), (*row)[3].GetRecord(0).Integer(), true)
+		assert(t, 2.0, (*row)[1].GetRecord(1).Float(), true)
+		assert(t, true, (*row)[2].GetRecord(1).IsNull(), true)
+		assert(t, tabula.TReal, (*row)[2].GetRecord(1).Type(), true)
+		assert(t, true, (*row)[3].GetRecord(1).IsNull(), true)
+		assert(t, true, (*row)[2].GetRecord(2).Bool(), true)
+		assert(t, time.Date(2018, 1, 1, 0, 0, 0, 0, time.UTC),
+			(*row)[1].GetRecord(3).Time(), true)
+		assert(t, true, (*row)[2].GetRecord(3).IsMissingValue(), true)
+	}
+}
+
+func TestTypeInferenceMaxRows(t *testing.T) {
+	ds := tabula.NewDataset(tabula.DatasetModeRows, []int{tabula.TString},
+		[]string{"v"})
+
+	for _, v := range []string{"1", "2", "a"} {
+		ds.PushRow(&tabula.Row{tabula.NewRecordString(v)})
+	}
+
+	ti := tabula.NewTypeInference()
+	ti.MaxRows = 2
+
+	inferred := ti.Infer(ds)
+
+	assert(t, tabula.TInteger, inferred[0].Type, true)
+	assert(t, 2, inferred[0].Count, true)
+
+	ti.MaxRows = 0
+	inferred = ti.Infer(ds)
+
+	assert(t, tabula.TString, inferred[0].Type, true)
+}
+
+func TestTypeInferenceBelowThreshold(t *testing.T) {
+	ds := tabula.NewDataset(tabula.DatasetModeRows, []int{tabula.TString},
+		[]string{"v"})
+
+	for _, v := range []string{"1", "2", "3", "a"} {
+		ds.PushRow(&tabula.Row{tabula.NewRecordString(v)})
+	}
+
+	ti := tabula.NewTypeInference()
+	inferred := ti.Infer(ds)
+
+	assert(t, tabula.TString, inferred[0].Type, true)
+	assert(t, 0.75, inferred[0].Confidence, true)
+
+	ti.Threshold = 0.75
+	inferred = ti.Infer(ds)
+
+	assert(t, tabula.TInteger, inferred[0].Type, true)
+	assert(t, 0.75, inferred[0].Confidence, true)
+}
+
+func inferredTypes(inferred []tabula.InferredType) (types []int) {
+	for _, inf := range inferred {
+		types = append(types, inf.Type)
+	}
+	return types
+}
@@ -0,0 +1,682 @@
+// Copyright 2017 M. Shulhan <ms@kilabit.info>. All rights reserved.
+// Use of this source code is governed by a BSD-style license that can be found
+// in the LICENSE file.
+
+package tabula
+
+import (
+	"archive/zip"
+	"encoding/xml"
+	"errors"
+	"io"
+	"path"
+	"strconv"
+	"strings"
+	"time"
+)
+
+const (
+	// xlsxMaxColumns is the maximum number of columns in sheet, where
+	// the last column is "XFD".
+	xlsxMaxColumns = 16384
+)
+
+var (
+	// ErrInvalidXLSX returned when input is not a valid XLSX file.
+	ErrInvalidXLSX = errors.New("tabula: invalid XLSX format")
+	// ErrSheetNotFound returned when the sheet is not exist in workbook.
+	ErrSheetNotFound = errors.New("tabula: sheet not found")
+)
+
+//
+// XLSXReader read a sheet in XLSX (Office Open XML spreadsheet) file into
+// dataset.
+//
+// If dataset does not have any columns, the columns will be created with
+// type detected from cells value: TBool if all values is boolean, TTime if
+// all values is ISO 8601 date cell or number with date format, TInteger if
+// all numbers in column is integer, TReal if all values is number, and
+// TString otherwise. Boolean cell in other than TBool column is read as
+// number 1 or 0. Cell that does not exist, empty cell, and error cell is set
+// to null, as is cell with one of column missing values. Empty text in other
+// than TString column is set to missing value.
+//
+// Number cell in TTime column is read as serial date in 1900 date system,
+// in the time zone of column time format or UTC, and text cell is parsed
+// using column time format.
+//
+type XLSXReader struct {
+	// Sheet is the name of sheet to be read. If its empty, the first
+	// sheet is read.
+	Sheet string
+	// Header if its true, the first row in sheet contain the name of
+	// columns.
+	Header bool
+	// NumberFormat if its not nil, will be used to convert the text cell
+	// for integer and real column. Number cell is always read as is.
+	NumberFormat *NumberFormat
+}
+
+//
+// xlsxValue contain the text of cell and flag whether its a number.
+//
+type xlsxValue struct {
+	v       string
+	numeric bool
+	boolean bool
+	date    bool
+	valid   bool
+}
+
+//
+// xlsxText contain the text of shared string or inline string, which may
+// be split into several runs.
+//
+type xlsxText struct {
+	T string `xml:"t"`
+	R []struct {
+		T string `xml:"t"`
+	} `xml:"r"`
+}
+
+//
+// xlsxCell contain the cell element in sheet.
+//
+type xlsxCell struct {
+	R  string   `xml:"r,attr"`
+	T  string   `xml:"t,attr"`
+	S  int      `xml:"s,attr"`
+	V  string   `xml:"v"`
+	IS xlsxText `xml:"is"`
+}
+
+//
+// xlsxStyleSheet contain the number formats and cell formats in styles.
+//
+type xlsxStyleSheet struct {
+	NumFmts []struct {
+		ID   int    `xml:"numFmtId,attr"`
+		Code string `xml:"formatCode,attr"`
+	} `xml:"numFmts>numFmt"`
+	CellXfs []struct {
+		NumFmtID int `xml:"numFmtId,attr"`
+	} `xml:"cellXfs>xf"`
+}
+
+//
+// xlsxRow contain the row element in sheet.
+//
+type xlsxRow struct {
+	R int        `xml:"r,attr"`
+	C []xlsxCell `xml:"c"`
+}
+
+//
+// NewXLSXReader create and return new XLSX reader which read the first
+// sheet, with header.
+//
+func NewXLSXReader() *XLSXReader {
+	return &XLSXReader{
+		Header: true,
+	}
+}
+
+//
+// ReadFile open XLSX file and read the sheet into dataset `ds`.
+//
+func (reader *XLSXReader) ReadFile(file string, ds DatasetInterface) (
+	e error,
+) {
+	r, size, closer, e := openFileAt(file)
+	if e != nil {
+		return e
+	}
+
+	e = reader.Read(r, size, ds)
+
+	if closer != nil {
+		errClose := closer.Close()
+		if e == nil {
+			e = errClose
+		}
+	}
+
+	return
+}
+
+//
+// Read XLSX from `r` with `size` bytes and load the sheet into dataset `ds`.
+//
+func (reader *XLSXReader) Read(r io.ReaderAt, size int64,
+	ds DatasetInterface,
+) (e error) {
+	zr, e := zip.NewReader(r, size)
+	if e != nil {
+		return ErrInvalidXLSX
+	}
+
+	files := make(map[string]*zip.File, len(zr.File))
+	for _, f := range zr.File {
+		files[f.Name] = f
+	}
+
+	sheetPath, e := reader.findSheet(files)
+	if e != nil {
+		return e
+	}
+
+	var sst []string
+
+	if f, ok := files["xl/sharedStrings.xml"]; ok {
+		sst, e = xlsxReadSharedStrings(f)
+		if e != nil {
+			return e
+		}
+	}
+
+	var dates []bool
+
+	if f, ok := files["xl/styles.xml"]; ok {
+		dates, e = xlsxReadDateStyles(f)
+		if e != nil {
+			return e
+		}
+	}
+
+	f, ok := files[sheetPath]
+	if !ok {
+		return ErrInvalidXLSX
+	}
+
+	lines, values, e := xlsxReadSheet(f, sst, dates)
+	if e != nil {
+		return e
+	}
+
+	var names []string
+
+	if reader.Header && len(values) > 0 {
+		for _, v := range values[0] {
+			names = append(names, v.v)
+		}
+		lines = lines[1:]
+		values = values[1:]
+	}
+
+	ncol := len(names)
+	for _, row := range values {
+		if len(row) > ncol {
+			ncol = len(row)
+		}
+	}
+	for len(names) < ncol {
+		names = append(names, "")
+	}
+
+	if ds.GetNColumn() <= 0 {
+		ds.Init(ds.GetMode(), xlsxColumnTypes(values, ncol), names)
+	} else {
+		initDatasetColumns(ds, names, ncol)
+	}
+
+	cols := *ds.GetColumns()
+
+	for y, cells := range values {
+		row := make(Row, len(cols))
+
+		for x := range cols {
+			var v xlsxValue
+			if x < len(cells) {
+				v = cells[x]
+			}
+
+			t := cols[x].Type
+			row[x] = NewRecord()
+			if !v.valid || cols[x].IsMissingValue(v.v) {
+				row[x].SetNull(t)
+				continue
+			}
+			if v.v == "" && t != TString {
+				row[x].SetMissingValue(t)
+				continue
+			}
+
+			s := v.v
+			if !v.numeric && (t == TInteger || t == TReal) {
+				s = reader.NumberFormat.Normalize(s)
+			}
+
+			if t == TTime {
+				e = xlsxSetTime(row[x], &v, cols[x].TimeFormat)
+			} else {
+				e = row[x].SetValue(s, t)
+			}
+			if e == nil {
+				e = cols[x].Encode(row[x])
+			}
+			if e != nil {
+				return &ReadError{
+					Line:   lines[y],
+					Column: x + 1,
+					Value:  v.v,
+					Err:    e,
+				}
+			}
+		}
+
+		ds.PushRow(&row)
+	}
+
+	return nil
+}
+
+//
+// xlsxSetTime set the record with time from cell value `v`. Number is
+// converted from serial date, ISO 8601 date cell is parsed using
+// DefaultTimeLayouts, and text is parsed using time format `tf`.
+//
+func xlsxSetTime(rec *Record, v *xlsxValue, tf *TimeFormat) error {
+	switch {
+	case v.numeric:
+		f64, e := strconv.ParseFloat(v.v, 64)
+		if e != nil {
+			return e
+		}
+		loc := time.UTC
+		if tf != nil && tf.Location != nil {
+			loc = tf.Location
+		}
+		rec.SetTime(xlsxSerialTime(f64, loc))
+		return nil
+	case v.date:
+		return rec.SetTimeValue(v.v, nil)
+	}
+	return rec.SetTimeValue(v.v, tf)
+}
+
+//
+// findSheet return the path of sheet in zip file, using the workbook and
+// its relationships.
+//
+func (reader *XLSXReader) findSheet(files map[string]*zip.File) (
+	sheetPath string, e error,
+) {
+	var workbook struct {
+		Sheets []struct {
+			Name string `xml:"name,attr"`
+			RID  string `xml:"http://schemas.openxmlformats.org/officeDocument/2006/relationships id,attr"`
+		} `xml:"sheets>sheet"`
+	}
+	var rels struct {
+		Rels []struct {
+			ID     string `xml:"Id,attr"`
+			Target string `xml:"Target,attr"`
+		} `xml:"Relationship"`
+	}
+
+	e = xlsxUnmarshal(files["xl/workbook.xml"], &workbook)
+	if e != nil {
+		return "", e
+	}
+	e = xlsxUnmarshal(files["xl/_rels/workbook.xml.rels"], &rels)
+	if e != nil {
+		return "", e
+	}
+
+	rid := ""
+	for _, sheet := range workbook.Sheets {
+		if reader.Sheet == "" || sheet.Name == reader.Sheet {
+			rid = sheet.RID
+			break
+		}
+	}
+	if rid == "" {
+		return "", ErrSheetNotFound
+	}
+
+	for _, rel := range rels.Rels {
+		if rel.ID != rid {
+			continue
+		}
+		if strings.HasPrefix(rel.Target, "/") {
+			return strings.TrimPrefix(rel.Target, "/"), nil
+		}
+		return path.Join("xl", rel.Target), nil
+	}
+
+	return "", ErrInvalidXLSX
+}
+
+//
+// xlsxUnmarshal read the XML file in zip and decode it into `v`.
+//
+func xlsxUnmarshal(f *zip.File, v interface{}) (e error) {
+	if f == nil {
+		return ErrInvalidXLSX
+	}
+
+	rc, e := f.Open()
+	if e != nil {
+		return e
+	}
+
+	e = xml.NewDecoder(rc).Decode(v)
+	if e != nil {
+		e = ErrInvalidXLSX
+	}
+
+	errClose := rc.Close()
+	if e == nil {
+		e = errClose
+	}
+
+	return
+}
+
+//
+// String return the text of shared string or inline string.
+//
+func (text *xlsxText) String() string {
+	if len(text.R) == 0 {
+		return text.T
+	}
+
+	s := ""
+	for _, r := range text.R {
+		s += r.T
+	}
+	return s
+}
+
+//
+// xlsxReadSharedStrings read all shared strings from zip file.
+//
+func xlsxReadSharedStrings(f *zip.File) (sst []string, e error) {
+	rc, e := f.Open()
+	if e != nil {
+		return nil, e
+	}
+	defer rc.Close()
+
+	dec := xml.NewDecoder(rc)
+
+	for {
+		tok, e := dec.Token()
+		if e == io.EOF {
+			return sst, nil
+		}
+		if e != nil {
+			return nil, ErrInvalidXLSX
+		}
+
+		start, ok := tok.(xml.StartElement)
+		if !ok || start.Name.Local != "si" {
+			continue
+		}
+
+		var si xlsxText
+
+		e = dec.DecodeElement(&si, &start)
+		if e != nil {
+			return nil, ErrInvalidXLSX
+		}
+
+		sst = append(sst, si.String())
+	}
+}
+
+//
+// xlsxReadDateStyles read the cell formats in styles and return true for
+// each format that display the number as date or time.
+//
+func xlsxReadDateStyles(f *zip.File) (dates []bool, e error) {
+	var styles xlsxStyleSheet
+
+	e = xlsxUnmarshal(f, &styles)
+	if e != nil {
+		return nil, e
+	}
+
+	codes := make(map[int]string, len(styles.NumFmts))
+	for _, numFmt := range styles.NumFmts {
+		codes[numFmt.ID] = numFmt.Code
+	}
+
+	dates = make([]bool, len(styles.CellXfs))
+
+	for x, xf := range styles.CellXfs {
+		code, ok := codes[xf.NumFmtID]
+		if ok {
+			dates[x] = xlsxIsDateFormat(code)
+		} else {
+			dates[x] = xlsxIsDateFormatID(xf.NumFmtID)
+		}
+	}
+
+	return dates, nil
+}
+
+//
+// xlsxIsDateFormatID return true if built-in number format `id` is date or
+// time format.
+//
+func xlsxIsDateFormatID(id int) bool {
+	return (id >= 14 && id <= 22) || (id >= 27 && id <= 36) ||
+		(id >= 45 && id <= 47) || (id >= 50 && id <= 58)
+}
+
+//
+// xlsxIsDateFormat return true if number format `code` contain date or
+// time part, ignoring the quoted text, escaped characters, and the color
+// or locale inside brackets.
+//
+func xlsxIsDateFormat(code string) bool {
+	for x := 0; x < len(code); x++ {
+		switch code[x] {
+		case '"':
+			end := strings.IndexByte(code[x+1:], '"')
+			if end < 0 {
+				return false
+			}
+			x += end + 1
+		case '[':
+			end := strings.IndexByte(code[x+1:], ']')
+			if end < 0 {
+				return false
+			}
+			x += end + 1
+		case '\\', '_', '*':
+			x++
+		case 'y', 'Y', 'm', 'M', 'd', 'D', 'h', 'H', 's', 'S':
+			return true
+		}
+	}
+	return false
+}
+
+//
+// xlsxReadSheet read all rows in sheet, and return the row number and
+// values of each row. Parameter `dates` contain the date cell formats.
+//
+func xlsxReadSheet(f *zip.File, sst []string, dates []bool) (lines []int,
+	values [][]xlsxValue, e error,
+) {
+	rc, e := f.Open()
+	if e != nil {
+		return nil, nil, e
+	}
+	defer rc.Close()
+
+	dec := xml.NewDecoder(rc)
+	line := 0
+
+	for {
+		tok, e := dec.Token()
+		if e == io.EOF {
+			return lines, values, nil
+		}
+		if e != nil {
+			return nil, nil, ErrInvalidXLSX
+		}
+
+		start, ok := tok.(xml.StartElement)
+		if !ok || start.Name.Local != "row" {
+			continue
+		}
+
+		var row xlsxRow
+
+		e = dec.DecodeElement(&row, &start)
+		if e != nil {
+			return nil, nil, ErrInvalidXLSX
+		}
+
+		line++
+		if row.R > 0 {
+			line = row.R
+		}
+
+		var cells []xlsxValue
+		x := -1
+
+		for _, c := range row.C {
+			x++
+			if c.R != "" {
+				x, e = xlsxColumnIndex(c.R)
+				if e != nil {
+					return nil, nil, e
+				}
+			}
+			for len(cells) <= x {
+				cells = append(cells, xlsxValue{})
+			}
+			cells[x], e = newXLSXValue(&c, sst, dates)
+			if e != nil {
+				return nil, nil, e
+			}
+		}
+
+		lines = append(lines, line)
+		values = append(values, cells)
+	}
+}
+
+//
+// newXLSXValue return the value of cell based on its type. Number with date
+// cell format in `dates` is marked as date.
+//
+func newXLSXValue(c *xlsxCell, sst []string, dates []bool) (
+	v xlsxValue, e error,
+) {
+	switch c.T {
+	case "s":
+		idx, e := strconv.Atoi(c.V)
+		if e != nil || idx < 0 || idx >= len(sst) {
+			return v, ErrInvalidXLSX
+		}
+		return xlsxValue{v: sst[idx], valid: true}, nil
+	case "inlineStr":
+		return xlsxValue{v: c.IS.String(), valid: true}, nil
+	case "str":
+		return xlsxValue{v: c.V, valid: true}, nil
+	case "d":
+		return xlsxValue{v: c.V, date: c.V != "", valid: true}, nil
+	case "e":
+		return v, nil
+	case "b":
+		if c.V == "" {
+			return v, nil
+		}
+		return xlsxValue{
+			v:       c.V,
+			numeric: true,
+			boolean: true,
+			valid:   true,
+		}, nil
+	}
+
+	if c.V == "" {
+		return v, nil
+	}
+
+	return xlsxValue{
+		v:       c.V,
+		numeric: true,
+		date:    c.S >= 0 && c.S < len(dates) && dates[c.S],
+		valid:   true,
+	}, nil
+}
+
+//
+// xlsxColumnIndex return the zero based column index from cell reference,
+// for example "A1" is 0 and "AB12" is 27. Reference after the last column,
+// "XFD", will return ErrInvalidXLSX.
+//
+func xlsxColumnIndex(ref string) (idx int, e error) {
+	n := 0
+	for _, c := range ref {
+		if c < 'A' || c > 'Z' {
+			break
+		}
+		idx = idx*26 + int(c-'A') + 1
+		if idx > xlsxMaxColumns {
+			return 0, ErrInvalidXLSX
+		}
+		n++
+	}
+	if n == 0 {
+		return 0, ErrInvalidXLSX
+	}
+	return idx - 1, nil
+}
+
+//
+// xlsxColumnTypes detect the type of each column from cells value.
+//
+func xlsxColumnTypes(values [][]xlsxValue, ncol int) (types []int) {
+	types = make([]int, ncol)
+
+	for x := range types {
+		types[x] = TInteger
+		n := 0
+		nbool := 0
+		ndate := 0
+
+		for _, row := range values {
+			if x >= len(row) || !row[x].valid {
+				continue
+			}
+			n++
+			if row[x].boolean {
+				nbool++
+			}
+			if row[x].date {
+				ndate++
+				continue
+			}
+			if !row[x].numeric {
+				types[x] = TString
+				break
+			}
+			if types[x] == TReal {
+				continue
+			}
+			_, e := strconv.ParseInt(row[x].v, 10, 64)
+			if e != nil {
+				types[x] = TReal
+			}
+		}
+
+		if n == 0 {
+			types[x] = TString
+		} else if nbool == n {
+			types[x] = TBool
+		} else if ndate == n {
+			types[x] = TTime
+		} else if ndate > 0 {
+			types[x] = TString
+		}
+	}
+
+	return types
+}
@@ -0,0 +1,187 @@
+// Copyright 2017 M. Shulhan <ms@kilabit.info>. All rights reserved.
+// Use of this source code is governed by a BSD-style license that can be found
+// in the LICENSE file.
+
+package tabula_test
+
+import (
+	"archive/zip"
+	"bytes"
+	"fmt"
+	"github.com/shuLhan/tabula"
+	"testing"
+)
+
+//
+// xlsxDataSheet contain shared strings, rich text, boolean, error, and empty
+// cells.
+//
+const xlsxDataSheet = `<?xml version="1.0"?>
+<worksheet xmlns="http://schemas.openxmlformats.org/spreadsheetml/2006/main">
+<sheetData>
+<row r="1"><c r="A1" t="s"><v>0</v></c><c r="B1" t="s"><v>1</v></c><c r="C1" t="s"><v>2</v></c></row>
+<row r="2"><c r="A2"><v>1</v></c><c r="B2"><v>0.5</v></c><c r="C2" t="s"><v>3</v></c></row>
+<row r="4"><c r="A4" t="b"><v>1</v></c><c r="C4" t="inlineStr"><is><t>beta</t></is></c><c r="D4"><v>7</v></c></row>
+<row r="5"><c r="A5"><v>3</v></c><c r="B5" t="e"><v>#DIV/0!</v></c><c r="C5" t="str"><v>gamma</v></c></row>
+</sheetData>
+</worksheet>`
+
+//
+// createXLSX create XLSX file in memory with two sheets, where the second
+// sheet content is `dataSheet`.
+//
+func createXLSX(t *testing.T, dataSheet string) []byte {
+	parts := map[string]string{
+		"xl/workbook.xml": `<?xml version="1.0"?>
+<workbook xmlns="http://schemas.openxmlformats.org/spreadsheetml/2006/main"
+ xmlns:r="http://schemas.openxmlformats.org/officeDocument/2006/relationships">
+<sheets>
+<sheet name="Empty" sheetId="1" r:id="rId1"/>
+<sheet name="Data" sheetId="2" r:id="rId2"/>
+</sheets>
+</workbook>`,
+		"xl/_rels/workbook.xml.rels": `<?xml version="1.0"?>
+<Relationships xmlns="http://schemas.openxmlformats.org/package/2006/relationships">
+<Relationship Id="rId1" Type="worksheet" Target="worksheets/sheet1.xml"/>
+<Relationship Id="rId2" Type="worksheet" Target="/xl/worksheets/data.xml"/>
+</Relationships>`,
+		"xl/sharedStrings.xml": `<?xml version="1.0"?>
+<sst xmlns="http://schemas.openxmlformats.org/spreadsheetml/2006/main">
+<si><t>id</t></si>
+<si><t>score</t></si>
+<si><r><t>na</t></r><r><t>me</t></r></si>
+<si><t>alpha</t><rPh><t>x</t></rPh></si>
+</sst>`,
+		"xl/worksheets/sheet1.xml": `<?xml version="1.0"?>
+<worksheet xmlns="http://schemas.openxmlformats.org/spreadsheetml/2006/main">
+<sheetData/>
+</worksheet>`,
+		"xl/worksheets/data.xml": dataSheet,
+	}
+
+	var buf bytes.Buffer
+
+	zw := zip.NewWriter(&buf)
+	for name, content := range parts {
+		w, e := zw.Create(name)
+		if e != nil {
+			t.Fatal(e)
+		}
+		_, e = w.Write([]byte(content))
+		if e != nil {
+			t.Fatal(e)
+		}
+	}
+
+	e := zw.Close()
+	if e != nil {
+		t.Fatal(e)
+	}
+
+	return buf.Bytes()
+}
+
+func TestXLSXReader(t *testing.T) {
+	in := createXLSX(t, xlsxDataSheet)
+
+	reader := tabula.NewXLSXReader()
+	reader.Sheet = "Data"
+
+	ds := tabula.NewDataset(tabula.DatasetModeColumns, nil, nil)
+
+	e := reader.Read(bytes.NewReader(in), int64(len(in)), ds)
+	if e != nil {
+		t.Fatal(e)
+	}
+
+	expTypes := []int{
+		tabula.TInteger, tabula.TReal, tabula.TString, tabula.TInteger,
+	}
+
+	assert(t, []string{"id", "score", "name", ""}, ds.GetColumnsName(), true)
+	assert(t, expTypes, ds.GetColumnsType(), true)
+
+	exp := "[1 1 3][0.5  ][alpha beta gamma][ 7 ]"
+	got := ""
+	for _, col := range ds.Columns {
+		got += fmt.Sprint(col.Records)
+	}
+
+	assert(t, exp, got, true)
+}
+
+func TestXLSXReaderError(t *testing.T) {
+	in := createXLSX(t, xlsxDataSheet)
+
+	reader := tabula.NewXLSXReader()
+	ds := tabula.NewDataset(tabula.DatasetModeRows, nil, nil)
+
+	e := reader.Read(bytes.NewReader(in), int64(len(in)), ds)
+	if e != nil {
+		t.Fatal(e)
+	}
+	assert(t, 0, ds.Len(), true)
+
+	reader.Sheet = "Unknown"
+
+	e = reader.Read(bytes.NewReader(in), int64(len(in)), ds)
+	assert(t, tabula.ErrSheetNotFound, e, true)
+
+	e = reader.Read(bytes.NewReader([]byte("not a zip")), 9, ds)
+	assert(t, tabula.ErrInvalidXLSX, e, true)
+
+	reader.Sheet = "Data"
+	types := []int{
+		tabula.TInteger, tabula.TInteger, tabula.TString, tabula.TInteger,
+	}
+	ds = tabula.NewDataset(tabula.DatasetModeRows, types, nil)
+
+	e = reader.Read(bytes.NewReader(in), int64(len(in)), ds)
+
+	rerr, ok := e.(*tabula.ReadError)
+	assert(t, true, ok, true)
+	assert(t, 2, rerr.Line, true)
+	assert(t, 2, rerr.Column, true)
+
+	types[1] = tabula.TReal
+	ds = tabula.NewDataset(tabula.DatasetModeRows, types, nil)
+
+	name := ds.GetColumn(2)
+	name.Categorical = true
+	name.ValueSpace = []string{"alpha", "beta"}
+
+	e = reader.Read(bytes.NewReader(in), int64(len(in)), ds)
+
+	rerr, ok = e.(*tabula.ReadError)
+	assert(t, true, ok, true)
+	assert(t, 5, rerr.Line, true)
+	assert(t, 3, rerr.Column, true)
+	assert(t, "gamma", rerr.Value, true)
+	assert(t, tabula.ErrNotInValueSpace, rerr.Err, true)
+}
+
+func TestXLSXReaderColumnRef(t *testing.T) {
+	refs := map[string]error{
+		"XFD1":            nil,
+		"XFE1":            tabula.ErrInvalidXLSX,
+		"ZZZZZZZZZZZZZZ1": tabula.ErrInvalidXLSX,
+	}
+
+	for ref, exp := range refs {
+		in := createXLSX(t, `<?xml version="1.0"?>
+<worksheet xmlns="http://schemas.openxmlformats.org/spreadsheetml/2006/main">
+<sheetData>
+<row r="1"><c r="`+ref+`"><v>1</v></c></row>
+</sheetData>
+</worksheet>`)
+
+		reader := tabula.NewXLSXReader()
+		reader.Sheet = "Data"
+
+		ds := tabula.NewDataset(tabula.DatasetModeRows, nil, nil)
+
+		e := reader.Read(bytes.NewReader(in), int64(len(in)), ds)
+
+		assert(t, exp, e, true)
+	}
+}
@@ -0,0 +1,343 @@
+// Copyright 2017 M. Shulhan <ms@kilabit.info>. All rights reserved.
+// Use of this source code is governed by a BSD-style license that can be found
+// in the LICENSE file.
+
+package tabula
+
+import (
+	"archive/zip"
+	"bufio"
+	"bytes"
+	"encoding/xml"
+	"io"
+	"math"
+	"strconv"
+	"time"
+)
+
+const (
+	xlsxHeader = `<?xml version="1.0" encoding="UTF-8" standalone="yes"?>` +
+		"\n"
+	xlsxContentTypes = xlsxHeader +
+		`<Types xmlns="http://schemas.openxmlformats.org/package/2006/content-types">` +
+		`<Default Extension="rels" ContentType="application/vnd.openxmlformats-package.relationships+xml"/>` +
+		`<Default Extension="xml" ContentType="application/xml"/>` +
+		`<Override PartName="/xl/workbook.xml" ContentType="application/vnd.openxmlformats-officedocument.spreadsheetml.sheet.main+xml"/>` +
+		`<Override PartName="/xl/worksheets/sheet1.xml" ContentType="application/vnd.openxmlformats-officedocument.spreadsheetml.worksheet+xml"/>` +
+		`<Override PartName="/xl/styles.xml" ContentType="application/vnd.openxmlformats-officedocument.spreadsheetml.styles+xml"/>` +
+		`</Types>`
+	xlsxRels = xlsxHeader +
+		`<Relationships xmlns="http://schemas.openxmlformats.org/package/2006/relationships">` +
+		`<Relationship Id="rId1" Type="http://schemas.openxmlformats.org/officeDocument/2006/relationships/officeDocument" Target="xl/workbook.xml"/>` +
+		`</Relationships>`
+	xlsxWorkbookRels = xlsxHeader +
+		`<Relationships xmlns="http://schemas.openxmlformats.org/package/2006/relationships">` +
+		`<Relationship Id="rId1" Type="http://schemas.openxmlformats.org/officeDocument/2006/relationships/worksheet" Target="worksheets/sheet1.xml"/>` +
+		`<Relationship Id="rId2" Type="http://schemas.openxmlformats.org/officeDocument/2006/relationships/styles" Target="styles.xml"/>` +
+		`</Relationships>`
+	xlsxWorkbookBegin = xlsxHeader +
+		`<workbook xmlns="http://schemas.openxmlformats.org/spreadsheetml/2006/main" xmlns:r="http://schemas.openxmlformats.org/officeDocument/2006/relationships">` +
+		`<sheets><sheet name="`
+	xlsxWorkbookEnd = `" sheetId="1" r:id="rId1"/></sheets></workbook>`
+	xlsxSheetBegin  = xlsxHeader +
+		`<worksheet xmlns="http://schemas.openxmlformats.org/spreadsheetml/2006/main">` +
+		`<sheetData>`
+	xlsxSheetEnd = `</sheetData></worksheet>`
+	// xlsxStyles define the cell format with index 1 for date and time.
+	xlsxStyles = xlsxHeader +
+		`<styleSheet xmlns="http://schemas.openxmlformats.org/spreadsheetml/2006/main">` +
+		`<numFmts count="1"><numFmt numFmtId="164" formatCode="yyyy-mm-dd hh:mm:ss"/></numFmts>` +
+		`<fonts count="1"><font><sz val="11"/><name val="Calibri"/></font></fonts>` +
+		`<fills count="2"><fill><patternFill patternType="none"/></fill><fill><patternFill patternType="gray125"/></fill></fills>` +
+		`<borders count="1"><border><left/><right/><top/><bottom/><diagonal/></border></borders>` +
+		`<cellStyleXfs count="1"><xf numFmtId="0" fontId="0" fillId="0" borderId="0"/></cellStyleXfs>` +
+		`<cellXfs count="2"><xf numFmtId="0" fontId="0" fillId="0" borderId="0" xfId="0"/>` +
+		`<xf numFmtId="164" fontId="0" fillId="0" borderId="0" xfId="0" applyNumberFormat="1"/></cellXfs>` +
+		`</styleSheet>`
+	// xlsxEpoch is the day zero of serial date in 1900 date system,
+	// for dates after February 1900.
+	xlsxEpoch = -2209161600
+)
+
+//
+// XLSXWriter write dataset into XLSX (Office Open XML spreadsheet) file with
+// single sheet.
+//
+// Integer, real, and decimal value is written as number, boolean as boolean,
+// time as serial date number with date format, and the rest as inline
+// string. Time is written in the time zone of column time format, if its
+// set, or in its own time zone. Missing value is written as empty cell.
+//
+type XLSXWriter struct {
+	// Sheet is the name of sheet. Default to "Sheet1".
+	Sheet string
+	// Header if its true, the first row will contain the column names.
+	Header bool
+}
+
+//
+// NewXLSXWriter create and return new XLSX writer with header.
+//
+func NewXLSXWriter() *XLSXWriter {
+	return &XLSXWriter{
+		Sheet:  "Sheet1",
+		Header: true,
+	}
+}
+
+//
+// WriteFile create or truncate `file` and write dataset `ds` into it.
+//
+func (writer *XLSXWriter) WriteFile(file string, ds DatasetInterface) (
+	e error,
+) {
+	f, e := CreateFile(file)
+	if e != nil {
+		return e
+	}
+
+	e = writer.Write(f, ds)
+
+	errClose := f.Close()
+	if e == nil {
+		e = errClose
+	}
+
+	return
+}
+
+//
+// Write dataset `ds` into `w` as XLSX. Dataset is read based on its mode
+// without transposing.
+//
+func (writer *XLSXWriter) Write(w io.Writer, ds DatasetInterface) (e error) {
+	zw := zip.NewWriter(w)
+
+	sheet := writer.Sheet
+	if sheet == "" {
+		sheet = "Sheet1"
+	}
+
+	parts := []struct {
+		name    string
+		content string
+	}{
+		{"[Content_Types].xml", xlsxContentTypes},
+		{"_rels/.rels", xlsxRels},
+		{"xl/_rels/workbook.xml.rels", xlsxWorkbookRels},
+		{"xl/workbook.xml", xlsxWorkbookBegin + xlsxEscape(sheet) +
+			xlsxWorkbookEnd},
+		{"xl/styles.xml", xlsxStyles},
+	}
+
+	for _, part := range parts {
+		var pw io.Writer
+
+		pw, e = zw.Create(part.name)
+		if e != nil {
+			return
+		}
+		_, e = io.WriteString(pw, part.content)
+		if e != nil {
+			return
+		}
+	}
+
+	pw, e := zw.Create("xl/worksheets/sheet1.xml")
+	if e != nil {
+		return
+	}
+
+	e = writer.writeSheet(pw, ds)
+	if e != nil {
+		return
+	}
+
+	return zw.Close()
+}
+
+//
+// writeSheet write the sheet data into `w`.
+//
+func (writer *XLSXWriter) writeSheet(w io.Writer, ds DatasetInterface) (
+	e error,
+) {
+	bw := bufio.NewWriter(w)
+
+	_, e = bw.WriteString(xlsxSheetBegin)
+	if e != nil {
+		return
+	}
+
+	ncol := ds.GetNColumn()
+	refs := make([]string, ncol)
+	for x := range refs {
+		refs[x] = xlsxColumnName(x)
+	}
+	tfs := columnsTimeFormat(ds)
+
+	var line []byte
+	nrow := ds.Len()
+	y := 1
+
+	if writer.Header {
+		line = xlsxAppendRowBegin(line[:0], y)
+		for x, name := range ds.GetColumnsName() {
+			line = xlsxAppendString(line, refs[x], y, name)
+		}
+		line = append(line, "</row>"...)
+
+		_, e = bw.Write(line)
+		if e != nil {
+			return
+		}
+		y++
+	}
+
+	for r := 0; r < nrow; r, y = r+1, y+1 {
+		line = xlsxAppendRowBegin(line[:0], y)
+
+		for x := 0; x < ncol; x++ {
+			line = xlsxAppendRecord(line, refs[x], y,
+				getRecordAt(ds, r, x), timeFormatAt(tfs, x))
+		}
+		line = append(line, "</row>"...)
+
+		_, e = bw.Write(line)
+		if e != nil {
+			return
+		}
+	}
+
+	_, e = bw.WriteString(xlsxSheetEnd)
+	if e != nil {
+		return
+	}
+
+	return bw.Flush()
+}
+
+//
+// xlsxEscape escape the special XML characters in `s`.
+//
+func xlsxEscape(s string) string {
+	var buf bytes.Buffer
+	_ = xml.EscapeText(&buf, []byte(s))
+	return buf.String()
+}
+
+//
+// xlsxColumnName return the column name in cell reference from zero based
+// index, for example 0 is "A" and 27 is "AB".
+//
+func xlsxColumnName(idx int) string {
+	var name []byte
+
+	for idx++; idx > 0; idx = (idx - 1) / 26 {
+		name = append([]byte{byte('A' + (idx-1)%26)}, name...)
+	}
+
+	return string(name)
+}
+
+//
+// xlsxAppendRowBegin append the start of row element into `line`.
+//
+func xlsxAppendRowBegin(line []byte, y int) []byte {
+	line = append(line, `<row r="`...)
+	line = strconv.AppendInt(line, int64(y), 10)
+	return append(line, `">`...)
+}
+
+//
+// xlsxAppendCellBegin append the start of cell element into `line`.
+//
+func xlsxAppendCellBegin(line []byte, ref string, y int) []byte {
+	line = append(line, `<c r="`...)
+	line = append(line, ref...)
+	line = strconv.AppendInt(line, int64(y), 10)
+	return append(line, '"')
+}
+
+//
+// xlsxAppendString append the cell with inline string `v` into `line`.
+//
+func xlsxAppendString(line []byte, ref string, y int, v string) []byte {
+	line = xlsxAppendCellBegin(line, ref, y)
+	line = append(line, ` t="inlineStr"><is><t xml:space="preserve">`...)
+	line = append(line, xlsxEscape(v)...)
+	return append(line, "</t></is></c>"...)
+}
+
+//
+// xlsxAppendRecord append the cell with record value into `line`, where time
+// is converted to time zone in `tf`. Missing value is not written.
+//
+func xlsxAppendRecord(line []byte, ref string, y int, rec *Record,
+	tf *TimeFormat,
+) []byte {
+	if rec == nil || rec.IsNil() || rec.IsMissingValue() {
+		return line
+	}
+
+	switch rec.Type() {
+	case TInteger:
+		line = xlsxAppendCellBegin(line, ref, y)
+		line = append(line, "><v>"...)
+		line = strconv.AppendInt(line, rec.Integer(), 10)
+		return append(line, "</v></c>"...)
+	case TReal:
+		f := rec.Float()
+		if math.IsInf(f, 0) || math.IsNaN(f) {
+			return line
+		}
+		line = xlsxAppendCellBegin(line, ref, y)
+		line = append(line, "><v>"...)
+		line = strconv.AppendFloat(line, f, 'g', -1, 64)
+		return append(line, "</v></c>"...)
+	case TDecimal:
+		line = xlsxAppendCellBegin(line, ref, y)
+		line = append(line, "><v>"...)
+		line = append(line, rec.String()...)
+		return append(line, "</v></c>"...)
+	case TBool:
+		line = xlsxAppendCellBegin(line, ref, y)
+		line = append(line, ` t="b"><v>`...)
+		line = strconv.AppendInt(line, rec.Integer(), 10)
+		return append(line, "</v></c>"...)
+	case TTime:
+		t := rec.Time()
+		if tf != nil && tf.Location != nil {
+			t = t.In(tf.Location)
+		}
+		line = xlsxAppendCellBegin(line, ref, y)
+		line = append(line, ` s="1"><v>`...)
+		line = strconv.AppendFloat(line, xlsxSerialDate(t), 'f', -1, 64)
+		return append(line, "</v></c>"...)
+	}
+
+	return xlsxAppendString(line, ref, y, rec.String())
+}
+
+//
+// xlsxSerialDate convert the wall clock of time `t` into serial date number,
+// which is the number of days since day zero, with fraction of day as time.
+// The time is rounded to milliseconds.
+//
+func xlsxSerialDate(t time.Time) float64 {
+	_, offset := t.Zone()
+	ms := (t.Unix()+int64(offset)-xlsxEpoch)*1000 +
+		int64(t.Nanosecond()+500000)/1e6
+	return float64(ms) / 86400000
+}
+
+//
+// xlsxSerialTime convert the serial date number `v` into time in location
+// `loc`, rounded to milliseconds.
+//
+func xlsxSerialTime(v float64, loc *time.Location) time.Time {
+	ms := int64(math.Floor(v*86400000 + 0.5))
+	t := time.Unix(xlsxEpoch+ms/1000, ms%1000*1e6).UTC()
+	return time.Date(t.Year(), t.Month(), t.Day(), t.Hour(), t.Minute(),
+		t.Second(), t.Nanosecond(), loc)
+}
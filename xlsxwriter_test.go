@@ -0,0 +1,126 @@
+// Copyright 2017 M. Shulhan <ms@kilabit.info>. All rights reserved.
+// Use of this source code is governed by a BSD-style license that can be found
+// in the LICENSE file.
+
+package tabula_test
+
+import (
+	"bytes"
+	"fmt"
+	"github.com/shuLhan/tabula"
+	"testing"
+	"time"
+)
+
+func TestXLSXWriter(t *testing.T) {
+	types := []int{tabula.TInteger, tabula.TReal, tabula.TString}
+	names := []string{"id", "score", "<name & \"title\">"}
+	ds := tabula.NewDataset(tabula.DatasetModeRows, types, names)
+
+	rows := []tabula.Row{{
+		tabula.NewRecordInt(1),
+		tabula.NewRecordReal(0.5),
+		tabula.NewRecordString(" a < b "),
+	}, {
+		tabula.NewRecordNull(tabula.TInteger),
+		tabula.NewRecordReal(1e21),
+		tabula.NewRecordNull(tabula.TString),
+	}}
+	for x := range rows {
+		ds.PushRow(&rows[x])
+	}
+
+	// Add more than 26 columns to test the cell reference.
+	for x := 0; x < 30; x++ {
+		col := tabula.NewColumn(tabula.TInteger, fmt.Sprint("c", x))
+		col.PushBack(tabula.NewRecordInt(int64(x)))
+		col.PushBack(tabula.NewRecordInt(int64(-x)))
+		ds.PushColumn(*col)
+	}
+
+	writer := tabula.NewXLSXWriter()
+	writer.Sheet = "Result"
+
+	var out bytes.Buffer
+
+	e := writer.Write(&out, ds)
+	if e != nil {
+		t.Fatal(e)
+	}
+
+	reader := tabula.NewXLSXReader()
+	reader.Sheet = "Result"
+
+	got := tabula.NewDataset(tabula.DatasetModeRows, nil, nil)
+
+	e = reader.Read(bytes.NewReader(out.Bytes()), int64(out.Len()), got)
+	if e != nil {
+		t.Fatal(e)
+	}
+
+	assert(t, ds.GetColumnsName(), got.GetColumnsName(), true)
+	assert(t, ds.GetColumnsType(), got.GetColumnsType(), true)
+	assert(t, ds.Rows, got.Rows, true)
+}
+
+func TestXLSXWriterDecimal(t *testing.T) {
+	ds := tabula.NewDataset(tabula.DatasetModeRows,
+		[]int{tabula.TDecimal}, []string{"amount"})
+
+	for _, d := range []tabula.Decimal{
+		{Value: 1250, Scale: 2},
+		{Value: -25, Scale: 2},
+	} {
+		ds.PushRow(&tabula.Row{tabula.NewRecordDecimal(d)})
+	}
+
+	var out bytes.Buffer
+
+	e := tabula.NewXLSXWriter().Write(&out, ds)
+	if e != nil {
+		t.Fatal(e)
+	}
+
+	got := tabula.NewDataset(tabula.DatasetModeRows, nil, nil)
+
+	e = tabula.NewXLSXReader().Read(bytes.NewReader(out.Bytes()),
+		int64(out.Len()), got)
+	if e != nil {
+		t.Fatal(e)
+	}
+
+	// Decimal is written as numeric cell.
+	assert(t, []int{tabula.TReal}, got.GetColumnsType(), true)
+	assert(t, "&[12.5]&[-0.25]", got.Rows.String(), true)
+}
+
+func TestXLSXWriterTime(t *testing.T) {
+	ds := tabula.NewDataset(tabula.DatasetModeRows, []int{tabula.TTime},
+		[]string{"at"})
+
+	ds.PushRow(&tabula.Row{tabula.NewRecordTime(time.Date(2017, 12, 31,
+		23, 59, 58, 125e6, time.UTC))})
+	ds.PushRow(&tabula.Row{tabula.NewRecordNull(tabula.TTime)})
+	ds.PushRow(&tabula.Row{tabula.NewRecordTime(time.Date(1900, 3, 1,
+		0, 0, 0, 0, time.UTC))})
+
+	var out bytes.Buffer
+
+	e := tabula.NewXLSXWriter().Write(&out, ds)
+	if e != nil {
+		t.Fatal(e)
+	}
+
+	got := tabula.NewDataset(tabula.DatasetModeRows, nil, nil)
+
+	e = tabula.NewXLSXReader().Read(bytes.NewReader(out.Bytes()),
+		int64(out.Len()), got)
+	if e != nil {
+		t.Fatal(e)
+	}
+
+	assert(t, []int{tabula.TTime}, got.GetColumnsType(), true)
+	assert(t, ds.GetDataAsRows().String(), got.GetDataAsRows().String(),
+		true)
+	assert(t, ds.GetRow(1), got.GetRow(1), true)
+}
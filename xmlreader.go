@@ -0,0 +1,445 @@
+// Copyright 2017 M. Shulhan <ms@kilabit.info>. All rights reserved.
+// Use of this source code is governed by a BSD-style license that can be found
+// in the LICENSE file.
+
+package tabula
+
+import (
+	"encoding/xml"
+	"errors"
+	"io"
+	"strings"
+)
+
+var (
+	// ErrInvalidXMLPath returned when the path of row or field is empty or
+	// not valid.
+	ErrInvalidXMLPath = errors.New("tabula: invalid XML path")
+)
+
+//
+// XMLField map the column in dataset to the value in row element.
+//
+// Path is the slash separated names of child element, relative to row
+// element, and optionally ended with attribute name that is prefixed with
+// "@". For example, "title" is the text of child element "title",
+// "author/name" is the text of "name" inside "author", "@id" is the
+// attribute "id" of row element, and "price/@currency" is the attribute
+// "currency" of child element "price".
+//
+type XMLField struct {
+	// Name of column.
+	Name string
+	// Type of column.
+	Type int
+	// Path of value in row element. If its empty, Name is used as child
+	// element name.
+	Path string
+	// TimeFormat define the layouts and time zone of TTime field. If its
+	// nil, DefaultTimeLayouts in UTC is used.
+	TimeFormat *TimeFormat
+	// MissingValues contain the values, after trimmed for non string
+	// field, that is set to null.
+	MissingValues []string
+	// Scale is the number of digits after decimal point of TDecimal
+	// field, see Column.Scale.
+	Scale int
+}
+
+//
+// XMLReader read the repeated elements in XML document into dataset, where
+// each element is a row and each field is read from its child elements or
+// attributes.
+//
+type XMLReader struct {
+	// RowPath is the slash separated names of row element. The path is
+	// matched with the end of element path in document, so "book" match
+	// all "book" elements and "catalog/book" match "book" inside
+	// "catalog". Path that start with "/" is matched from the root
+	// element.
+	RowPath string
+	// Fields define the columns and where to read their value in row
+	// element. If its empty, the dataset columns name is used as child
+	// element names. If dataset does not have any columns, the fields
+	// is created as string from attributes and child elements of the
+	// first row.
+	Fields []XMLField
+	// MaxRows maximum number of rows to be read. If its zero or negative,
+	// all rows will be read.
+	MaxRows int
+	// MissingValue if its not empty, value that equal to it will be set
+	// to missing value. Empty numeric value is always set to missing
+	// value, and element or attribute that does not exist is set to null.
+	MissingValue string
+}
+
+//
+// xmlNode contain an element and all of its attributes and child elements.
+//
+type xmlNode struct {
+	XMLName xml.Name
+	Attrs   []xml.Attr `xml:",any,attr"`
+	Text    string     `xml:",chardata"`
+	Nodes   []xmlNode  `xml:",any"`
+}
+
+//
+// xmlLineReader count the lines that has been read by XML decoder.
+//
+type xmlLineReader struct {
+	r io.Reader
+	// newlines contain the offset of newlines that has been read from
+	// input but not passed by decoder.
+	newlines []int64
+	off      int64
+	line     int
+}
+
+//
+// NewXMLReader create and return new XML reader that read element in
+// `rowPath` using `fields`.
+//
+func NewXMLReader(rowPath string, fields []XMLField) *XMLReader {
+	return &XMLReader{
+		RowPath:      rowPath,
+		Fields:       fields,
+		MissingValue: DefaultMissingValue,
+	}
+}
+
+//
+// ReadFile open XML file and read all of its rows into dataset `ds`.
+//
+func (reader *XMLReader) ReadFile(file string, ds DatasetInterface) (
+	e error,
+) {
+	f, e := OpenFile(file)
+	if e != nil {
+		return e
+	}
+
+	e = reader.Read(f, ds)
+
+	errClose := f.Close()
+	if e == nil {
+		e = errClose
+	}
+
+	return
+}
+
+//
+// Read all row elements from `r` into dataset `ds`. If Fields is not empty,
+// the dataset columns will be replaced with Fields, in the same order. The
+// dataset mode is not changed. Document with other than UTF-8 encoding is
+// decoded using NewDecodeReader.
+//
+// If field value can not be converted to column type, it will return
+// ReadError which contain the line of row element and the column number.
+//
+func (reader *XMLReader) Read(r io.Reader, ds DatasetInterface) (e error) {
+	rowPath := strings.Split(strings.TrimPrefix(reader.RowPath, "/"), "/")
+	anchored := strings.HasPrefix(reader.RowPath, "/")
+	for _, name := range rowPath {
+		if name == "" {
+			return ErrInvalidXMLPath
+		}
+	}
+
+	fields := reader.Fields
+	useColumns := len(fields) == 0 && ds.GetNColumn() > 0
+	if useColumns {
+		for _, col := range *ds.GetColumns() {
+			fields = append(fields, XMLField{
+				Name:          col.Name,
+				Type:          col.Type,
+				TimeFormat:    col.TimeFormat,
+				MissingValues: col.MissingValues,
+				Scale:         col.Scale,
+			})
+		}
+	}
+
+	detect := len(fields) == 0
+
+	paths, e := xmlFieldsPath(fields)
+	if e != nil {
+		return e
+	}
+
+	lr := &xmlLineReader{r: r}
+	dec := xml.NewDecoder(lr)
+	dec.CharsetReader = func(charset string, in io.Reader) (
+		io.Reader, error,
+	) {
+		return NewDecodeReader(in, charset)
+	}
+
+	var stack []string
+	nrow := 0
+
+	for reader.MaxRows <= 0 || nrow < reader.MaxRows {
+		tok, e := dec.Token()
+		if e == io.EOF {
+			break
+		}
+		if e != nil {
+			return e
+		}
+
+		switch t := tok.(type) {
+		case xml.StartElement:
+			stack = append(stack, t.Name.Local)
+			if !xmlMatchPath(stack, rowPath, anchored) {
+				continue
+			}
+		case xml.EndElement:
+			stack = stack[:len(stack)-1]
+			continue
+		default:
+			continue
+		}
+
+		start := tok.(xml.StartElement)
+		line := lr.lineAt(dec.InputOffset())
+
+		var node xmlNode
+
+		e = dec.DecodeElement(&node, &start)
+		if e != nil {
+			return e
+		}
+		stack = stack[:len(stack)-1]
+
+		if nrow == 0 && detect {
+			fields = node.fields()
+			paths, _ = xmlFieldsPath(fields)
+		}
+		if nrow == 0 && !useColumns {
+			xmlInitColumns(ds, fields)
+		}
+
+		row := make(Row, len(fields))
+		cols := ds.GetColumns()
+
+		for x := range fields {
+			row[x], e = reader.newRecord(&node, paths[x], &(*cols)[x])
+			if e == nil {
+				e = (*cols)[x].Encode(row[x])
+			}
+			if e != nil {
+				v, _ := node.value(paths[x])
+				return &ReadError{
+					Line:   line,
+					Column: x + 1,
+					Value:  v,
+					Err:    e,
+				}
+			}
+		}
+
+		ds.PushRow(&row)
+		nrow++
+	}
+
+	if nrow == 0 && !detect && !useColumns {
+		xmlInitColumns(ds, fields)
+	}
+
+	return nil
+}
+
+//
+// xmlInitColumns replace the dataset columns with `fields`.
+//
+func xmlInitColumns(ds DatasetInterface, fields []XMLField) {
+	types := make([]int, len(fields))
+	names := make([]string, len(fields))
+
+	for x, field := range fields {
+		types[x] = field.Type
+		names[x] = field.Name
+	}
+
+	ds.Init(ds.GetMode(), types, names)
+
+	cols := ds.GetColumns()
+	for x := range *cols {
+		(*cols)[x].TimeFormat = fields[x].TimeFormat
+		(*cols)[x].MissingValues = fields[x].MissingValues
+		(*cols)[x].Scale = fields[x].Scale
+	}
+}
+
+//
+// newRecord create new record from the value of `node` in `path` using the
+// type, time format, missing values, and scale of column `col`.
+//
+func (reader *XMLReader) newRecord(node *xmlNode, path []string,
+	col *Column,
+) (*Record, error) {
+	t := col.Type
+	v, ok := node.value(path)
+	if t != TString {
+		v = strings.TrimSpace(v)
+	}
+
+	if !ok || col.IsMissingValue(v) {
+		return NewRecordNull(t), nil
+	}
+
+	if (t != TString && len(v) == 0) ||
+		(len(reader.MissingValue) > 0 && v == reader.MissingValue) {
+		rec := NewRecord()
+		rec.SetMissingValue(t)
+		return rec, nil
+	}
+
+	return col.newRecord(v, nil)
+}
+
+//
+// xmlFieldsPath split the path of each field.
+//
+func xmlFieldsPath(fields []XMLField) (paths [][]string, e error) {
+	paths = make([][]string, len(fields))
+
+	for x, field := range fields {
+		path := field.Path
+		if path == "" {
+			path = field.Name
+		}
+
+		paths[x] = strings.Split(path, "/")
+
+		for y, name := range paths[x] {
+			if name == "" || name == "@" ||
+				(name[0] == '@' && y != len(paths[x])-1) {
+				return nil, ErrInvalidXMLPath
+			}
+		}
+	}
+
+	return paths, nil
+}
+
+//
+// xmlMatchPath return true if the end of element path in `stack` match
+// with `path`, or if `anchored` is true, the whole stack match with `path`.
+//
+func xmlMatchPath(stack, path []string, anchored bool) bool {
+	if len(stack) < len(path) || (anchored && len(stack) != len(path)) {
+		return false
+	}
+
+	stack = stack[len(stack)-len(path):]
+	for x, name := range path {
+		if stack[x] != name {
+			return false
+		}
+	}
+
+	return true
+}
+
+//
+// value return the text of child element or attribute in `path`. If its not
+// exist, it will return false.
+//
+func (node *xmlNode) value(path []string) (string, bool) {
+	for _, name := range path {
+		if name[0] == '@' {
+			for _, attr := range node.Attrs {
+				if attr.Name.Local == name[1:] {
+					return attr.Value, true
+				}
+			}
+			return "", false
+		}
+
+		child := node.child(name)
+		if child == nil {
+			return "", false
+		}
+		node = child
+	}
+
+	return node.Text, true
+}
+
+//
+// child return the first child element with local name `name`.
+//
+func (node *xmlNode) child(name string) *xmlNode {
+	for x := range node.Nodes {
+		if node.Nodes[x].XMLName.Local == name {
+			return &node.Nodes[x]
+		}
+	}
+	return nil
+}
+
+//
+// fields return the string fields from attributes and child elements of
+// node.
+//
+func (node *xmlNode) fields() (fields []XMLField) {
+	seen := make(map[string]bool)
+
+	for _, attr := range node.Attrs {
+		if attr.Name.Space == "xmlns" || attr.Name.Local == "xmlns" {
+			continue
+		}
+		path := "@" + attr.Name.Local
+		if seen[path] {
+			continue
+		}
+		seen[path] = true
+		fields = append(fields, XMLField{
+			Name: attr.Name.Local,
+			Type: TString,
+			Path: path,
+		})
+	}
+
+	for _, child := range node.Nodes {
+		path := child.XMLName.Local
+		if seen[path] {
+			continue
+		}
+		seen[path] = true
+		fields = append(fields, XMLField{
+			Name: path,
+			Type: TString,
+			Path: path,
+		})
+	}
+
+	return fields
+}
+
+//
+// Read from input and save the offset of newlines.
+//
+func (lr *xmlLineReader) Read(p []byte) (n int, e error) {
+	n, e = lr.r.Read(p)
+	for x, c := range p[:n] {
+		if c == '\n' {
+			lr.newlines = append(lr.newlines, lr.off+int64(x))
+		}
+	}
+	lr.off += int64(n)
+	return
+}
+
+//
+// lineAt return the line number of input at `offset`. The offset must not
+// be less than the offset in previous call.
+//
+func (lr *xmlLineReader) lineAt(offset int64) int {
+	for len(lr.newlines) > 0 && lr.newlines[0] < offset {
+		lr.line++
+		lr.newlines = lr.newlines[1:]
+	}
+	return lr.line + 1
+}
@@ -0,0 +1,170 @@
+// Copyright 2017 M. Shulhan <ms@kilabit.info>. All rights reserved.
+// Use of this source code is governed by a BSD-style license that can be found
+// in the LICENSE file.
+
+package tabula_test
+
+import (
+	"fmt"
+	"github.com/shuLhan/tabula"
+	"strings"
+	"testing"
+)
+
+const testXML = `<?xml version="1.0"?>
+<catalog xmlns="urn:test">
+	<book id="1">
+		<title>Go &amp; You</title>
+		<price currency="USD">10.5</price>
+	</book>
+	<shelf>
+		<book id="2">
+			<title>?</title>
+		</book>
+	</shelf>
+	<book id="3">
+		<title>Data</title>
+		<price currency="EUR"> 7 </price>
+	</book>
+</catalog>
+`
+
+func TestXMLReader(t *testing.T) {
+	fields := []tabula.XMLField{
+		{Name: "id", Type: tabula.TInteger, Path: "@id"},
+		{Name: "title", Type: tabula.TString},
+		{Name: "price", Type: tabula.TReal, Path: "price"},
+		{Name: "currency", Type: tabula.TString, Path: "price/@currency"},
+	}
+
+	cases := []struct {
+		rowPath string
+		exp     string
+	}{{
+		rowPath: "book",
+		exp:     "&[1 Go & You 10.5 USD]&[2 ?  ]&[3 Data 7 EUR]",
+	}, {
+		rowPath: "/catalog/book",
+		exp:     "&[1 Go & You 10.5 USD]&[3 Data 7 EUR]",
+	}, {
+		rowPath: "shelf/book",
+		exp:     "&[2 ?  ]",
+	}}
+
+	for _, c := range cases {
+		ds := tabula.NewDataset(tabula.DatasetModeRows, nil, nil)
+		reader := tabula.NewXMLReader(c.rowPath, fields)
+
+		e := reader.Read(strings.NewReader(testXML), ds)
+		if e != nil {
+			t.Fatal(e)
+		}
+
+		assert(t, []string{"id", "title", "price", "currency"},
+			ds.GetColumnsName(), true)
+		assert(t, c.exp, ds.Rows.String(), true)
+	}
+}
+
+func TestXMLReaderDetect(t *testing.T) {
+	ds := tabula.NewDataset(tabula.DatasetModeColumns, nil, nil)
+	reader := tabula.NewXMLReader("book", nil)
+	reader.MaxRows = 2
+
+	e := reader.Read(strings.NewReader(testXML), ds)
+	if e != nil {
+		t.Fatal(e)
+	}
+
+	assert(t, []string{"id", "title", "price"}, ds.GetColumnsName(), true)
+	assert(t, 2, ds.Len(), true)
+	assert(t, "[1 2]", fmt.Sprint(ds.Columns[0].Records), true)
+	assert(t, "[10.5 ]", fmt.Sprint(ds.Columns[2].Records), true)
+	assert(t, true, ds.Columns[2].Records[1].IsNull(), true)
+}
+
+func TestXMLReaderError(t *testing.T) {
+	fields := []tabula.XMLField{
+		{Name: "title", Type: tabula.TInteger},
+	}
+
+	ds := tabula.NewDataset(tabula.DatasetModeRows, nil, nil)
+
+	e := tabula.NewXMLReader("book", fields).Read(
+		strings.NewReader(testXML), ds)
+
+	re, ok := e.(*tabula.ReadError)
+
+	assert(t, true, ok, true)
+	assert(t, 3, re.Line, true)
+	assert(t, 1, re.Column, true)
+	assert(t, "Go & You", re.Value, true)
+
+	ds = tabula.NewDataset(tabula.DatasetModeRows,
+		[]int{tabula.TString}, []string{"title"})
+
+	title := ds.GetColumn(0)
+	title.Categorical = true
+	title.ValueSpace = []string{"Go & You"}
+
+	e = tabula.NewXMLReader("book", nil).Read(strings.NewReader(testXML),
+		ds)
+
+	re, ok = e.(*tabula.ReadError)
+
+	assert(t, true, ok, true)
+	assert(t, 12, re.Line, true)
+	assert(t, 1, re.Column, true)
+	assert(t, "Data", re.Value, true)
+	assert(t, tabula.ErrNotInValueSpace, re.Err, true)
+
+	e = tabula.NewXMLReader("a//b", fields).Read(
+		strings.NewReader(testXML), ds)
+
+	assert(t, tabula.ErrInvalidXMLPath, e, true)
+}
+
+func TestXMLReaderDecimal(t *testing.T) {
+	fields := []tabula.XMLField{
+		{Name: "id", Type: tabula.TInteger, Path: "@id"},
+		{Name: "price", Type: tabula.TDecimal, Scale: 2},
+	}
+
+	ds := tabula.NewDataset(tabula.DatasetModeRows, nil, nil)
+
+	e := tabula.NewXMLReader("book", fields).Read(
+		strings.NewReader(testXML), ds)
+	if e != nil {
+		t.Fatal(e)
+	}
+
+	assert(t, 2, ds.Columns[1].Scale, true)
+	assert(t, "&[1 10.50]&[2 ]&[3 7.00]", ds.Rows.String(), true)
+
+	// Dataset columns is used if fields is empty.
+	ds = tabula.NewDataset(tabula.DatasetModeRows,
+		[]int{tabula.TDecimal}, []string{"price"})
+	ds.SetColumnsScale([]int{2})
+
+	e = tabula.NewXMLReader("/catalog/book", nil).Read(
+		strings.NewReader(testXML), ds)
+	if e != nil {
+		t.Fatal(e)
+	}
+
+	assert(t, "&[10.50]&[7.00]", ds.Rows.String(), true)
+}
+
+func TestXMLReaderEncoding(t *testing.T) {
+	input := "<?xml version=\"1.0\" encoding=\"ISO-8859-1\"?>" +
+		"<r><row name=\"Cr\xe8me\"/></r>"
+
+	ds := tabula.NewDataset(tabula.DatasetModeRows, nil, nil)
+
+	e := tabula.NewXMLReader("row", nil).Read(strings.NewReader(input), ds)
+	if e != nil {
+		t.Fatal(e)
+	}
+
+	assert(t, "&[Crème]", ds.Rows.String(), true)
+}
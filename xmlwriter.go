@@ -0,0 +1,234 @@
+// Copyright 2017 M. Shulhan <ms@kilabit.info>. All rights reserved.
+// Use of this source code is governed by a BSD-style license that can be found
+// in the LICENSE file.
+
+package tabula
+
+import (
+	"bufio"
+	"encoding/xml"
+	"io"
+	"strings"
+	"unicode"
+)
+
+//
+// XMLWriter write dataset as XML document, where each row is written as
+// row element inside root element, and each column is written as child
+// element or attribute of row element.
+//
+// For example, with Fields {Name: "id", Path: "@id"} and {Name: "title"},
+//
+//	<?xml version="1.0" encoding="UTF-8"?>
+//	<dataset>
+//		<row id="1">
+//			<title>Go</title>
+//		</row>
+//	</dataset>
+//
+// Missing value is not written, so it will be read back as missing value by
+// XMLReader.
+//
+type XMLWriter struct {
+	// Root is the name of root element. Default to "dataset".
+	Root string
+	// Row is the name of row element. Default to "row".
+	Row string
+	// Fields define where to write the column, using the column name as
+	// key. Path is the name of child element, or the name of attribute
+	// if its prefixed with "@". Column that is not defined in Fields is
+	// written as child element with its name. Nested path is not
+	// supported. If TimeFormat is not nil, it will be used to format the
+	// time instead of column time format.
+	Fields []XMLField
+	// Indent is the string used for indentation. If its empty, the
+	// document is written without newline and indentation.
+	Indent string
+}
+
+//
+// NewXMLWriter create and return new XML writer with default root and row
+// element name, and tab as indentation.
+//
+func NewXMLWriter() *XMLWriter {
+	return &XMLWriter{
+		Root:   "dataset",
+		Row:    "row",
+		Indent: "\t",
+	}
+}
+
+//
+// WriteFile create or truncate `file` and write dataset `ds` into it.
+//
+func (writer *XMLWriter) WriteFile(file string, ds DatasetInterface) (
+	e error,
+) {
+	f, e := CreateFile(file)
+	if e != nil {
+		return e
+	}
+
+	e = writer.Write(f, ds)
+
+	errClose := f.Close()
+	if e == nil {
+		e = errClose
+	}
+
+	return
+}
+
+//
+// Write dataset `ds` into `w` as XML document. Dataset is read based on its
+// mode without transposing.
+//
+func (writer *XMLWriter) Write(w io.Writer, ds DatasetInterface) (e error) {
+	root := writer.Root
+	if root == "" {
+		root = "dataset"
+	}
+	rowName := writer.Row
+	if rowName == "" {
+		rowName = "row"
+	}
+
+	names := ds.GetColumnsName()
+	tfs := columnsTimeFormat(ds)
+	tags := make([]string, len(names))
+	isAttr := make([]bool, len(names))
+
+	for x, name := range names {
+		path := name
+		for _, field := range writer.Fields {
+			if field.Name != name {
+				continue
+			}
+			if field.Path != "" {
+				path = field.Path
+			}
+			if field.TimeFormat != nil {
+				tfs[x] = field.TimeFormat
+			}
+			break
+		}
+		if strings.Contains(path, "/") {
+			return ErrInvalidXMLPath
+		}
+		if strings.HasPrefix(path, "@") {
+			isAttr[x] = true
+			path = path[1:]
+		}
+		tags[x] = xmlName(path)
+	}
+
+	nl := ""
+	if writer.Indent != "" {
+		nl = "\n"
+	}
+
+	bw := bufio.NewWriter(w)
+
+	_, e = bw.WriteString(strings.TrimSuffix(xml.Header, "\n") + nl + "<" +
+		xmlName(root) + ">" + nl)
+	if e != nil {
+		return
+	}
+
+	var line []byte
+	nrow := ds.Len()
+
+	for r := 0; r < nrow; r++ {
+		line = append(line[:0], writer.Indent...)
+		line = append(line, '<')
+		line = append(line, xmlName(rowName)...)
+
+		for x := range names {
+			rec := getRecordAt(ds, r, x)
+			if !isAttr[x] || rec == nil || rec.IsNil() ||
+				rec.IsMissingValue() {
+				continue
+			}
+			line = append(line, ' ')
+			line = append(line, tags[x]...)
+			line = append(line, `="`...)
+			line = append(line, xlsxEscape(formatRecordTime(rec,
+				timeFormatAt(tfs, x)))...)
+			line = append(line, '"')
+		}
+
+		hasChild := false
+
+		for x := range names {
+			rec := getRecordAt(ds, r, x)
+			if isAttr[x] || rec == nil || rec.IsNil() ||
+				rec.IsMissingValue() {
+				continue
+			}
+			if !hasChild {
+				line = append(line, '>')
+				line = append(line, nl...)
+				hasChild = true
+			}
+			line = append(line, writer.Indent...)
+			line = append(line, writer.Indent...)
+			line = append(line, '<')
+			line = append(line, tags[x]...)
+			line = append(line, '>')
+			line = append(line, xlsxEscape(formatRecordTime(rec,
+				timeFormatAt(tfs, x)))...)
+			line = append(line, "</"...)
+			line = append(line, tags[x]...)
+			line = append(line, '>')
+			line = append(line, nl...)
+		}
+
+		if hasChild {
+			line = append(line, writer.Indent...)
+			line = append(line, "</"...)
+			line = append(line, xmlName(rowName)...)
+			line = append(line, '>')
+		} else {
+			line = append(line, "/>"...)
+		}
+		line = append(line, nl...)
+
+		_, e = bw.Write(line)
+		if e != nil {
+			return
+		}
+	}
+
+	_, e = bw.WriteString("</" + xmlName(root) + ">\n")
+	if e != nil {
+		return
+	}
+
+	return bw.Flush()
+}
+
+//
+// xmlName convert `s` into valid XML name by replacing invalid characters
+// with "_". If `s` is empty or started with invalid character, it will be
+// prefixed with "_".
+//
+func xmlName(s string) string {
+	name := strings.Map(func(r rune) rune {
+		if unicode.IsLetter(r) || unicode.IsDigit(r) || r == '_' ||
+			r == '-' || r == '.' || r == ':' {
+			return r
+		}
+		return '_'
+	}, s)
+
+	if name == "" {
+		return "_"
+	}
+
+	r := []rune(name)[0]
+	if !unicode.IsLetter(r) && r != '_' {
+		name = "_" + name
+	}
+
+	return name
+}
@@ -0,0 +1,119 @@
+// Copyright 2017 M. Shulhan <ms@kilabit.info>. All rights reserved.
+// Use of this source code is governed by a BSD-style license that can be found
+// in the LICENSE file.
+
+package tabula_test
+
+import (
+	"bytes"
+	"github.com/shuLhan/tabula"
+	"testing"
+)
+
+func TestXMLWriter(t *testing.T) {
+	ds := tabula.NewDataset(tabula.DatasetModeRows, datasetTypes,
+		[]string{"id", "score", "first name"})
+
+	rows := []tabula.Row{{
+		tabula.NewRecordInt(1),
+		tabula.NewRecordReal(0.5),
+		tabula.NewRecordString(`"a" & <b>`),
+	}, {
+		tabula.NewRecordInt(2),
+		tabula.NewRecordNull(tabula.TReal),
+		tabula.NewRecordNull(tabula.TString),
+	}}
+	for x := range rows {
+		ds.PushRow(&rows[x])
+	}
+
+	fields := []tabula.XMLField{
+		{Name: "id", Path: "@id"},
+	}
+
+	exp := `<?xml version="1.0" encoding="UTF-8"?>
+<data>
+  <item id="1">
+    <score>0.5</score>
+    <first_name>&#34;a&#34; &amp; &lt;b&gt;</first_name>
+  </item>
+  <item id="2"/>
+</data>
+`
+
+	writer := &tabula.XMLWriter{
+		Root:   "data",
+		Row:    "item",
+		Fields: fields,
+		Indent: "  ",
+	}
+
+	var out bytes.Buffer
+
+	e := writer.Write(&out, ds)
+	if e != nil {
+		t.Fatal(e)
+	}
+
+	assert(t, exp, out.String(), true)
+
+	fields = []tabula.XMLField{
+		{Name: "id", Type: tabula.TInteger, Path: "@id"},
+		{Name: "score", Type: tabula.TReal},
+		{Name: "first name", Type: tabula.TString, Path: "first_name"},
+	}
+
+	got := tabula.NewDataset(tabula.DatasetModeRows, nil, nil)
+
+	e = tabula.NewXMLReader("/data/item", fields).Read(&out, got)
+	if e != nil {
+		t.Fatal(e)
+	}
+
+	assert(t, ds.Rows.String(), got.Rows.String(), true)
+	assert(t, ds.Rows[1], got.Rows[1], true)
+
+	writer.Fields = []tabula.XMLField{
+		{Name: "id", Path: "a/b"},
+	}
+
+	e = writer.Write(&out, ds)
+
+	assert(t, tabula.ErrInvalidXMLPath, e, true)
+}
+
+func TestXMLWriterNoIndent(t *testing.T) {
+	ds := tabula.NewDataset(tabula.DatasetModeColumns, datasetTypes,
+		datasetNames)
+
+	e := populateWithRows(ds)
+	if e != nil {
+		t.Fatal(e)
+	}
+
+	writer := tabula.NewXMLWriter()
+	writer.Indent = ""
+
+	var out bytes.Buffer
+
+	e = writer.Write(&out, ds)
+	if e != nil {
+		t.Fatal(e)
+	}
+
+	exp := `<?xml version="1.0" encoding="UTF-8"?><dataset><row><int>0</int>`
+
+	assert(t, exp, out.String()[:len(exp)], true)
+
+	got := tabula.NewDataset(tabula.DatasetModeRows, datasetTypes,
+		datasetNames)
+
+	e = tabula.NewXMLReader("row", nil).Read(&out, got)
+	if e != nil {
+		t.Fatal(e)
+	}
+
+	ds.TransposeToRows()
+
+	assert(t, ds.Rows.String(), got.Rows.String(), true)
+}